@@ -2,18 +2,31 @@ package main
 
 import (
 	"context"
+	"crypto/hmac"
 	"database/sql"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"time"
 
+	"ArticleCrawler/internal/pipeline"
 	pb "ArticleCrawler/pkg/proto"
 
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
 )
 
+// hostname, под которым сервис видит машину с e2e: для сервиса в docker compose - host.docker.internal
+var receiverHost = flag.String("receiver-host", "localhost", "host the crawler uses to reach the webhook receiver")
+
 func main() {
+	flag.Parse()
 	fmt.Println("E2E test started")
 
 	const url = "https://example.com"
@@ -59,4 +72,102 @@ func main() {
 
 	duration := time.Since(start)
 	fmt.Printf(" Article processed in: %v\n", duration)
+
+	checkWebhook(client)
+}
+
+// checkWebhook поднимает получатель, который заодно отдает уникальную статью, подписывает его
+// на события этой статьи и ждет подписанную доставку.
+func checkWebhook(client pb.CrawlerClient) {
+	const secret = "e2e-secret"
+	token := fmt.Sprintf("e2e%d", time.Now().UnixNano())
+	events := make(chan pipeline.EventPayload, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, `<html><head><title>Webhook check %s</title></head><body><article><h1>Webhook check %s</h1>
+<p>This page exists only for the end-to-end webhook check, its token is %s.</p>
+<p>The crawler fetches it, stores it as a new article and posts the event to the receiver.</p></article></body></html>`, token, token, token)
+	})
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ts, err := strconv.ParseInt(r.Header.Get(pipeline.WebhookTimestampHeader), 10, 64)
+		if err != nil {
+			http.Error(w, "bad timestamp", http.StatusBadRequest)
+			return
+		}
+		want := pipeline.WebhookSignature(secret, ts, body)
+		if !hmac.Equal([]byte(want), []byte(r.Header.Get(pipeline.WebhookSignatureHeader))) {
+			log.Printf("Webhook signature mismatch")
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		var ev pipeline.EventPayload
+		if err := json.Unmarshal(body, &ev); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		select {
+		case events <- ev:
+		default:
+		}
+	})
+	lis, err := net.Listen("tcp", ":0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := httptest.NewUnstartedServer(mux)
+	srv.Listener.Close()
+	srv.Listener = lis
+	srv.Start()
+	defer srv.Close()
+	base := fmt.Sprintf("http://%s:%d", *receiverHost, lis.Addr().(*net.TCPAddr).Port)
+
+	ctx := context.Background()
+	hook, err := client.CreateWebhook(ctx, &pb.CreateWebhookRequest{
+		Url:        base + "/hook",
+		Secret:     secret,
+		TitleQuery: token,
+		EventTypes: []string{pipeline.EventCreated},
+	})
+	if err != nil {
+		log.Fatalf("CreateWebhook error: %v", err)
+	}
+	defer client.DeleteWebhook(ctx, &pb.DeleteWebhookRequest{Id: hook.Id})
+
+	start := time.Now()
+	log.Printf("Submitting webhook check page %s/article...", base)
+	if _, err := client.SubmitUrl(ctx, &pb.SubmitUrlRequest{Url: base + "/article"}); err != nil {
+		log.Fatalf("SubmitUrl error: %v", err)
+	}
+	select {
+	case ev := <-events:
+		if ev.Article.URL != base+"/article" || ev.Type != pipeline.EventCreated || ev.Sequence == 0 {
+			log.Fatalf("unexpected webhook event: %+v", ev)
+		}
+		log.Printf("Signed webhook received for article %d, event %d", ev.Article.ID, ev.Sequence)
+	case <-time.After(time.Minute):
+		log.Fatalf("no webhook delivery within a minute")
+	}
+
+	// запись о доставке сохраняется после ответа получателя
+	for i := 0; ; i++ {
+		resp, err := client.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{WebhookId: hook.Id, State: "delivered"})
+		if err != nil {
+			log.Fatalf("ListWebhookDeliveries error: %v", err)
+		}
+		if len(resp.Deliveries) > 0 {
+			break
+		}
+		if i == 20 {
+			log.Fatalf("delivery is not marked delivered")
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	fmt.Printf(" Webhook delivered in: %v\n", time.Since(start))
 }
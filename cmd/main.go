@@ -5,31 +5,45 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"ArticleCrawler/internal/config"
 	"ArticleCrawler/internal/db"
 	"ArticleCrawler/internal/limiter"
+	"ArticleCrawler/internal/metrics"
 	"ArticleCrawler/internal/pipeline"
+	"ArticleCrawler/internal/robots"
 	grpcserver "ArticleCrawler/internal/server"
+	"ArticleCrawler/internal/tracing"
 
 	pb "ArticleCrawler/pkg/proto"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// configWatchInterval - как часто проверяется, не изменился ли файл конфига.
+const configWatchInterval = 2 * time.Second
+
 func main() {
 	cfgPath := flag.String("config", "config.yaml", "path to config yaml")
+	flagOverrides := config.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	cfg, err := config.Load(*cfgPath)
+	cfg, err := config.Load(*cfgPath, flagOverrides)
 	if err != nil {
-		log.Fatalf("failed to load config: %v", err)
+		log.Fatalf("failed to load config:\n%v", err)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -43,6 +57,27 @@ func main() {
 		cancel()
 	}()
 
+	if cfg.Tracing.Enabled {
+		shutdown, err := tracing.Setup(ctx, tracing.Options{
+			ServiceName: cfg.Tracing.ServiceName,
+			Exporter:    cfg.Tracing.Exporter,
+			Endpoint:    cfg.Tracing.Endpoint,
+			Insecure:    cfg.Tracing.Insecure,
+			FilePath:    cfg.Tracing.FilePath,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			log.Fatalf("tracing setup: %v", err)
+		}
+		defer func() {
+			sctx, scancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer scancel()
+			if err := shutdown(sctx); err != nil {
+				log.Printf("[main] tracing shutdown: %v", err)
+			}
+		}()
+	}
+
 	repo, err := db.NewRepository(ctx, cfg.Database.URL)
 	if err != nil {
 		log.Fatalf("db connect: %v", err)
@@ -50,42 +85,125 @@ func main() {
 	defer repo.Close()
 
 	dlim := limiter.NewDomainLimiter(cfg.RateLimit.DefaultRPS, cfg.RateLimit.Burst)
+	applyRateLimit(dlim, repo, cfg, nil)
 
 	fetchJobs := make(chan pipeline.FetchJob, 100)
 	fetchResults := make(chan pipeline.FetchResult, 100)
 	parseResults := make(chan pipeline.ParseResult, 100)
 	enrichResults := make(chan pipeline.EnrichResult, 100)
+	metrics.RegisterChannel("fetchJobs", func() int { return len(fetchJobs) }, func() int { return cap(fetchJobs) })
+	metrics.RegisterChannel("fetchResults", func() int { return len(fetchResults) }, func() int { return cap(fetchResults) })
+	metrics.RegisterChannel("parseResults", func() int { return len(parseResults) }, func() int { return cap(parseResults) })
+	metrics.RegisterChannel("enrichResults", func() int { return len(enrichResults) }, func() int { return cap(enrichResults) })
+	metrics.RegisterDBPool(repo.Stat)
+	metrics.RegisterLimiter(func() int { return dlim.StoreStats().Tracked }, func(reason string) int64 {
+		st := dlim.StoreStats()
+		if reason == "idle" {
+			return st.IdleEvictions
+		}
+		return st.CapacityEvictions
+	}, func() int64 { return dlim.StoreStats().BackendErrors })
+	go dlim.Run(ctx)
 
-	hub := pipeline.NewHub()
+	hub := pipeline.NewHub(repo)
+	go hub.Listen(ctx, db.NewListener(cfg.Database.URL))
 
-	f := pipeline.NewFetcher(dlim, cfg.BackoffBase(), cfg.Backoff.MaxRetries)
-	for i := 0; i < cfg.Pipeline.FetchWorkers; i++ {
-		go func() {
-			f.Fetch(ctx, fetchJobs, fetchResults, ctx.Done())
-		}()
+	jobs := pipeline.NewJobQueue(repo, cfg.QueueWorkerID(), cfg.QueueLease(), cfg.QueuePollInterval(), cfg.Queue.BatchSize, cfg.Queue.MaxAttempts)
+	go jobs.Run(ctx, fetchJobs)
+
+	var recrawler *pipeline.Recrawler
+	if cfg.Recrawl.Enabled {
+		tiers := make([]pipeline.RecrawlTier, 0, len(cfg.Recrawl.Schedule))
+		for _, t := range cfg.Recrawl.Schedule {
+			tiers = append(tiers, pipeline.RecrawlTier{
+				MaxAge:   time.Duration(t.MaxAgeHours) * time.Hour,
+				Interval: time.Duration(t.IntervalMinutes) * time.Minute,
+			})
+		}
+		recrawler = pipeline.NewRecrawler(repo, jobs, tiers, cfg.RecrawlPollInterval(), cfg.Recrawl.BatchSize)
+		go recrawler.Run(ctx)
 	}
 
-	parser := pipeline.NewParser()
-	for i := 0; i < cfg.Pipeline.ParseWorkers; i++ {
-		go parser.Parse(ctx, fetchResults, parseResults)
+	var rb *robots.Cache
+	if cfg.Robots.Enabled {
+		rb = robots.NewCache(nil, cfg.Robots.UserAgent, cfg.RobotsCacheTTL(), cfg.Robots.Allowlist)
 	}
 
-	enr := pipeline.NewEnricher()
-	for i := 0; i < cfg.Pipeline.EnrichWorkers; i++ {
-		go enr.Enrich(ctx, parseResults, enrichResults)
+	filter, err := newURLFilter(cfg)
+	if err != nil {
+		log.Fatalf("url_filter: %v", err)
+	}
+	f := pipeline.NewFetcher(dlim, jobs, rb, cfg.Robots.UserAgent, cfg.BackoffBase(), cfg.Backoff.MaxRetries)
+	f.SetFilter(filter)
+	go f.Fetch(ctx, cfg.Pipeline.FetchWorkers, fetchJobs, fetchResults)
+
+	crawls := pipeline.NewCrawls(repo, jobs)
+
+	var sources *pipeline.SourcePoller
+	if cfg.Sources.Enabled {
+		sources = pipeline.NewSourcePoller(repo, jobs, dlim, rb, cfg.Robots.UserAgent, cfg.SourcesPollInterval(), cfg.Sources.BatchSize)
+		go sources.Run(ctx)
 	}
 
-	for i := 0; i < cfg.Pipeline.StoreWorkers; i++ {
-		sw := pipeline.NewStoreWorker(repo, hub)
-		go sw.Store(ctx, enrichResults, ctx.Done())
+	var webhooks *pipeline.WebhookDispatcher
+	if cfg.Webhooks.Enabled {
+		webhooks = pipeline.NewWebhookDispatcher(repo, hub, cfg.WebhooksPollInterval(), cfg.Webhooks.BatchSize, cfg.WebhooksTimeout(), pipeline.WebhookRetry{
+			MaxAttempts:  cfg.Webhooks.MaxAttempts,
+			BaseDelay:    time.Duration(cfg.Webhooks.BackoffBaseSeconds) * time.Second,
+			MaxDelay:     time.Duration(cfg.Webhooks.BackoffMaxSeconds) * time.Second,
+			DisableAfter: cfg.Webhooks.DisableAfter,
+		})
+		go webhooks.Run(ctx)
+	}
+
+	sitemaps := pipeline.NewSitemapImporter(repo, jobs, dlim, rb, cfg.Robots.UserAgent, cfg.QueueWorkerID())
+	go sitemaps.Run(ctx)
+
+	parser := pipeline.NewParser(jobs, cfg.Parser.DefaultMode, cfg.Parser.Domains)
+	parser.SetCrawls(crawls)
+	go parser.Parse(ctx, cfg.Pipeline.ParseWorkers, fetchResults, parseResults)
+
+	enr := pipeline.NewEnricher(jobs)
+	go enr.Enrich(ctx, cfg.Pipeline.EnrichWorkers, parseResults, enrichResults)
+
+	sw := pipeline.NewStoreWorker(repo, hub, jobs, recrawler, cfg.NearDuplicateDistance())
+	go sw.Store(ctx, cfg.Pipeline.StoreWorkers, enrichResults)
+
+	rl := &reloader{
+		path:     *cfgPath,
+		flags:    flagOverrides,
+		repo:     repo,
+		limiter:  dlim,
+		fetcher:  f,
+		parser:   parser,
+		enricher: enr,
+		store:    sw,
+		cfg:      cfg,
 	}
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-hupCh:
+				log.Println("[main] received SIGHUP, reloading config")
+				rl.reload()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go config.Watch(ctx, *cfgPath, configWatchInterval, rl.reload)
 
-	s := grpcserver.NewServer(repo, hub, fetchJobs)
+	s := grpcserver.NewServer(repo, hub, jobs, crawls, dlim)
+	s.SetSources(sources, cfg.SourcesDefaultInterval())
+	s.SetSitemaps(sitemaps)
+	s.SetWebhooks(webhooks)
 	if err := s.Start(ctx, cfg.Server.GRPCAddr); err != nil {
 		log.Fatalf("failed to start grpc: %v", err)
 	}
 
-	go startHTTP(ctx, cfg.Server.HTTPAddr, fetchJobs, cfg.Server.GRPCAddr)
+	go startHTTP(ctx, cfg.Server.HTTPAddr, s)
 
 	log.Println("[main] service started")
 	<-ctx.Done()
@@ -94,57 +212,310 @@ func main() {
 	log.Println("[main] shutdown complete")
 }
 
-func startHTTP(ctx context.Context, addr string, submitCh chan pipeline.FetchJob, grpcAddr string) {
+func startHTTP(ctx context.Context, addr string, s *grpcserver.Server) {
 	r := gin.Default()
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	r.POST("/submit", func(c *gin.Context) {
 		var body struct {
-			Url string `json:"url"`
+			Url   string `json:"url"`
+			Crawl *struct {
+				MaxDepth int32    `json:"max_depth"`
+				MaxPages int32    `json:"max_pages"`
+				Scope    string   `json:"scope"`
+				Include  []string `json:"include"`
+				Exclude  []string `json:"exclude"`
+			} `json:"crawl"`
 		}
 		if err := c.BindJSON(&body); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		select {
-		case submitCh <- pipeline.FetchJob{URL: body.Url}:
-			c.JSON(http.StatusOK, gin.H{"status": "submitted"})
-		default:
-			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "busy"})
+		if body.Url == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "empty url"})
+			return
 		}
+		// трейс начинается здесь (или продолжает traceparent клиента) и сохраняется в задаче
+		reqCtx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		reqCtx, span := otel.Tracer("ArticleCrawler/http").Start(reqCtx, "POST /submit")
+		defer span.End()
+		span.SetAttributes(attribute.String("url.full", body.Url))
+		req := &pb.SubmitUrlRequest{Url: body.Url}
+		if body.Crawl != nil {
+			req.Crawl = &pb.CrawlOptions{
+				MaxDepth: body.Crawl.MaxDepth,
+				MaxPages: body.Crawl.MaxPages,
+				Scope:    body.Crawl.Scope,
+				Include:  body.Crawl.Include,
+				Exclude:  body.Crawl.Exclude,
+			}
+		}
+		resp, err := s.SubmitUrl(reqCtx, req)
+		if err != nil {
+			tracing.RecordError(span, err)
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		span.SetAttributes(attribute.String("job.id", resp.Id))
+		if resp.CrawlId != "" {
+			span.SetAttributes(attribute.String("crawl.id", resp.CrawlId))
+			c.JSON(http.StatusOK, gin.H{"status": "submitted", "id": resp.Id, "crawl_id": resp.CrawlId})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "submitted", "id": resp.Id})
 	})
-	r.GET("/stream", func(c *gin.Context) {
-		conn, err := grpc.Dial(grpcAddr, grpc.WithInsecure())
+	r.GET("/jobs/:id", func(c *gin.Context) {
+		job, err := s.GetJobStatus(c.Request.Context(), &pb.GetJobStatusRequest{Id: c.Param("id")})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	})
+	r.GET("/jobs", func(c *gin.Context) {
+		limit, offset, err := pagination(c)
 		if err != nil {
-			c.String(500, err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		client := pb.NewCrawlerClient(conn)
-		stream, err := client.StreamNewArticles(ctx, &pb.StreamNewArticlesRequest{})
+		resp, err := s.ListJobs(c.Request.Context(), &pb.ListJobsRequest{
+			Limit:  limit,
+			Offset: offset,
+			State:  c.Query("state"),
+		})
 		if err != nil {
-			c.String(500, err.Error())
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
 			return
 		}
-		c.Writer.Header().Set("Content-Type", "text/event-stream")
-		c.Writer.WriteHeader(200)
-		flusher, ok := c.Writer.(gin.ResponseWriter)
-		if !ok {
-			c.String(500, "no flusher")
+		c.JSON(http.StatusOK, resp)
+	})
+	r.GET("/crawls/:id", func(c *gin.Context) {
+		crawl, err := s.GetCrawl(c.Request.Context(), &pb.GetCrawlRequest{Id: c.Param("id")})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
 			return
 		}
-		for {
-			art, err := stream.Recv()
-			if err != nil {
-				return
-			}
-			fmt.Fprintf(c.Writer, "data: %s - %s\n\n", art.Url, art.Title)
-			flusher.Flush()
+		c.JSON(http.StatusOK, crawl)
+	})
+	r.GET("/crawls", func(c *gin.Context) {
+		limit, offset, err := pagination(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		resp, err := s.ListCrawls(c.Request.Context(), &pb.ListCrawlsRequest{
+			Limit:  limit,
+			Offset: offset,
+			State:  c.Query("state"),
+		})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+	r.POST("/crawls/:id/cancel", func(c *gin.Context) {
+		crawl, err := s.CancelCrawl(c.Request.Context(), &pb.CancelCrawlRequest{Id: c.Param("id")})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, crawl)
+	})
+	r.POST("/sources", func(c *gin.Context) {
+		var body struct {
+			Url             string `json:"url"`
+			IntervalSeconds int32  `json:"interval_seconds"`
+		}
+		if err := c.BindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		src, err := s.AddSource(c.Request.Context(), &pb.AddSourceRequest{Url: body.Url, IntervalSeconds: body.IntervalSeconds})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, src)
+	})
+	r.GET("/sources", func(c *gin.Context) {
+		limit, offset, err := pagination(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		resp, err := s.ListSources(c.Request.Context(), &pb.ListSourcesRequest{Limit: limit, Offset: offset})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+	r.DELETE("/sources/:id", func(c *gin.Context) {
+		if _, err := s.RemoveSource(c.Request.Context(), &pb.RemoveSourceRequest{Id: c.Param("id")}); err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "removed"})
+	})
+	r.POST("/sitemaps", func(c *gin.Context) {
+		var body struct {
+			Url     string   `json:"url"`
+			Site    string   `json:"site"`
+			From    string   `json:"from"`
+			To      string   `json:"to"`
+			Include []string `json:"include"`
+			Exclude []string `json:"exclude"`
+			MaxUrls int32    `json:"max_urls"`
+		}
+		if err := c.BindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		imp, err := s.SubmitSitemap(c.Request.Context(), &pb.SubmitSitemapRequest{
+			Url:     body.Url,
+			Site:    body.Site,
+			From:    body.From,
+			To:      body.To,
+			Include: body.Include,
+			Exclude: body.Exclude,
+			MaxUrls: body.MaxUrls,
+		})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusAccepted, imp)
+	})
+	r.GET("/sitemaps/:id", func(c *gin.Context) {
+		imp, err := s.GetSitemapImport(c.Request.Context(), &pb.GetSitemapImportRequest{Id: c.Param("id")})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, imp)
+	})
+	r.POST("/webhooks", func(c *gin.Context) {
+		var body struct {
+			Url         string   `json:"url"`
+			Secret      string   `json:"secret"`
+			Domains     []string `json:"domains"`
+			Language    string   `json:"language"`
+			TitleQuery  string   `json:"title_query"`
+			TitleRegex  string   `json:"title_regex"`
+			MinReadTime int32    `json:"min_read_time"`
+			SourceId    string   `json:"source_id"`
+			EventTypes  []string `json:"event_types"`
+		}
+		if err := c.BindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		w, err := s.CreateWebhook(c.Request.Context(), &pb.CreateWebhookRequest{
+			Url:         body.Url,
+			Secret:      body.Secret,
+			Domains:     body.Domains,
+			Language:    body.Language,
+			TitleQuery:  body.TitleQuery,
+			TitleRegex:  body.TitleRegex,
+			MinReadTime: body.MinReadTime,
+			SourceId:    body.SourceId,
+			EventTypes:  body.EventTypes,
+		})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, w)
+	})
+	r.GET("/webhooks", func(c *gin.Context) {
+		limit, offset, err := pagination(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		resp, err := s.ListWebhooks(c.Request.Context(), &pb.ListWebhooksRequest{Limit: limit, Offset: offset})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+	r.DELETE("/webhooks/:id", func(c *gin.Context) {
+		if _, err := s.DeleteWebhook(c.Request.Context(), &pb.DeleteWebhookRequest{Id: c.Param("id")}); err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "removed"})
+	})
+	r.POST("/webhooks/:id/enable", func(c *gin.Context) {
+		w, err := s.EnableWebhook(c.Request.Context(), &pb.EnableWebhookRequest{Id: c.Param("id")})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, w)
+	})
+	r.GET("/webhooks/:id/deliveries", func(c *gin.Context) {
+		limit, offset, err := pagination(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		resp, err := s.ListWebhookDeliveries(c.Request.Context(), &pb.ListWebhookDeliveriesRequest{
+			WebhookId: c.Param("id"),
+			State:     c.Query("state"),
+			Limit:     limit,
+			Offset:    offset,
+		})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+	r.GET("/limits", func(c *gin.Context) {
+		resp, err := s.ListDomainLimits(c.Request.Context(), &pb.ListDomainLimitsRequest{Domain: c.Query("domain")})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+	r.GET("/search", func(c *gin.Context) {
+		limit, offset, err := pagination(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
+		resp, err := s.SearchArticles(c.Request.Context(), &pb.SearchArticlesRequest{
+			Query:    c.Query("q"),
+			Language: c.Query("language"),
+			Domains:  c.QueryArray("domain"),
+			From:     c.Query("from"),
+			To:       c.Query("to"),
+			Limit:    limit,
+			Offset:   offset,
+		})
+		if err != nil {
+			c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+	r.GET("/stream", func(c *gin.Context) {
+		serveSSE(c, s)
+	})
+	r.GET("/ws", func(c *gin.Context) {
+		serveWS(c, s)
 	})
 	srv := &http.Server{
 		Addr:    addr,
 		Handler: r,
+		// запросы наследуют ctx сервиса: при остановке /stream и /ws закрываются сами
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -156,3 +527,42 @@ func startHTTP(ctx context.Context, addr string, submitCh chan pipeline.FetchJob
 	defer cancel()
 	srv.Shutdown(ctxSh)
 }
+
+// pagination разбирает limit и offset из запроса; без параметров - первые 20 записей.
+func pagination(c *gin.Context) (int32, int32, error) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid limit %q", c.Query("limit"))
+	}
+	offset, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid offset %q", c.Query("offset"))
+	}
+	return int32(limit), int32(offset), nil
+}
+
+// httpStatus переводит gRPC-код ошибки обработчика в HTTP-статус.
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.FailedPrecondition, codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorMessage - текст ошибки без префикса "rpc error: code = ...".
+func errorMessage(err error) string {
+	if st, ok := status.FromError(err); ok {
+		return st.Message()
+	}
+	return err.Error()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"log"
+	"sync"
+
+	"ArticleCrawler/internal/config"
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/limiter"
+	"ArticleCrawler/internal/pipeline"
+	"ArticleCrawler/internal/urlfilter"
+)
+
+// reloader перечитывает конфиг по SIGHUP или при изменении файла и применяет то,
+// что можно поменять на ходу: лимиты доменов, backoff, число воркеров, режимы парсера и фильтр URL.
+// Остальные изменения только логируются как требующие перезапуска, и так при каждой
+// перезагрузке, пока сервис не перезапустят.
+type reloader struct {
+	path  string
+	flags *config.Flags
+	repo  *db.Repository
+
+	limiter  *limiter.DomainLimiter
+	fetcher  *pipeline.Fetcher
+	parser   *pipeline.Parser
+	enricher *pipeline.Enricher
+	store    *pipeline.StoreWorker
+
+	mu  sync.Mutex
+	cfg *config.Config
+}
+
+func (r *reloader) reload() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next, err := config.Load(r.path, r.flags)
+	if err != nil {
+		log.Printf("[config] reload of %s rejected, keeping current config:\n%v", r.path, err)
+		return
+	}
+	changes := config.Diff(r.cfg, next)
+	if len(changes) == 0 {
+		log.Printf("[config] reloaded %s: no changes", r.path)
+		return
+	}
+	log.Printf("[config] reloaded %s: %d change(s)", r.path, len(changes))
+	for _, c := range changes {
+		if c.Live() {
+			log.Printf("[config]   %s", c)
+		} else {
+			log.Printf("[config]   %s (requires restart, not applied)", c)
+		}
+	}
+	filter, err := newURLFilter(next)
+	if err != nil {
+		// Load уже проверил шаблоны, сюда попасть не должны
+		log.Printf("[config] url_filter: %v", err)
+		return
+	}
+	applyRateLimit(r.limiter, r.repo, next, r.cfg)
+	r.fetcher.SetBackoff(next.BackoffBase(), next.Backoff.MaxRetries)
+	r.fetcher.SetFilter(filter)
+	r.fetcher.Resize(next.Pipeline.FetchWorkers)
+	r.parser.SetModes(next.Parser.DefaultMode, next.Parser.Domains)
+	r.parser.Resize(next.Pipeline.ParseWorkers)
+	r.enricher.Resize(next.Pipeline.EnrichWorkers)
+	r.store.Resize(next.Pipeline.StoreWorkers)
+	r.cfg = config.Applied(r.cfg, next)
+}
+
+// applyRateLimit настраивает лимитер по cfg. prev - предыдущий конфиг или nil при запуске:
+// хранилище ведер пересоздается, только если его настройки изменились.
+func applyRateLimit(dlim *limiter.DomainLimiter, repo *db.Repository, cfg, prev *config.Config) {
+	rl := cfg.RateLimit
+	dlim.SetDefault(rl.DefaultRPS, rl.Burst)
+	overrides := make(map[string]limiter.Limit, len(rl.Domains))
+	for d, l := range rl.Domains {
+		overrides[d] = limiter.Limit{RPS: l.RPS, Burst: l.Burst}
+	}
+	dlim.SetOverrides(overrides)
+	dlim.SetEviction(rl.MaxDomains, cfg.RateLimitIdleTTL())
+	dlim.SetAdaptive(limiter.Adaptive{
+		Enabled:       rl.Adaptive.Enabled,
+		MinRPS:        rl.Adaptive.MinRPS,
+		QuietPeriod:   cfg.RateLimitQuietPeriod(),
+		LatencyFactor: rl.Adaptive.LatencyFactor,
+	})
+	if prev != nil && prev.RateLimit.Backend == rl.Backend && prev.RateLimit.FailOpen == rl.FailOpen {
+		return
+	}
+	if rl.Backend == "postgres" {
+		dlim.SetBackend(limiter.NewPostgres(repo), rl.FailOpen)
+	} else {
+		dlim.SetBackend(limiter.Memory{}, rl.FailOpen)
+	}
+}
+
+func newURLFilter(cfg *config.Config) (*urlfilter.Filter, error) {
+	return urlfilter.New(cfg.URLFilter.AllowDomains, cfg.URLFilter.DenyDomains, cfg.URLFilter.DenyPatterns)
+}
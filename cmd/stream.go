@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	grpcserver "ArticleCrawler/internal/server"
+
+	pb "ArticleCrawler/pkg/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// sseKeepAlive - как часто в SSE уходит комментарий-пинг, чтобы прокси не закрывали тихое соединение
+	sseKeepAlive = 15 * time.Second
+	// wsPingInterval - как часто WebSocket-клиенту уходит ping; ответный pong должен прийти за wsPongWait
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsWriteWait    = 10 * time.Second
+)
+
+// API без авторизации по cookie, поэтому подключение к /ws со страниц других доменов
+// (дашборды) разрешено.
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// streamRequest собирает фильтры StreamNewArticles из параметров запроса /stream и /ws.
+// Повторное подключение продолжает с Last-Event-ID (его сам присылает EventSource) или с since.
+func streamRequest(c *gin.Context) (*pb.StreamNewArticlesRequest, error) {
+	var minReadTime int64
+	if v := c.Query("min_read_time"); v != "" {
+		var err error
+		if minReadTime, err = strconv.ParseInt(v, 10, 32); err != nil {
+			return nil, fmt.Errorf("invalid min_read_time %q", v)
+		}
+	}
+	var fields []string
+	for _, f := range c.QueryArray("fields") {
+		fields = append(fields, strings.Split(f, ",")...)
+	}
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("since")
+	}
+	var since int64
+	if lastID != "" {
+		var err error
+		if since, err = strconv.ParseInt(lastID, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid event id %q", lastID)
+		}
+	}
+	return &pb.StreamNewArticlesRequest{
+		Domains:       c.QueryArray("domain"),
+		Language:      c.Query("language"),
+		TitleQuery:    c.Query("title"),
+		TitleRegex:    c.Query("title_regex"),
+		MinReadTime:   int32(minReadTime),
+		SourceId:      c.Query("source_id"),
+		Fields:        fields,
+		SinceSequence: since,
+	}, nil
+}
+
+// openStream подписывает HTTP-клиента на хаб. При ошибке ответ уже записан.
+func openStream(c *gin.Context, s *grpcserver.Server, kind string) (*grpcserver.ArticleStream, string, bool) {
+	req, err := streamRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, "", false
+	}
+	id := fmt.Sprintf("%s-%d", kind, time.Now().UnixNano())
+	st, err := s.OpenStream(c.Request.Context(), id, req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": errorMessage(err)})
+		return nil, "", false
+	}
+	return st, id, true
+}
+
+type streamMessage struct {
+	seq       int64
+	eventType string
+	data      []byte
+}
+
+// pumpStream читает события подписки в канал, чтобы обработчик мог одновременно слать пинги.
+// Канал закрывается, когда подписка закончилась или отменен ctx.
+func pumpStream(ctx context.Context, st *grpcserver.ArticleStream, id string) <-chan streamMessage {
+	out := make(chan streamMessage)
+	go func() {
+		defer close(out)
+		for {
+			ev, data, err := st.Next(ctx)
+			if ctx.Err() != nil || (ev == nil && err == nil) {
+				return
+			}
+			if err != nil {
+				// клиент переподключится с номером последнего полученного события
+				log.Printf("[http stream] %s: %v", id, err)
+				return
+			}
+			select {
+			case out <- streamMessage{seq: ev.Seq, eventType: ev.Type, data: data}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// serveSSE отдает события в формате Server-Sent Events: id - sequence события, event - его тип,
+// data - JSON события.
+func serveSSE(c *gin.Context, s *grpcserver.Server) {
+	st, id, ok := openStream(c, s, "sse")
+	if !ok {
+		return
+	}
+	defer st.Close()
+	ctx := c.Request.Context()
+	events := pumpStream(ctx, st, id)
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.WriteHeader(http.StatusOK)
+	c.Writer.Flush()
+	ping := time.NewTicker(sseKeepAlive)
+	defer ping.Stop()
+	for {
+		select {
+		case m, ok := <-events:
+			if !ok {
+				return
+			}
+			if m.seq != 0 {
+				fmt.Fprintf(c.Writer, "id: %d\n", m.seq)
+			}
+			fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", m.eventType, m.data)
+		case <-ping.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		case <-ctx.Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// serveWS отдает те же события, что /stream, текстовыми JSON-сообщениями WebSocket.
+// Сообщения клиента не нужны: чтение только отвечает на ping/close и замечает отключение.
+func serveWS(c *gin.Context, s *grpcserver.Server) {
+	st, id, ok := openStream(c, s, "ws")
+	if !ok {
+		return
+	}
+	defer st.Close()
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade уже ответил клиенту ошибкой
+		log.Printf("[ws] %s: upgrade: %v", id, err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	conn.SetReadLimit(4096)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	events := pumpStream(ctx, st, id)
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case m, ok := <-events:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(wsWriteWait))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.TextMessage, m.data); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
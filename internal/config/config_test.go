@@ -0,0 +1,149 @@
+package config
+
+import (
+    "flag"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+const baseYAML = `
+database:
+  url: postgres://crawler@localhost/crawler
+pipeline:
+  fetch_workers: 3
+server:
+  http_addr: ":9000"
+`
+
+// load пишет yaml во временный файл и вызывает Load с переменными env и флагами args.
+func load(t *testing.T, yaml string, env map[string]string, args ...string) (*Config, error) {
+    t.Helper()
+    path := filepath.Join(t.TempDir(), "config.yaml")
+    if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    for k, v := range env {
+        t.Setenv(k, v)
+    }
+    fs := flag.NewFlagSet("test", flag.ContinueOnError)
+    flags := BindFlags(fs)
+    if err := fs.Parse(args); err != nil {
+        t.Fatal(err)
+    }
+    return Load(path, flags)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+    cases := []struct {
+        name     string
+        env      map[string]string
+        args     []string
+        workers  int
+        httpAddr string
+    }{
+        {"file", nil, nil, 3, ":9000"},
+        {"env over file", map[string]string{"CRAWLER_PIPELINE_FETCH_WORKERS": "5"}, nil, 5, ":9000"},
+        {"flag over file", nil, []string{"-pipeline.fetch_workers=7"}, 7, ":9000"},
+        {"flag over env", map[string]string{"CRAWLER_PIPELINE_FETCH_WORKERS": "5", "CRAWLER_SERVER_HTTP_ADDR": ":9100"},
+            []string{"-pipeline.fetch_workers", "7"}, 7, ":9100"},
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            cfg, err := load(t, baseYAML, tc.env, tc.args...)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if cfg.Pipeline.FetchWorkers != tc.workers || cfg.Server.HTTPAddr != tc.httpAddr {
+                t.Errorf("fetch_workers %d, http_addr %q; want %d, %q", cfg.Pipeline.FetchWorkers, cfg.Server.HTTPAddr, tc.workers, tc.httpAddr)
+            }
+            // поля, не заданные нигде, берутся из Default
+            if cfg.Pipeline.ParseWorkers != Default().Pipeline.ParseWorkers {
+                t.Errorf("parse_workers %d, want default", cfg.Pipeline.ParseWorkers)
+            }
+        })
+    }
+}
+
+func TestLoadStructuredOverrides(t *testing.T) {
+    cfg, err := load(t, baseYAML, map[string]string{
+        "CRAWLER_URL_FILTER_DENY_DOMAINS": "[a.com, b.com]",
+        "CRAWLER_PARSER_DOMAINS":          "{example.com: paragraphs}",
+        "CRAWLER_RATE_LIMIT_FAIL_OPEN":    "false",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := strings.Join(cfg.URLFilter.DenyDomains, ","); got != "a.com,b.com" {
+        t.Errorf("deny_domains %q", got)
+    }
+    if cfg.Parser.Domains["example.com"] != "paragraphs" {
+        t.Errorf("parser.domains %v", cfg.Parser.Domains)
+    }
+    if cfg.RateLimit.FailOpen {
+        t.Error("rate_limit.fail_open is still true")
+    }
+}
+
+func TestLoadOverrideErrors(t *testing.T) {
+    cases := []struct {
+        name string
+        env  map[string]string
+        args []string
+        want []string
+    }{
+        {"malformed env", map[string]string{"CRAWLER_PIPELINE_FETCH_WORKERS": "many"}, nil,
+            []string{"CRAWLER_PIPELINE_FETCH_WORKERS", `"many"`}},
+        {"unknown env", map[string]string{"CRAWLER_PIPELINE_FETCH_WORKER": "5"}, nil,
+            []string{"CRAWLER_PIPELINE_FETCH_WORKER: unknown setting"}},
+        {"malformed flag", nil, []string{"-rate_limit.burst=lots"},
+            []string{"-rate_limit.burst", `"lots"`}},
+        {"all sources reported", map[string]string{"CRAWLER_QUEUE_BATCH_SIZE": "x"}, []string{"-backoff.max_retries=y"},
+            []string{"CRAWLER_QUEUE_BATCH_SIZE", "-backoff.max_retries"}},
+        {"override fails validation", map[string]string{"CRAWLER_PIPELINE_FETCH_WORKERS": "0"}, nil,
+            []string{"pipeline.fetch_workers must be positive"}},
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            _, err := load(t, baseYAML, tc.env, tc.args...)
+            if err == nil {
+                t.Fatal("Load succeeded")
+            }
+            for _, w := range tc.want {
+                if !strings.Contains(err.Error(), w) {
+                    t.Errorf("error %q does not mention %s", err, w)
+                }
+            }
+        })
+    }
+}
+
+func TestValidate(t *testing.T) {
+    cases := []struct {
+        name   string
+        modify func(c *Config)
+        want   string
+    }{
+        {"valid", func(c *Config) {}, ""},
+        {"no database url", func(c *Config) { c.Database.URL = "" }, "database.url is required"},
+        {"bad address", func(c *Config) { c.Server.GRPCAddr = "localhost" }, "server.grpc_addr"},
+        {"unknown backend", func(c *Config) { c.RateLimit.Backend = "redis" }, "rate_limit.backend"},
+        {"max_distance beyond bands", func(c *Config) { c.Dedup.MaxDistance = 4 }, "dedup.max_distance must be between 0 and 3"},
+        {"bad parser mode", func(c *Config) { c.Parser.Domains = map[string]string{"a.com": "fast"} }, "parser.domains[a.com]"},
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            c := Default()
+            c.Database.URL = "postgres://localhost/crawler"
+            tc.modify(c)
+            err := c.Validate()
+            switch {
+            case tc.want == "" && err != nil:
+                t.Fatalf("unexpected error: %v", err)
+            case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+                t.Fatalf("error %v, want %q", err, tc.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+    "flag"
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+)
+
+// EnvPrefix - префикс переменных окружения: поле pipeline.fetch_workers задается
+// через CRAWLER_PIPELINE_FETCH_WORKERS.
+const EnvPrefix = "CRAWLER_"
+
+// field - лист конфига: путь из yaml-тегов через точку и само поле.
+type field struct {
+    path  string
+    value reflect.Value
+}
+
+func fields(cfg *Config) []field {
+    var out []field
+    walk(reflect.ValueOf(cfg).Elem(), "", &out)
+    return out
+}
+
+func walk(v reflect.Value, prefix string, out *[]field) {
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+        if prefix != "" {
+            name = prefix + "." + name
+        }
+        if v.Field(i).Kind() == reflect.Struct {
+            walk(v.Field(i), name, out)
+            continue
+        }
+        *out = append(*out, field{path: name, value: v.Field(i)})
+    }
+}
+
+func envName(path string) string {
+    return EnvPrefix + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+// set присваивает полю строковое значение. Строки берутся как есть, остальное разбирается
+// как YAML: числа, true/false, списки "[a.com, b.com]", словари "{example.com: paragraphs}".
+func (f field) set(raw string) error {
+    if f.value.Kind() == reflect.String {
+        f.value.SetString(raw)
+        return nil
+    }
+    ptr := reflect.New(f.value.Type())
+    if errs := decodeStrict([]byte(raw), ptr.Interface()); len(errs) > 0 {
+        return fmt.Errorf("cannot parse %q as %s: %v", raw, f.value.Type(), errs[0])
+    }
+    f.value.Set(ptr.Elem())
+    return nil
+}
+
+// applyEnv применяет переменные CRAWLER_* из environ. Переменная с префиксом,
+// которой не соответствует ни одно поле, считается ошибкой: скорее всего, это опечатка.
+func applyEnv(cfg *Config, environ []string) []error {
+    vars := make(map[string]string)
+    for _, kv := range environ {
+        k, v, _ := strings.Cut(kv, "=")
+        if strings.HasPrefix(k, EnvPrefix) {
+            vars[k] = v
+        }
+    }
+    var errs []error
+    for _, f := range fields(cfg) {
+        name := envName(f.path)
+        raw, ok := vars[name]
+        if !ok {
+            continue
+        }
+        delete(vars, name)
+        if err := f.set(raw); err != nil {
+            errs = append(errs, fmt.Errorf("%s: %w", name, err))
+        }
+    }
+    unknown := make([]string, 0, len(vars))
+    for k := range vars {
+        unknown = append(unknown, k)
+    }
+    sort.Strings(unknown)
+    for _, k := range unknown {
+        errs = append(errs, fmt.Errorf("%s: unknown setting", k))
+    }
+    return errs
+}
+
+// Flags - переопределения из командной строки, по флагу на каждое поле: -pipeline.fetch_workers=8.
+type Flags struct {
+    values map[string]string
+}
+
+// BindFlags регистрирует флаги всех полей конфига в fs. Значения разбираются в Load,
+// чтобы ошибки флагов попали в общий список.
+func BindFlags(fs *flag.FlagSet) *Flags {
+    f := &Flags{values: make(map[string]string)}
+    for _, fl := range fields(Default()) {
+        path := fl.path
+        fs.Func(path, fmt.Sprintf("overrides %s (env %s)", path, envName(path)), func(v string) error {
+            f.values[path] = v
+            return nil
+        })
+    }
+    return f
+}
+
+func (f *Flags) apply(cfg *Config) []error {
+    var errs []error
+    for _, fl := range fields(cfg) {
+        raw, ok := f.values[fl.path]
+        if !ok {
+            continue
+        }
+        if err := fl.set(raw); err != nil {
+            errs = append(errs, fmt.Errorf("-%s: %w", fl.path, err))
+        }
+    }
+    return errs
+}
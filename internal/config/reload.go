@@ -0,0 +1,91 @@
+package config
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "strings"
+    "time"
+)
+
+// Change - поле, значение которого отличается между двумя конфигами.
+type Change struct {
+    Path string
+    Old  string
+    New  string
+}
+
+func (c Change) String() string {
+    return fmt.Sprintf("%s: %s -> %s", c.Path, c.Old, c.New)
+}
+
+// Diff перечисляет поля, измененные между prev и next, в порядке объявления.
+func Diff(prev, next *Config) []Change {
+    of, nf := fields(prev), fields(next)
+    var changes []Change
+    for i := range of {
+        o, n := fmt.Sprintf("%v", of[i].value.Interface()), fmt.Sprintf("%v", nf[i].value.Interface())
+        if o != n {
+            changes = append(changes, Change{Path: of[i].path, Old: o, New: n})
+        }
+    }
+    return changes
+}
+
+// liveSections можно применить без перезапуска, остальные изменения требуют рестарта.
+var liveSections = []string{"pipeline.", "rate_limit.", "backoff.", "parser.", "url_filter."}
+
+// Live - можно ли применить изменение на работающем сервисе.
+func (c Change) Live() bool {
+    for _, s := range liveSections {
+        if strings.HasPrefix(c.Path, s) {
+            return true
+        }
+    }
+    return false
+}
+
+// Applied возвращает конфиг, который действует после перезагрузки: поля, применяемые на ходу,
+// берутся из next, остальные остаются из prev до перезапуска. Поэтому следующий Diff снова
+// покажет изменения, требующие перезапуска.
+func Applied(prev, next *Config) *Config {
+    cfg := *prev
+    pf, nf := fields(&cfg), fields(next)
+    for i := range pf {
+        if (Change{Path: pf[i].path}).Live() {
+            pf[i].value.Set(nf[i].value)
+        }
+    }
+    return &cfg
+}
+
+// Watch вызывает onChange, когда у файла меняется время изменения или размер.
+// Файл проверяется раз в interval, пока ctx не отменен.
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) {
+    if path == "" {
+        path = "config.yaml"
+    }
+    stamp := func() (time.Time, int64) {
+        fi, err := os.Stat(path)
+        if err != nil {
+            return time.Time{}, -1
+        }
+        return fi.ModTime(), fi.Size()
+    }
+    mtime, size := stamp()
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ticker.C:
+        case <-ctx.Done():
+            return
+        }
+        m, s := stamp()
+        if s < 0 || (m.Equal(mtime) && s == size) {
+            continue
+        }
+        mtime, size = m, s
+        onChange()
+    }
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestAppliedKeepsRestartOnlyChangesPending(t *testing.T) {
+    prev := Default()
+    next := Default()
+    next.Server.GRPCAddr = ":6000"
+    next.Pipeline.FetchWorkers = 16
+    next.RateLimit.Domains = map[string]DomainRateConfig{"example.com": {RPS: 1, Burst: 1}}
+
+    applied := Applied(prev, next)
+    if applied.Pipeline.FetchWorkers != 16 || len(applied.RateLimit.Domains) != 1 {
+        t.Errorf("live changes are not applied: %+v %+v", applied.Pipeline, applied.RateLimit.Domains)
+    }
+    if applied.Server.GRPCAddr != prev.Server.GRPCAddr {
+        t.Errorf("server.grpc_addr applied without restart: %s", applied.Server.GRPCAddr)
+    }
+    if prev.Pipeline.FetchWorkers != Default().Pipeline.FetchWorkers {
+        t.Error("Applied modified prev")
+    }
+    // та же перезагрузка еще раз: изменение, требующее перезапуска, снова в списке
+    changes := Diff(applied, next)
+    if len(changes) != 1 || changes[0].Path != "server.grpc_addr" || changes[0].Live() {
+        t.Fatalf("second reload reports %v, want only server.grpc_addr", changes)
+    }
+}
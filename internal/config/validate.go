@@ -0,0 +1,141 @@
+package config
+
+import (
+    "errors"
+    "fmt"
+    "net"
+    "sort"
+    "strconv"
+
+    "ArticleCrawler/internal/simhash"
+    "ArticleCrawler/internal/urlfilter"
+)
+
+// Validate проверяет конфиг целиком и возвращает все найденные ошибки разом (errors.Join).
+func (c *Config) Validate() error {
+    var errs []error
+    check := func(ok bool, format string, args ...interface{}) {
+        if !ok {
+            errs = append(errs, fmt.Errorf(format, args...))
+        }
+    }
+    addr := func(name, v string) {
+        if err := validAddr(v); err != nil {
+            errs = append(errs, fmt.Errorf("%s: %w", name, err))
+        }
+    }
+
+    addr("server.grpc_addr", c.Server.GRPCAddr)
+    addr("server.http_addr", c.Server.HTTPAddr)
+
+    check(c.Pipeline.FetchWorkers > 0, "pipeline.fetch_workers must be positive, got %d", c.Pipeline.FetchWorkers)
+    check(c.Pipeline.ParseWorkers > 0, "pipeline.parse_workers must be positive, got %d", c.Pipeline.ParseWorkers)
+    check(c.Pipeline.EnrichWorkers > 0, "pipeline.enrich_workers must be positive, got %d", c.Pipeline.EnrichWorkers)
+    check(c.Pipeline.StoreWorkers > 0, "pipeline.store_workers must be positive, got %d", c.Pipeline.StoreWorkers)
+
+    rl := c.RateLimit
+    check(rl.DefaultRPS > 0, "rate_limit.default_rps must be positive, got %d", rl.DefaultRPS)
+    check(rl.Burst > 0, "rate_limit.burst must be positive, got %d", rl.Burst)
+    check(rl.Backend == "memory" || rl.Backend == "postgres", "rate_limit.backend must be memory or postgres, got %q", rl.Backend)
+    check(rl.MaxDomains >= 0, "rate_limit.max_domains must not be negative, got %d", rl.MaxDomains)
+    check(rl.IdleTTLSeconds >= 0, "rate_limit.idle_ttl_seconds must not be negative, got %d", rl.IdleTTLSeconds)
+    for _, d := range sortedKeys(rl.Domains) {
+        l := rl.Domains[d]
+        check(d != "", "rate_limit.domains: empty domain")
+        check(l.RPS > 0, "rate_limit.domains[%s].rps must be positive, got %g", d, l.RPS)
+        check(l.Burst >= 0, "rate_limit.domains[%s].burst must not be negative, got %d", d, l.Burst)
+    }
+    check(rl.Adaptive.MinRPS >= 0, "rate_limit.adaptive.min_rps must not be negative, got %g", rl.Adaptive.MinRPS)
+    check(rl.Adaptive.QuietPeriodSeconds >= 0, "rate_limit.adaptive.quiet_period_seconds must not be negative, got %d", rl.Adaptive.QuietPeriodSeconds)
+    check(rl.Adaptive.LatencyFactor == 0 || rl.Adaptive.LatencyFactor > 1, "rate_limit.adaptive.latency_factor must be greater than 1, got %g", rl.Adaptive.LatencyFactor)
+
+    check(c.Database.URL != "", "database.url is required")
+
+    check(c.Backoff.BaseSeconds >= 0, "backoff.base_seconds must not be negative, got %d", c.Backoff.BaseSeconds)
+    check(c.Backoff.MaxRetries > 0, "backoff.max_retries must be positive, got %d", c.Backoff.MaxRetries)
+
+    check(c.Queue.PollIntervalMs > 0, "queue.poll_interval_ms must be positive, got %d", c.Queue.PollIntervalMs)
+    check(c.Queue.LeaseSeconds > 0, "queue.lease_seconds must be positive, got %d", c.Queue.LeaseSeconds)
+    check(c.Queue.BatchSize > 0, "queue.batch_size must be positive, got %d", c.Queue.BatchSize)
+    check(c.Queue.MaxAttempts > 0, "queue.max_attempts must be positive, got %d", c.Queue.MaxAttempts)
+
+    check(c.Robots.CacheTTLSeconds >= 0, "robots.cache_ttl_seconds must not be negative, got %d", c.Robots.CacheTTLSeconds)
+
+    check(validParserMode(c.Parser.DefaultMode), "parser.default_mode must be readability or paragraphs, got %q", c.Parser.DefaultMode)
+    for _, d := range sortedKeys(c.Parser.Domains) {
+        m := c.Parser.Domains[d]
+        check(validParserMode(m), "parser.domains[%s] must be readability or paragraphs, got %q", d, m)
+    }
+
+    if _, err := urlfilter.New(c.URLFilter.AllowDomains, c.URLFilter.DenyDomains, c.URLFilter.DenyPatterns); err != nil {
+        errs = append(errs, fmt.Errorf("url_filter.deny_patterns: %w", err))
+    }
+
+    if c.Recrawl.Enabled {
+        check(c.Recrawl.PollIntervalSeconds > 0, "recrawl.poll_interval_seconds must be positive, got %d", c.Recrawl.PollIntervalSeconds)
+        check(c.Recrawl.BatchSize > 0, "recrawl.batch_size must be positive, got %d", c.Recrawl.BatchSize)
+        check(len(c.Recrawl.Schedule) > 0, "recrawl.schedule must not be empty")
+    }
+    for i, t := range c.Recrawl.Schedule {
+        check(t.MaxAgeHours >= 0, "recrawl.schedule[%d].max_age_hours must not be negative, got %d", i, t.MaxAgeHours)
+        check(t.IntervalMinutes > 0, "recrawl.schedule[%d].interval_minutes must be positive, got %d", i, t.IntervalMinutes)
+    }
+
+    if c.Sources.Enabled {
+        check(c.Sources.PollIntervalSeconds > 0, "sources.poll_interval_seconds must be positive, got %d", c.Sources.PollIntervalSeconds)
+        check(c.Sources.BatchSize > 0, "sources.batch_size must be positive, got %d", c.Sources.BatchSize)
+    }
+    check(c.Sources.DefaultIntervalSeconds >= 60, "sources.default_interval_seconds must be at least 60, got %d", c.Sources.DefaultIntervalSeconds)
+
+    if c.Webhooks.Enabled {
+        check(c.Webhooks.PollIntervalSeconds > 0, "webhooks.poll_interval_seconds must be positive, got %d", c.Webhooks.PollIntervalSeconds)
+        check(c.Webhooks.BatchSize > 0, "webhooks.batch_size must be positive, got %d", c.Webhooks.BatchSize)
+        check(c.Webhooks.TimeoutSeconds > 0 && c.Webhooks.TimeoutSeconds <= 120, "webhooks.timeout_seconds must be between 1 and 120, got %d", c.Webhooks.TimeoutSeconds)
+        check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive, got %d", c.Webhooks.MaxAttempts)
+        check(c.Webhooks.BackoffBaseSeconds > 0, "webhooks.backoff_base_seconds must be positive, got %d", c.Webhooks.BackoffBaseSeconds)
+        check(c.Webhooks.BackoffMaxSeconds >= c.Webhooks.BackoffBaseSeconds, "webhooks.backoff_max_seconds must be at least backoff_base_seconds, got %d", c.Webhooks.BackoffMaxSeconds)
+        check(c.Webhooks.DisableAfter > 0, "webhooks.disable_after must be positive, got %d", c.Webhooks.DisableAfter)
+    }
+
+    check(c.Dedup.MaxDistance >= 0 && c.Dedup.MaxDistance < simhash.Bands, "dedup.max_distance must be between 0 and %d, got %d", simhash.Bands-1, c.Dedup.MaxDistance)
+
+    if c.Tracing.Enabled {
+        switch c.Tracing.Exporter {
+        case "otlp":
+            addr("tracing.endpoint", c.Tracing.Endpoint)
+        case "stdout":
+        case "file":
+            check(c.Tracing.FilePath != "", "tracing.file_path is required for the file exporter")
+        default:
+            errs = append(errs, fmt.Errorf("tracing.exporter must be otlp, stdout or file, got %q", c.Tracing.Exporter))
+        }
+    }
+    check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+
+    return errors.Join(errs...)
+}
+
+// validAddr принимает адреса вида host:port и :port.
+func validAddr(v string) error {
+    _, port, err := net.SplitHostPort(v)
+    if err != nil {
+        return err
+    }
+    if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+        return fmt.Errorf("invalid port %q in %q", port, v)
+    }
+    return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+func validParserMode(m string) bool {
+    return m == "readability" || m == "paragraphs"
+}
@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ArticleCrawler/internal/tracing"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type CrawlState string
+
+const (
+	CrawlRunning  CrawlState = "running"
+	CrawlFinished CrawlState = "finished"
+	CrawlCanceled CrawlState = "canceled"
+)
+
+// Crawl - рекурсивный обход от затравочного URL. PagesQueued - сколько страниц поставлено
+// в очередь (включая затравку), PagesDone и PagesFailed считаются по конечным состояниям задач.
+type Crawl struct {
+	ID          int64
+	SeedURL     string
+	MaxDepth    int
+	MaxPages    int
+	Scope       string
+	Include     []string
+	Exclude     []string
+	State       CrawlState
+	PagesQueued int
+	PagesDone   int
+	PagesFailed int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+const crawlColumns = `c.id, c.seed_url, c.max_depth, c.max_pages, c.scope, c.include_patterns, c.exclude_patterns, c.state,
+  c.pages_queued, c.created_at, c.updated_at, c.finished_at,
+  (SELECT count(*) FROM crawl_jobs j WHERE j.crawl_id = c.id AND j.state IN ('stored', 'duplicate', 'not_modified')),
+  (SELECT count(*) FROM crawl_jobs j WHERE j.crawl_id = c.id AND j.state IN ('failed', 'canceled'))`
+
+func scanCrawl(row interface{ Scan(...interface{}) error }) (*Crawl, error) {
+	var c Crawl
+	err := row.Scan(&c.ID, &c.SeedURL, &c.MaxDepth, &c.MaxPages, &c.Scope, &c.Include, &c.Exclude, &c.State,
+		&c.PagesQueued, &c.CreatedAt, &c.UpdatedAt, &c.FinishedAt, &c.PagesDone, &c.PagesFailed)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// completeCrawlsSQL завершает обходы из $1, у которых не осталось задач в работе.
+const completeCrawlsSQL = `
+UPDATE crawls c SET state = 'finished', finished_at = now(), updated_at = now()
+WHERE c.id = ANY($1) AND c.state = 'running'
+  AND NOT EXISTS (SELECT 1 FROM crawl_jobs j WHERE j.crawl_id = c.id AND j.state IN ('queued', 'fetching', 'parsing'))`
+
+// CreateCrawl сохраняет обход и ставит в очередь задачу для затравки с глубиной 0.
+// Возвращает id обхода и id задачи.
+func (r *Repository) CreateCrawl(ctx context.Context, c *Crawl) (int64, int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+	var crawlID, jobID int64
+	err = tx.QueryRow(ctx, `
+INSERT INTO crawls (seed_url, max_depth, max_pages, scope, include_patterns, exclude_patterns, state, pages_queued)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 1) RETURNING id`,
+		c.SeedURL, c.MaxDepth, c.MaxPages, c.Scope, nonNil(c.Include), nonNil(c.Exclude), CrawlRunning).Scan(&crawlID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO crawl_visited (crawl_id, url) VALUES ($1, $2)", crawlID, c.SeedURL); err != nil {
+		return 0, 0, err
+	}
+	err = tx.QueryRow(ctx, "INSERT INTO crawl_jobs (url, state, crawl_id, depth, trace_parent) VALUES ($1, $2, $3, 0, NULLIF($4, '')) RETURNING id",
+		c.SeedURL, JobQueued, crawlID, tracing.Inject(ctx)).Scan(&jobID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return crawlID, jobID, tx.Commit(ctx)
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// EnqueueCrawlLinks ставит в очередь еще не посещенные обходом urls с глубиной depth, пока
+// обход не упрется в max_pages. Строка обхода блокируется на время транзакции, поэтому
+// параллельные вызовы с разных реплик не превысят лимит и не поставят один URL дважды.
+// running == false - обход уже не идет, ссылки отброшены.
+func (r *Repository) EnqueueCrawlLinks(ctx context.Context, crawlID int64, depth int, urls []string) (int, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback(ctx)
+	var state CrawlState
+	var left int
+	err = tx.QueryRow(ctx, "SELECT state, max_pages - pages_queued FROM crawls WHERE id = $1 FOR UPDATE", crawlID).Scan(&state, &left)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if state != CrawlRunning {
+		return 0, false, nil
+	}
+	if left <= 0 || len(urls) == 0 {
+		return 0, true, nil
+	}
+	rows, err := tx.Query(ctx, `
+INSERT INTO crawl_visited (crawl_id, url)
+SELECT $1, t.url FROM unnest($2::text[]) WITH ORDINALITY AS t(url, n)
+WHERE NOT EXISTS (SELECT 1 FROM crawl_visited v WHERE v.crawl_id = $1 AND v.url = t.url)
+ORDER BY t.n
+LIMIT $3
+ON CONFLICT DO NOTHING
+RETURNING url`, crawlID, urls, left)
+	if err != nil {
+		return 0, false, err
+	}
+	var fresh []string
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			rows.Close()
+			return 0, false, err
+		}
+		fresh = append(fresh, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, false, err
+	}
+	if len(fresh) == 0 {
+		return 0, true, nil
+	}
+	_, err = tx.Exec(ctx, `
+INSERT INTO crawl_jobs (url, state, crawl_id, depth, trace_parent)
+SELECT u, $2, $3, $4, NULLIF($5, '') FROM unnest($1::text[]) AS u`, fresh, JobQueued, crawlID, depth, tracing.Inject(ctx))
+	if err != nil {
+		return 0, false, err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE crawls SET pages_queued = pages_queued + $2, updated_at = now() WHERE id = $1", crawlID, len(fresh)); err != nil {
+		return 0, false, err
+	}
+	return len(fresh), true, tx.Commit(ctx)
+}
+
+func (r *Repository) GetCrawl(ctx context.Context, id int64) (*Crawl, error) {
+	return scanCrawl(r.pool.QueryRow(ctx, "SELECT "+crawlColumns+" FROM crawls c WHERE c.id = $1", id))
+}
+
+func (r *Repository) ListCrawls(ctx context.Context, state CrawlState, limit, offset int32) ([]*Crawl, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+crawlColumns+" FROM crawls c WHERE ($1 = '' OR c.state = $1) ORDER BY c.id DESC LIMIT $2 OFFSET $3", state, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*Crawl
+	for rows.Next() {
+		c, err := scanCrawl(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+// CancelCrawl останавливает идущий обход: задачи, которые еще ждут в очереди, помечаются canceled,
+// а задачи в работе доделываются, но найденные в них ссылки уже не ставятся.
+// Возвращает false, если обход уже завершен или отменен.
+func (r *Repository) CancelCrawl(ctx context.Context, id int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	tag, err := tx.Exec(ctx, "UPDATE crawls SET state = $2, finished_at = now(), updated_at = now() WHERE id = $1 AND state = $3",
+		id, CrawlCanceled, CrawlRunning)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	_, err = tx.Exec(ctx, "UPDATE crawl_jobs SET state = $2, last_error = 'crawl canceled', updated_at = now() WHERE crawl_id = $1 AND state = $3",
+		id, JobCanceled, JobQueued)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
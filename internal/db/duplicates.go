@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ArticleCrawler/internal/simhash"
+)
+
+// LinkNearDuplicates ищет статьи, чей SimHash отличается от hash не больше чем в maxDistance битах,
+// и объединяет их кластеры вместе со статьей id в один. Кластер идентифицируется наименьшим
+// id среди входящих в него статей. Возвращает id кластера и ближайшую похожую статью;
+// если похожих нет, оба значения 0 и текущий кластер статьи не меняется.
+func (r *Repository) LinkNearDuplicates(ctx context.Context, id int64, hash uint64, maxDistance int) (int64, int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+	// кластеры меняются по несколько строк за раз, параллельные слияния сериализуем
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext('article_clusters'))"); err != nil {
+		return 0, 0, err
+	}
+
+	// кандидаты находятся по индексам полос: при расстоянии меньше числа полос (это проверяет
+	// config.Validate) хотя бы одна полоса совпадает целиком. Выражения повторяют индексы из
+	// 008_near_duplicates.sql буквально, иначе Postgres их не сопоставит и просмотрит всю таблицу
+	args := []interface{}{id}
+	conds := make([]string, 0, simhash.Bands)
+	for i := 0; i < simhash.Bands; i++ {
+		args = append(args, simhash.Band(hash, i))
+		band := "(simhash & 65535)"
+		if i > 0 {
+			band = fmt.Sprintf("((simhash >> %d) & 65535)", 16*i)
+		}
+		conds = append(conds, fmt.Sprintf("%s = $%d", band, len(args)))
+	}
+	query := "SELECT id, simhash, coalesce(cluster_id, 0) FROM articles WHERE id <> $1 AND simhash IS NOT NULL AND (" +
+		strings.Join(conds, " OR ") + ")"
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	var matched []int64
+	var nearest int64
+	nearestDist := maxDistance + 1
+	clusterID := id
+	for rows.Next() {
+		var candID, candHash, candCluster int64
+		if err := rows.Scan(&candID, &candHash, &candCluster); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		d := simhash.Distance(hash, uint64(candHash))
+		if d > maxDistance {
+			continue
+		}
+		matched = append(matched, candID)
+		if d < nearestDist {
+			nearest, nearestDist = candID, d
+		}
+		root := candCluster
+		if root == 0 {
+			root = candID
+		}
+		if root < clusterID {
+			clusterID = root
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+	if len(matched) == 0 {
+		return 0, 0, nil
+	}
+
+	var own int64
+	if err := tx.QueryRow(ctx, "SELECT coalesce(cluster_id, 0) FROM articles WHERE id = $1", id).Scan(&own); err != nil {
+		return 0, 0, err
+	}
+	if own != 0 && own < clusterID {
+		clusterID = own
+	}
+	// в кластер переезжают сами найденные статьи и все участники их прежних кластеров
+	ids := append(matched, id)
+	_, err = tx.Exec(ctx, `
+UPDATE articles SET cluster_id = $1
+WHERE id = ANY($2) OR id = $1
+   OR cluster_id IN (SELECT coalesce(cluster_id, id) FROM articles WHERE id = ANY($2))`, clusterID, ids)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, err
+	}
+	return clusterID, nearest, nil
+}
+
+func (r *Repository) ListClusterArticles(ctx context.Context, clusterID int64) ([]*Article, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+articleColumns+" FROM articles WHERE cluster_id=$1 ORDER BY id", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*Article
+	for rows.Next() {
+		a, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// ArticleEvent - запись журнала событий: статья в ее текущем состоянии и номер события.
+type ArticleEvent struct {
+	Seq      int64
+	Type     string
+	Article  *Article
+	LoggedAt time.Time
+}
+
+// AppendArticleEvent записывает событие в журнал, сообщает о нем в канал EventsChannel
+// и возвращает его номер. Уведомление уходит при коммите, то есть после того, как событие видно в журнале.
+//
+// Номера bigserial выдаются в порядке вызова nextval, а не коммита: без блокировки событие N+1
+// могло бы стать видно раньше N, и клиент, продолживший с since_sequence = N+1, потерял бы N.
+// Поэтому записи в журнал идут по одной: блокировка держится до коммита, и номера становятся
+// видны строго по возрастанию. Транзакция короткая - одна вставка.
+func (r *Repository) AppendArticleEvent(ctx context.Context, eventType string, articleID int64) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext('article_events'))"); err != nil {
+		return 0, err
+	}
+	var seq int64
+	err = tx.QueryRow(ctx, `
+WITH e AS (
+  INSERT INTO article_events (event_type, article_id) VALUES ($1, $2) RETURNING seq, event_type, article_id
+)
+SELECT seq, pg_notify($3, json_build_object('seq', seq, 'type', event_type, 'article_id', article_id)::text) FROM e`,
+		eventType, articleID, EventsChannel).Scan(&seq, nil)
+	if err != nil {
+		return 0, err
+	}
+	return seq, tx.Commit(ctx)
+}
+
+// ArticleEventsSince возвращает до limit событий с номером больше seq по возрастанию номера.
+func (r *Repository) ArticleEventsSince(ctx context.Context, seq int64, limit int) ([]*ArticleEvent, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT `+articleColumns+`, e.seq, e.event_type, e.logged_at
+FROM article_events e JOIN articles a ON a.id = e.article_id
+WHERE e.seq > $1
+ORDER BY e.seq
+LIMIT $2`, seq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*ArticleEvent
+	for rows.Next() {
+		var ev ArticleEvent
+		art, err := scanArticle(rows, &ev.Seq, &ev.Type, &ev.LoggedAt)
+		if err != nil {
+			return nil, err
+		}
+		ev.Article = art
+		res = append(res, &ev)
+	}
+	return res, rows.Err()
+}
+
+// LastArticleEventSeq возвращает номер последнего события журнала, 0 - журнал пуст.
+func (r *Repository) LastArticleEventSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.pool.QueryRow(ctx, "SELECT coalesce(max(seq), 0) FROM article_events").Scan(&seq)
+	return seq, err
+}
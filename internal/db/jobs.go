@@ -0,0 +1,283 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ArticleCrawler/internal/tracing"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type JobState string
+
+const (
+	JobQueued      JobState = "queued"
+	JobFetching    JobState = "fetching"
+	JobParsing     JobState = "parsing"
+	JobStored      JobState = "stored"
+	JobDuplicate   JobState = "duplicate"
+	JobNotModified JobState = "not_modified"
+	JobFailed      JobState = "failed"
+	// задача обхода, снятая с очереди вместе с обходом (CancelCrawl)
+	JobCanceled JobState = "canceled"
+)
+
+const (
+	StageFetch  = "fetch"
+	StageParse  = "parse"
+	StageEnrich = "enrich"
+	StageStore  = "store"
+)
+
+const (
+	StageOK          = "ok"
+	StageError       = "error"
+	StageDropped     = "dropped"
+	StageDuplicate   = "duplicate"
+	StageNotModified = "not_modified"
+	// статья сохранена, но почти совпадает с уже известной и попала в ее кластер
+	StageNearDuplicate = "near_duplicate"
+)
+
+type CrawlJob struct {
+	ID             int64
+	URL            string
+	State          JobState
+	Attempts       int
+	LockedBy       string
+	LeaseExpiresAt *time.Time
+	LastError      string
+	ArticleID      int64
+	TraceParent    string // W3C traceparent запроса, который поставил задачу
+	CrawlID        int64  // 0 - задача не относится к рекурсивному обходу
+	Depth          int    // расстояние от затравки обхода в переходах по ссылкам
+	SourceID       int64  // лента, из которой пришла ссылка, 0 - не из ленты
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	// Валидаторы последнего ответа по этому url, заполняются только в ClaimJobs.
+	ETag         string
+	LastModified string
+	ResponseHash string
+}
+
+type JobEvent struct {
+	ID        int64
+	JobID     int64
+	Stage     string
+	Status    string
+	Error     string
+	CreatedAt time.Time
+}
+
+const jobColumns = "id, url, state, attempts, locked_by, lease_expires_at, last_error, article_id, coalesce(trace_parent, ''), coalesce(crawl_id, 0), depth, coalesce(source_id, 0), created_at, updated_at"
+
+func scanJob(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*CrawlJob, error) {
+	var j CrawlJob
+	var lockedBy, lastError *string
+	var articleID *int64
+	dest := []interface{}{&j.ID, &j.URL, &j.State, &j.Attempts, &lockedBy, &j.LeaseExpiresAt, &lastError, &articleID, &j.TraceParent, &j.CrawlID, &j.Depth, &j.SourceID, &j.CreatedAt, &j.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if lockedBy != nil {
+		j.LockedBy = *lockedBy
+	}
+	if lastError != nil {
+		j.LastError = *lastError
+	}
+	if articleID != nil {
+		j.ArticleID = *articleID
+	}
+	return &j, nil
+}
+
+// EnqueueJob сохраняет вместе с задачей контекст трассировки из ctx, чтобы этапы пайплайна
+// попали в тот же трейс, что и запрос на отправку URL.
+func (r *Repository) EnqueueJob(ctx context.Context, url string) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, "INSERT INTO crawl_jobs (url, state, trace_parent) VALUES ($1, $2, NULLIF($3, '')) RETURNING id",
+		url, JobQueued, tracing.Inject(ctx)).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ClaimJobs переводит до limit задач из queued в fetching и закрепляет их за workerID.
+// SKIP LOCKED позволяет нескольким репликам забирать задачи из одной таблицы без двойной выборки.
+func (r *Repository) ClaimJobs(ctx context.Context, workerID string, limit int, lease time.Duration) ([]*CrawlJob, error) {
+	query := `
+WITH claimed AS (
+  UPDATE crawl_jobs SET
+    state = $1,
+    locked_by = $2,
+    lease_expires_at = now() + make_interval(secs => $3),
+    attempts = attempts + 1,
+    updated_at = now()
+  WHERE id IN (
+    SELECT id FROM crawl_jobs
+    WHERE state = $4
+    ORDER BY id
+    LIMIT $5
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING ` + jobColumns + `
+)
+SELECT claimed.*, coalesce(a.etag, ''), coalesce(a.last_modified, ''), coalesce(a.response_hash, '')
+FROM claimed LEFT JOIN articles a ON a.url = claimed.url
+ORDER BY claimed.id`
+	rows, err := r.pool.Query(ctx, query, JobFetching, workerID, lease.Seconds(), JobQueued, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*CrawlJob
+	for rows.Next() {
+		var etag, lastModified, responseHash string
+		j, err := scanJob(rows, &etag, &lastModified, &responseHash)
+		if err != nil {
+			return nil, err
+		}
+		j.ETag, j.LastModified, j.ResponseHash = etag, lastModified, responseHash
+		res = append(res, j)
+	}
+	return res, rows.Err()
+}
+
+// ErrLeaseLost - задача больше не закреплена за воркером: аренда истекла, и задачу забрал
+// RequeueExpiredJobs (возможно, уже другая реплика), или задача снята с очереди.
+var ErrLeaseLost = errors.New("job lease lost")
+
+// AdvanceJob переводит задачу воркера workerID в следующее рабочее состояние и продлевает аренду.
+func (r *Repository) AdvanceJob(ctx context.Context, id int64, workerID string, state JobState, lease time.Duration) error {
+	tag, err := r.pool.Exec(ctx, `
+UPDATE crawl_jobs SET state = $3, lease_expires_at = now() + make_interval(secs => $4), updated_at = now()
+WHERE id = $1 AND locked_by = $2 AND state IN ('fetching', 'parsing')`, id, workerID, state, lease.Seconds())
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// FinishJob переводит задачу воркера workerID в конечное состояние и снимает аренду.
+// Задача, которая уже не закреплена за workerID, не меняется: ErrLeaseLost.
+// articleID == 0 означает, что статья не была сохранена.
+// Если это была последняя незавершенная задача обхода, обход тоже завершается.
+// Статья задачи из ленты запоминает эту ленту, если еще не пришла из другой.
+func (r *Repository) FinishJob(ctx context.Context, id int64, workerID string, state JobState, articleID int64, errText string) error {
+	var crawlID, sourceID int64
+	err := r.pool.QueryRow(ctx, `
+UPDATE crawl_jobs SET
+  state = $2,
+  article_id = NULLIF($3::bigint, 0),
+  last_error = NULLIF($4, ''),
+  locked_by = NULL,
+  lease_expires_at = NULL,
+  updated_at = now()
+WHERE id = $1 AND locked_by = $5 AND state IN ('fetching', 'parsing')
+RETURNING coalesce(crawl_id, 0), coalesce(source_id, 0)`, id, state, articleID, errText, workerID).Scan(&crawlID, &sourceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrLeaseLost
+	}
+	if err != nil {
+		return err
+	}
+	if sourceID != 0 && articleID != 0 {
+		if _, err := r.pool.Exec(ctx, "UPDATE articles SET source_id = $2 WHERE id = $1 AND source_id IS NULL", articleID, sourceID); err != nil {
+			return err
+		}
+	}
+	if crawlID != 0 {
+		_, err = r.pool.Exec(ctx, completeCrawlsSQL, []int64{crawlID})
+	}
+	return err
+}
+
+// RequeueExpiredJobs возвращает в очередь задачи с истекшей арендой.
+// Задачи, исчерпавшие maxAttempts, помечаются failed.
+func (r *Repository) RequeueExpiredJobs(ctx context.Context, maxAttempts int) (int64, error) {
+	var n int64
+	var crawls []int64
+	err := r.pool.QueryRow(ctx, `
+WITH expired AS (
+  UPDATE crawl_jobs SET
+    state = CASE WHEN attempts >= $1 THEN 'failed' ELSE 'queued' END,
+    last_error = CASE WHEN attempts >= $1 THEN 'lease expired' ELSE last_error END,
+    locked_by = NULL,
+    lease_expires_at = NULL,
+    updated_at = now()
+  WHERE state IN ('fetching', 'parsing') AND lease_expires_at < now()
+  RETURNING crawl_id, state
+)
+SELECT count(*), coalesce(array_agg(DISTINCT crawl_id) FILTER (WHERE crawl_id IS NOT NULL AND state = 'failed'), '{}')
+FROM expired`, maxAttempts).Scan(&n, &crawls)
+	if err != nil {
+		return 0, err
+	}
+	if len(crawls) > 0 {
+		if _, err := r.pool.Exec(ctx, completeCrawlsSQL, crawls); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// RecoverJobs возвращает в очередь задачи, которые были в работе у workerID на момент остановки.
+func (r *Repository) RecoverJobs(ctx context.Context, workerID string) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `
+UPDATE crawl_jobs SET state = 'queued', locked_by = NULL, lease_expires_at = NULL, updated_at = now()
+WHERE locked_by = $1 AND state IN ('fetching', 'parsing')`, workerID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *Repository) GetJob(ctx context.Context, id int64) (*CrawlJob, error) {
+	return scanJob(r.pool.QueryRow(ctx, "SELECT "+jobColumns+" FROM crawl_jobs WHERE id=$1", id))
+}
+
+func (r *Repository) ListJobs(ctx context.Context, state JobState, limit, offset int32) ([]*CrawlJob, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+jobColumns+" FROM crawl_jobs WHERE ($1 = '' OR state = $1) ORDER BY id DESC LIMIT $2 OFFSET $3", state, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*CrawlJob
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, j)
+	}
+	return res, rows.Err()
+}
+
+func (r *Repository) RecordJobEvent(ctx context.Context, jobID int64, stage, status, errText string) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO crawl_job_events (job_id, stage, status, error) VALUES ($1,$2,$3,NULLIF($4, ''))", jobID, stage, status, errText)
+	return err
+}
+
+func (r *Repository) ListJobEvents(ctx context.Context, jobID int64) ([]*JobEvent, error) {
+	rows, err := r.pool.Query(ctx, "SELECT id, job_id, stage, status, coalesce(error, ''), created_at FROM crawl_job_events WHERE job_id=$1 ORDER BY id", jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*JobEvent
+	for rows.Next() {
+		var e JobEvent
+		if err := rows.Scan(&e.ID, &e.JobID, &e.Stage, &e.Status, &e.Error, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, &e)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS crawl_jobs (
+    id bigserial PRIMARY KEY,
+    url text NOT NULL,
+    state text NOT NULL DEFAULT 'queued',
+    attempts integer NOT NULL DEFAULT 0,
+    locked_by text,
+    lease_expires_at timestamptz,
+    last_error text,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_crawl_jobs_queued ON crawl_jobs (id) WHERE state = 'queued';
+CREATE INDEX IF NOT EXISTS idx_crawl_jobs_lease ON crawl_jobs (lease_expires_at) WHERE state IN ('fetching', 'parsing');
+CREATE INDEX IF NOT EXISTS idx_crawl_jobs_locked_by ON crawl_jobs (locked_by) WHERE locked_by IS NOT NULL;
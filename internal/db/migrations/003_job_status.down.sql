@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS crawl_job_events;
+DROP INDEX IF EXISTS idx_crawl_jobs_state;
+ALTER TABLE crawl_jobs DROP COLUMN IF EXISTS article_id;
@@ -0,0 +1,14 @@
+ALTER TABLE crawl_jobs ADD COLUMN IF NOT EXISTS article_id bigint REFERENCES articles (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_crawl_jobs_state ON crawl_jobs (state, id);
+
+CREATE TABLE IF NOT EXISTS crawl_job_events (
+    id bigserial PRIMARY KEY,
+    job_id bigint NOT NULL REFERENCES crawl_jobs (id) ON DELETE CASCADE,
+    stage text NOT NULL,
+    status text NOT NULL,
+    error text,
+    created_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_crawl_job_events_job_id ON crawl_job_events (job_id, id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_articles_published_at;
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS author,
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS modified_at,
+    DROP COLUMN IF EXISTS canonical_url,
+    DROP COLUMN IF EXISTS site_name,
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS section,
+    DROP COLUMN IF EXISTS keywords;
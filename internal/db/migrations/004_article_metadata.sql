@@ -0,0 +1,11 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS author text,
+    ADD COLUMN IF NOT EXISTS published_at timestamptz,
+    ADD COLUMN IF NOT EXISTS modified_at timestamptz,
+    ADD COLUMN IF NOT EXISTS canonical_url text,
+    ADD COLUMN IF NOT EXISTS site_name text,
+    ADD COLUMN IF NOT EXISTS image_url text,
+    ADD COLUMN IF NOT EXISTS section text,
+    ADD COLUMN IF NOT EXISTS keywords text[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_articles_published_at ON articles (published_at);
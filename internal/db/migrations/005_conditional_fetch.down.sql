@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_crawl_jobs_active_url;
+DROP INDEX IF EXISTS idx_articles_next_recrawl_at;
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS etag,
+    DROP COLUMN IF EXISTS last_modified,
+    DROP COLUMN IF EXISTS response_hash,
+    DROP COLUMN IF EXISTS last_checked_at,
+    DROP COLUMN IF EXISTS next_recrawl_at;
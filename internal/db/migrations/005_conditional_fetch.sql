@@ -0,0 +1,10 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS etag text,
+    ADD COLUMN IF NOT EXISTS last_modified text,
+    ADD COLUMN IF NOT EXISTS response_hash text,
+    ADD COLUMN IF NOT EXISTS last_checked_at timestamptz DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS next_recrawl_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_articles_next_recrawl_at ON articles (next_recrawl_at) WHERE next_recrawl_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_crawl_jobs_active_url ON crawl_jobs (url) WHERE state IN ('queued', 'fetching', 'parsing');
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id bigserial PRIMARY KEY,
+    article_id bigint NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    content_hash text NOT NULL,
+    title text,
+    body text,
+    summary text,
+    created_at timestamptz DEFAULT now(),
+    last_seen_at timestamptz DEFAULT now(),
+    UNIQUE (article_id, content_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_revisions_article_id ON article_revisions (article_id, id);
+
+INSERT INTO article_revisions (article_id, content_hash, title, body, summary, created_at, last_seen_at)
+SELECT id, content_hash, title, body, summary, updated_at, updated_at FROM articles WHERE content_hash IS NOT NULL
+ON CONFLICT (article_id, content_hash) DO NOTHING;
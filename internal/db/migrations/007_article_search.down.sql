@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_articles_language;
+DROP INDEX IF EXISTS idx_articles_domain;
+DROP INDEX IF EXISTS idx_articles_search_vector;
+ALTER TABLE articles DROP COLUMN IF EXISTS domain;
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS crawler_websearch_all(text);
+DROP FUNCTION IF EXISTS crawler_ts_config(text);
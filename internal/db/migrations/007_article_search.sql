@@ -0,0 +1,64 @@
+-- Конфигурация полнотекстового поиска по коду языка из whatlanggo (ISO 639-3).
+CREATE OR REPLACE FUNCTION crawler_ts_config(lang text) RETURNS regconfig
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT CASE lang
+        WHEN 'arb' THEN 'arabic'
+        WHEN 'dan' THEN 'danish'
+        WHEN 'deu' THEN 'german'
+        WHEN 'ell' THEN 'greek'
+        WHEN 'eng' THEN 'english'
+        WHEN 'fin' THEN 'finnish'
+        WHEN 'fra' THEN 'french'
+        WHEN 'hun' THEN 'hungarian'
+        WHEN 'ind' THEN 'indonesian'
+        WHEN 'ita' THEN 'italian'
+        WHEN 'lit' THEN 'lithuanian'
+        WHEN 'nep' THEN 'nepali'
+        WHEN 'nld' THEN 'dutch'
+        WHEN 'nob' THEN 'norwegian'
+        WHEN 'por' THEN 'portuguese'
+        WHEN 'ron' THEN 'romanian'
+        WHEN 'rus' THEN 'russian'
+        WHEN 'spa' THEN 'spanish'
+        WHEN 'srp' THEN 'serbian'
+        WHEN 'swe' THEN 'swedish'
+        WHEN 'tam' THEN 'tamil'
+        WHEN 'tur' THEN 'turkish'
+        WHEN 'ydd' THEN 'yiddish'
+        ELSE 'simple'
+    END::regconfig
+$$;
+
+-- Запрос без фильтра по языку: объединение (OR) разборов во всех конфигурациях,
+-- чтобы один и тот же GIN-индекс находил статьи на любом языке.
+CREATE OR REPLACE FUNCTION crawler_websearch_all(q text) RETURNS tsquery
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT websearch_to_tsquery('simple', q)
+        || websearch_to_tsquery('arabic', q) || websearch_to_tsquery('danish', q)
+        || websearch_to_tsquery('german', q) || websearch_to_tsquery('greek', q)
+        || websearch_to_tsquery('english', q) || websearch_to_tsquery('finnish', q)
+        || websearch_to_tsquery('french', q) || websearch_to_tsquery('hungarian', q)
+        || websearch_to_tsquery('indonesian', q) || websearch_to_tsquery('italian', q)
+        || websearch_to_tsquery('lithuanian', q) || websearch_to_tsquery('nepali', q)
+        || websearch_to_tsquery('dutch', q) || websearch_to_tsquery('norwegian', q)
+        || websearch_to_tsquery('portuguese', q) || websearch_to_tsquery('romanian', q)
+        || websearch_to_tsquery('russian', q) || websearch_to_tsquery('spanish', q)
+        || websearch_to_tsquery('serbian', q) || websearch_to_tsquery('swedish', q)
+        || websearch_to_tsquery('tamil', q) || websearch_to_tsquery('turkish', q)
+        || websearch_to_tsquery('yiddish', q)
+$$;
+
+-- body обрезается, чтобы огромные страницы не упирались в лимит размера tsvector.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(crawler_ts_config(language), coalesce(title, '')), 'A') ||
+    setweight(to_tsvector(crawler_ts_config(language), coalesce(summary, '')), 'B') ||
+    setweight(to_tsvector(crawler_ts_config(language), left(coalesce(body, ''), 200000)), 'C')
+) STORED;
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS domain text GENERATED ALWAYS AS (
+    lower(substring(url from '^[a-zA-Z][a-zA-Z0-9+.-]*://(?:[^@/]*@)?([^/:?#]+)'))
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_articles_domain ON articles (domain);
+CREATE INDEX IF NOT EXISTS idx_articles_language ON articles (language);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_articles_cluster_id;
+DROP INDEX IF EXISTS idx_articles_simhash_b3;
+DROP INDEX IF EXISTS idx_articles_simhash_b2;
+DROP INDEX IF EXISTS idx_articles_simhash_b1;
+DROP INDEX IF EXISTS idx_articles_simhash_b0;
+ALTER TABLE articles DROP COLUMN IF EXISTS cluster_id, DROP COLUMN IF EXISTS simhash;
@@ -0,0 +1,11 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS simhash bigint,
+    ADD COLUMN IF NOT EXISTS cluster_id bigint REFERENCES articles(id) ON DELETE SET NULL;
+
+-- Полосы по 16 бит для поиска кандидатов: при расстоянии до 3 бит хотя бы одна совпадает.
+CREATE INDEX IF NOT EXISTS idx_articles_simhash_b0 ON articles ((simhash & 65535)) WHERE simhash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_articles_simhash_b1 ON articles (((simhash >> 16) & 65535)) WHERE simhash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_articles_simhash_b2 ON articles (((simhash >> 32) & 65535)) WHERE simhash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_articles_simhash_b3 ON articles (((simhash >> 48) & 65535)) WHERE simhash IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_articles_cluster_id ON articles (cluster_id) WHERE cluster_id IS NOT NULL;
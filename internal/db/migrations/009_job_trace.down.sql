@@ -0,0 +1 @@
+ALTER TABLE crawl_jobs DROP COLUMN IF EXISTS trace_parent;
@@ -0,0 +1 @@
+ALTER TABLE crawl_jobs ADD COLUMN IF NOT EXISTS trace_parent text;
@@ -0,0 +1,9 @@
+-- Общие для реплик ведра токенов по доменам (GCRA): tat - теоретическое время,
+-- к которому ведро снова станет полным. Строка без запросов дольше burst/rps ничего не хранит
+-- и может быть удалена.
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    domain text PRIMARY KEY,
+    tat timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_tat ON rate_limit_buckets (tat);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_crawl_jobs_crawl_id;
+ALTER TABLE crawl_jobs DROP COLUMN IF EXISTS depth;
+ALTER TABLE crawl_jobs DROP COLUMN IF EXISTS crawl_id;
+DROP TABLE IF EXISTS crawl_visited;
+DROP TABLE IF EXISTS crawls;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS crawls (
+    id bigserial PRIMARY KEY,
+    seed_url text NOT NULL,
+    max_depth integer NOT NULL DEFAULT 0,
+    max_pages integer NOT NULL DEFAULT 0,
+    scope text NOT NULL DEFAULT 'host',
+    include_patterns text[] NOT NULL DEFAULT '{}',
+    exclude_patterns text[] NOT NULL DEFAULT '{}',
+    state text NOT NULL DEFAULT 'running',
+    pages_queued integer NOT NULL DEFAULT 0,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    finished_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS crawl_visited (
+    crawl_id bigint NOT NULL REFERENCES crawls (id) ON DELETE CASCADE,
+    url text NOT NULL,
+    PRIMARY KEY (crawl_id, url)
+);
+
+ALTER TABLE crawl_jobs ADD COLUMN IF NOT EXISTS crawl_id bigint REFERENCES crawls (id) ON DELETE SET NULL;
+ALTER TABLE crawl_jobs ADD COLUMN IF NOT EXISTS depth integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_crawl_jobs_crawl_id ON crawl_jobs (crawl_id, state) WHERE crawl_id IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_articles_source_id;
+ALTER TABLE articles DROP COLUMN IF EXISTS source_id;
+ALTER TABLE crawl_jobs DROP COLUMN IF EXISTS source_id;
+DROP TABLE IF EXISTS source_entries;
+DROP TABLE IF EXISTS sources;
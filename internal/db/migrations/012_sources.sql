@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS sources (
+    id bigserial PRIMARY KEY,
+    url text NOT NULL UNIQUE,
+    format text,
+    title text,
+    interval_seconds integer NOT NULL,
+    etag text,
+    last_modified text,
+    next_poll_at timestamptz NOT NULL DEFAULT now(),
+    last_polled_at timestamptz,
+    last_error text,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sources_next_poll ON sources (next_poll_at);
+
+CREATE TABLE IF NOT EXISTS source_entries (
+    source_id bigint NOT NULL REFERENCES sources (id) ON DELETE CASCADE,
+    url text NOT NULL,
+    title text,
+    published_at timestamptz,
+    created_at timestamptz DEFAULT now(),
+    PRIMARY KEY (source_id, url)
+);
+
+ALTER TABLE crawl_jobs ADD COLUMN IF NOT EXISTS source_id bigint REFERENCES sources (id) ON DELETE SET NULL;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS source_id bigint REFERENCES sources (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_articles_source_id ON articles (source_id) WHERE source_id IS NOT NULL;
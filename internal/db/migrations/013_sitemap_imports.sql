@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS sitemap_imports (
+    id bigserial PRIMARY KEY,
+    sitemaps text[] NOT NULL,
+    state text NOT NULL DEFAULT 'running',
+    worker_id text,
+    sitemaps_fetched integer NOT NULL DEFAULT 0,
+    sitemaps_failed integer NOT NULL DEFAULT 0,
+    urls_found integer NOT NULL DEFAULT 0,
+    urls_matched integer NOT NULL DEFAULT 0,
+    urls_enqueued integer NOT NULL DEFAULT 0,
+    last_error text,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    finished_at timestamptz
+);
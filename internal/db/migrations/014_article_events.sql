@@ -0,0 +1,7 @@
+-- журнал событий хаба: по нему клиенты стрима догоняют пропущенное после переподключения
+CREATE TABLE IF NOT EXISTS article_events (
+    seq bigserial PRIMARY KEY,
+    event_type text NOT NULL,
+    article_id bigint NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    logged_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+    id bigserial PRIMARY KEY,
+    url text NOT NULL,
+    secret text NOT NULL,
+    domains text[] NOT NULL DEFAULT '{}',
+    language text,
+    title_query text,
+    title_regex text,
+    min_read_time integer NOT NULL DEFAULT 0,
+    source_id bigint,
+    event_types text[] NOT NULL DEFAULT '{}',
+    enabled boolean NOT NULL DEFAULT true,
+    consecutive_failures integer NOT NULL DEFAULT 0,
+    disabled_reason text,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now()
+);
+
+-- одна доставка на событие и подписку: события приходят в хаб каждой реплики
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id bigserial PRIMARY KEY,
+    webhook_id bigint NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_seq bigint NOT NULL,
+    event_type text NOT NULL,
+    article_id bigint NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    state text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    last_status integer,
+    last_error text,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now(),
+    delivered_at timestamptz,
+    UNIQUE (webhook_id, event_seq)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE state = 'pending';
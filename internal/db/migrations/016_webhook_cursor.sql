@@ -0,0 +1,7 @@
+-- до какого события журнала диспетчер вебхуков уже завел доставки: после рестарта он
+-- дочитывает журнал с этого места, а не только живые события
+CREATE TABLE IF NOT EXISTS webhook_cursor (
+    id boolean PRIMARY KEY DEFAULT true CHECK (id),
+    routed_seq bigint NOT NULL,
+    updated_at timestamptz DEFAULT now()
+);
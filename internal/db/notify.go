@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// EventsChannel - канал NOTIFY, в который AppendArticleEvent сообщает о новых событиях.
+const EventsChannel = "article_events"
+
+const (
+	// если уведомлений нет дольше, соединение проверяется пингом: обрыв без RST иначе не заметить
+	listenPingInterval = 30 * time.Second
+	listenRetryMin     = time.Second
+	listenRetryMax     = 30 * time.Second
+)
+
+// EventNotification - содержимое уведомления о событии статьи.
+type EventNotification struct {
+	Seq       int64  `json:"seq"`
+	Type      string `json:"type"`
+	ArticleID int64  `json:"article_id"`
+}
+
+// Listener слушает EventsChannel на отдельном соединении: соединение с LISTEN нельзя возвращать
+// в пул, а ожидание уведомлений заняло бы его навсегда.
+type Listener struct {
+	dbURL string
+}
+
+func NewListener(dbURL string) *Listener {
+	return &Listener{dbURL: dbURL}
+}
+
+// Run держит соединение, пока ctx не отменен, и переподключается после обрыва с растущей паузой.
+// onConnect вызывается после каждого подключения, когда LISTEN уже выполнен: уведомления,
+// отправленные до этого момента, потеряны, и их надо взять из журнала. handle вызывается
+// на каждое уведомление в порядке коммитов.
+func (l *Listener) Run(ctx context.Context, onConnect func(), handle func(EventNotification)) {
+	retry := listenRetryMin
+	for {
+		err := l.listen(ctx, func() {
+			retry = listenRetryMin
+			onConnect()
+		}, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("[listener] %v, reconnecting in %s", err, retry)
+		select {
+		case <-time.After(retry):
+		case <-ctx.Done():
+			return
+		}
+		retry = min(retry*2, listenRetryMax)
+	}
+}
+
+func (l *Listener) listen(ctx context.Context, onConnect func(), handle func(EventNotification)) error {
+	conn, err := pgx.Connect(ctx, l.dbURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{EventsChannel}.Sanitize()); err != nil {
+		return err
+	}
+	log.Printf("[listener] listening on %s", EventsChannel)
+	onConnect()
+	for {
+		wctx, cancel := context.WithTimeout(ctx, listenPingInterval)
+		n, err := conn.WaitForNotification(wctx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			pctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			err = conn.Ping(pctx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var ev EventNotification
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			log.Printf("[listener] bad payload %q: %v", n.Payload, err)
+			continue
+		}
+		handle(ev)
+	}
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// TakeRateToken забирает токен из общего ведра домена по алгоритму GCRA: каждый запрос сдвигает
+// tat на interval, запрос разрешен, пока tat опережает текущее время не больше чем на tolerance
+// (interval = 1/rps, tolerance = burst/rps). Проверка и сдвиг делаются одним запросом,
+// поэтому реплики не могут вместе превысить лимит. Время берется у Postgres, а не у реплик.
+// Возвращает 0, если токен взят, иначе - через сколько появится следующий (tat + interval - tolerance),
+// чтобы ожидающий не опрашивал таблицу.
+func (r *Repository) TakeRateToken(ctx context.Context, domain string, interval, tolerance time.Duration) (time.Duration, error) {
+	var taken bool
+	var wait float64
+	err := r.pool.QueryRow(ctx, `
+WITH taken AS (
+  INSERT INTO rate_limit_buckets AS b (domain, tat)
+  VALUES ($1, now() + make_interval(secs => $2))
+  ON CONFLICT (domain) DO UPDATE
+  SET tat = greatest(b.tat, now()) + make_interval(secs => $2)
+  WHERE greatest(b.tat, now()) + make_interval(secs => $2) - now() <= make_interval(secs => $3)
+  RETURNING true AS taken, 0::float8 AS wait
+)
+SELECT taken, wait FROM taken
+UNION ALL
+SELECT false, greatest(extract(epoch FROM b.tat + make_interval(secs => $2) - now()) - $3, 0)::float8
+FROM rate_limit_buckets b
+WHERE b.domain = $1 AND NOT EXISTS (SELECT 1 FROM taken)`, domain, interval.Seconds(), tolerance.Seconds()).Scan(&taken, &wait)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// ведро вставила параллельная транзакция, которую снимок запроса еще не видит
+		return interval, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if taken {
+		return 0, nil
+	}
+	// +1ms: отказ с нулевым ожиданием (граница tolerance совпала с now()) не должен давать 0
+	return time.Duration(wait*float64(time.Second)) + time.Millisecond, nil
+}
+
+// DeleteIdleRateBuckets удаляет ведра, которые полны уже дольше olderThan.
+func (r *Repository) DeleteIdleRateBuckets(ctx context.Context, olderThan time.Duration) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM rate_limit_buckets WHERE tat < now() - make_interval(secs => $1)", olderThan.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
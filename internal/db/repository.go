@@ -17,12 +17,58 @@ type Article struct {
 	ContentHash     string
 	Language        string
 	ReadTimeMinutes int32
+	Author          string
+	PublishedAt     *time.Time
+	ModifiedAt      *time.Time
+	CanonicalURL    string
+	SiteName        string
+	ImageURL        string
+	Section         string
+	Keywords        []string
+	ETag            string
+	LastModified    string
+	ResponseHash    string
+	LastCheckedAt   *time.Time
+	NextRecrawlAt   *time.Time
+	SimHash         uint64
+	ClusterID       int64
+	SourceID        int64 // лента, из которой статья пришла впервые, 0 - не из ленты
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
 
+type SaveStatus int
+
+const (
+	SaveInserted  SaveStatus = iota // новый url
+	SaveUpdated                     // url уже был, контент изменился
+	SaveUnchanged                   // у этого url уже сохранен такой же контент
+	SaveDuplicate                   // такой же контент сохранен под другим url
+)
+
+const articleColumns = `id, url, title, body, summary, content_hash, language, read_time_minutes,
+coalesce(author, ''), published_at, modified_at, coalesce(canonical_url, ''), coalesce(site_name, ''),
+coalesce(image_url, ''), coalesce(section, ''), keywords,
+coalesce(etag, ''), coalesce(last_modified, ''), coalesce(response_hash, ''), last_checked_at, next_recrawl_at,
+coalesce(simhash, 0), coalesce(cluster_id, 0), coalesce(source_id, 0), created_at, updated_at`
+
+func scanArticle(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*Article, error) {
+	var a Article
+	var simhash int64
+	dest := []interface{}{&a.ID, &a.URL, &a.Title, &a.Body, &a.Summary, &a.ContentHash, &a.Language, &a.ReadTimeMinutes,
+		&a.Author, &a.PublishedAt, &a.ModifiedAt, &a.CanonicalURL, &a.SiteName,
+		&a.ImageURL, &a.Section, &a.Keywords,
+		&a.ETag, &a.LastModified, &a.ResponseHash, &a.LastCheckedAt, &a.NextRecrawlAt,
+		&simhash, &a.ClusterID, &a.SourceID, &a.CreatedAt, &a.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	a.SimHash = uint64(simhash)
+	return &a, nil
+}
+
 type Repository struct {
-	pool *pgxpool.Pool
+	pool *tracedPool
 }
 
 func NewRepository(ctx context.Context, dbURL string) (*Repository, error) {
@@ -41,25 +87,42 @@ func NewRepository(ctx context.Context, dbURL string) (*Repository, error) {
 		return nil, err
 	}
 
-	return &Repository{pool: pool}, nil
+	return &Repository{pool: &tracedPool{Pool: pool}}, nil
 }
 
 func (r *Repository) Close() {
 	r.pool.Close()
 }
 
-func (r *Repository) SaveArticle(ctx context.Context, a *Article) (bool, error) {
+// Stat - статистика пула соединений для метрик.
+func (r *Repository) Stat() *pgxpool.Stat {
+	return r.pool.Stat()
+}
+
+// SaveArticle сохраняет статью по url. a.NextRecrawlAt учитывается только для новой статьи,
+// дальше расписание ведет планировщик перепроверок.
+func (r *Repository) SaveArticle(ctx context.Context, a *Article) (SaveStatus, error) {
 	if a.ContentHash != "" {
 		var existingID int64
-		err := r.pool.QueryRow(ctx, "SELECT id FROM articles WHERE content_hash=$1", a.ContentHash).Scan(&existingID)
+		var existingURL string
+		err := r.pool.QueryRow(ctx, "SELECT id, url FROM articles WHERE content_hash=$1", a.ContentHash).Scan(&existingID, &existingURL)
 		if err == nil {
-			return false, nil
+			a.ID = existingID
+			if existingURL == a.URL {
+				_, err := r.TouchArticle(ctx, a.URL, a.ETag, a.LastModified, a.ResponseHash)
+				return SaveUnchanged, err
+			}
+			return SaveDuplicate, nil
 		}
 	}
 	var id int64
+	var inserted bool
 	query := `
-INSERT INTO articles (url, title, body, summary, content_hash, language, read_time_minutes)
-VALUES ($1,$2,$3,$4,$5,$6,$7)
+INSERT INTO articles (url, title, body, summary, content_hash, language, read_time_minutes,
+  author, published_at, modified_at, canonical_url, site_name, image_url, section, keywords,
+  etag, last_modified, response_hash, last_checked_at, next_recrawl_at, simhash)
+VALUES ($1,$2,$3,$4,$5,$6,$7,NULLIF($8, ''),$9,$10,NULLIF($11, ''),NULLIF($12, ''),NULLIF($13, ''),NULLIF($14, ''),$15,
+  NULLIF($16, ''),NULLIF($17, ''),NULLIF($18, ''),now(),$19,NULLIF($20::bigint, 0))
 ON CONFLICT (url) DO UPDATE SET
   title = EXCLUDED.title,
   body = EXCLUDED.body,
@@ -67,41 +130,141 @@ ON CONFLICT (url) DO UPDATE SET
   content_hash = EXCLUDED.content_hash,
   language = EXCLUDED.language,
   read_time_minutes = EXCLUDED.read_time_minutes,
+  author = EXCLUDED.author,
+  published_at = EXCLUDED.published_at,
+  modified_at = EXCLUDED.modified_at,
+  canonical_url = EXCLUDED.canonical_url,
+  site_name = EXCLUDED.site_name,
+  image_url = EXCLUDED.image_url,
+  section = EXCLUDED.section,
+  keywords = EXCLUDED.keywords,
+  etag = EXCLUDED.etag,
+  last_modified = EXCLUDED.last_modified,
+  response_hash = EXCLUDED.response_hash,
+  simhash = EXCLUDED.simhash,
+  last_checked_at = now(),
   updated_at = now()
-RETURNING id
+RETURNING id, (xmax = 0)
 `
-	err := r.pool.QueryRow(ctx, query,
+	keywords := a.Keywords
+	if keywords == nil {
+		keywords = []string{}
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return SaveInserted, err
+	}
+	defer tx.Rollback(ctx)
+	err = tx.QueryRow(ctx, query,
 		a.URL, a.Title, a.Body, a.Summary, a.ContentHash, a.Language, a.ReadTimeMinutes,
-	).Scan(&id)
+		a.Author, a.PublishedAt, a.ModifiedAt, a.CanonicalURL, a.SiteName, a.ImageURL, a.Section, keywords,
+		a.ETag, a.LastModified, a.ResponseHash, a.NextRecrawlAt, int64(a.SimHash),
+	).Scan(&id, &inserted)
 	if err != nil {
-		return false, err
+		return SaveInserted, err
 	}
-	return true, nil
+	_, err = tx.Exec(ctx, `
+INSERT INTO article_revisions (article_id, content_hash, title, body, summary)
+VALUES ($1,$2,$3,$4,$5)
+ON CONFLICT (article_id, content_hash) DO UPDATE SET last_seen_at = now()`,
+		id, a.ContentHash, a.Title, a.Body, a.Summary)
+	if err != nil {
+		return SaveInserted, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return SaveInserted, err
+	}
+	a.ID = id
+	if !inserted {
+		return SaveUpdated, nil
+	}
+	return SaveInserted, nil
 }
 
-func (r *Repository) GetArticleByID(ctx context.Context, id int64) (*Article, error) {
-	var a Article
-	row := r.pool.QueryRow(ctx, "SELECT id, url, title, body, summary, content_hash, language, read_time_minutes, created_at, updated_at FROM articles WHERE id=$1", id)
-	err := row.Scan(&a.ID, &a.URL, &a.Title, &a.Body, &a.Summary, &a.ContentHash, &a.Language, &a.ReadTimeMinutes, &a.CreatedAt, &a.UpdatedAt)
+// TouchArticle отмечает, что url перепроверен и не изменился. Пустые валидаторы не затирают сохраненные.
+func (r *Repository) TouchArticle(ctx context.Context, url, etag, lastModified, responseHash string) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+UPDATE articles SET
+  last_checked_at = now(),
+  etag = coalesce(NULLIF($2, ''), etag),
+  last_modified = coalesce(NULLIF($3, ''), last_modified),
+  response_hash = coalesce(NULLIF($4, ''), response_hash)
+WHERE url = $1
+RETURNING id`, url, etag, lastModified, responseHash).Scan(&id)
+	return id, err
+}
+
+// EnqueueDueRecrawls ставит в очередь статьи, у которых подошло время перепроверки,
+// и сдвигает им next_recrawl_at на next(created_at). URL, по которым уже есть активная задача, не дублируются.
+// SKIP LOCKED позволяет нескольким репликам запускать планировщик одновременно.
+func (r *Repository) EnqueueDueRecrawls(ctx context.Context, limit int, next func(createdAt time.Time) time.Time) (int, error) {
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return &a, nil
+	defer tx.Rollback(ctx)
+	rows, err := tx.Query(ctx, `
+SELECT id, url, created_at FROM articles
+WHERE next_recrawl_at <= now()
+ORDER BY next_recrawl_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, err
+	}
+	type due struct {
+		id        int64
+		url       string
+		createdAt time.Time
+	}
+	var items []due
+	for rows.Next() {
+		var d due
+		if err := rows.Scan(&d.id, &d.url, &d.createdAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		items = append(items, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	enqueued := 0
+	for _, d := range items {
+		tag, err := tx.Exec(ctx, `
+INSERT INTO crawl_jobs (url, state)
+SELECT $1, 'queued'
+WHERE NOT EXISTS (SELECT 1 FROM crawl_jobs WHERE url = $1 AND state IN ('queued', 'fetching', 'parsing'))`, d.url)
+		if err != nil {
+			return 0, err
+		}
+		enqueued += int(tag.RowsAffected())
+		if _, err := tx.Exec(ctx, "UPDATE articles SET next_recrawl_at = $2 WHERE id = $1", d.id, next(d.createdAt)); err != nil {
+			return 0, err
+		}
+	}
+	return enqueued, tx.Commit(ctx)
+}
+
+func (r *Repository) GetArticleByID(ctx context.Context, id int64) (*Article, error) {
+	return scanArticle(r.pool.QueryRow(ctx, "SELECT "+articleColumns+" FROM articles WHERE id=$1", id))
 }
 
 func (r *Repository) ListArticles(ctx context.Context, limit, offset int32) ([]*Article, error) {
-	rows, err := r.pool.Query(ctx, "SELECT id, url, title, body, summary, content_hash, language, read_time_minutes, created_at, updated_at FROM articles ORDER BY created_at DESC LIMIT $1 OFFSET $2", limit, offset)
+	rows, err := r.pool.Query(ctx, "SELECT "+articleColumns+" FROM articles ORDER BY created_at DESC LIMIT $1 OFFSET $2", limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var res []*Article
 	for rows.Next() {
-		var a Article
-		if err := rows.Scan(&a.ID, &a.URL, &a.Title, &a.Body, &a.Summary, &a.ContentHash, &a.Language, &a.ReadTimeMinutes, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		a, err := scanArticle(rows)
+		if err != nil {
 			return nil, err
 		}
-		res = append(res, &a)
+		res = append(res, a)
 	}
 	return res, nil
 }
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type ArticleRevision struct {
+	ID          int64
+	ArticleID   int64
+	ContentHash string
+	Title       string
+	Body        string
+	Summary     string
+	CreatedAt   time.Time
+	LastSeenAt  time.Time
+}
+
+const revisionColumns = "id, article_id, content_hash, coalesce(title, ''), coalesce(body, ''), coalesce(summary, ''), created_at, last_seen_at"
+
+func scanRevision(row interface{ Scan(...interface{}) error }) (*ArticleRevision, error) {
+	var rev ArticleRevision
+	if err := row.Scan(&rev.ID, &rev.ArticleID, &rev.ContentHash, &rev.Title, &rev.Body, &rev.Summary, &rev.CreatedAt, &rev.LastSeenAt); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (r *Repository) ListArticleRevisions(ctx context.Context, articleID int64) ([]*ArticleRevision, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+revisionColumns+" FROM article_revisions WHERE article_id=$1 ORDER BY id", articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*ArticleRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rev)
+	}
+	return res, rows.Err()
+}
+
+func (r *Repository) GetArticleRevision(ctx context.Context, id int64) (*ArticleRevision, error) {
+	return scanRevision(r.pool.QueryRow(ctx, "SELECT "+revisionColumns+" FROM article_revisions WHERE id=$1", id))
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+type SearchParams struct {
+	Query    string
+	Language string
+	Domains  []string
+	From     *time.Time
+	To       *time.Time
+	Limit    int32
+	Offset   int32
+}
+
+type SearchHit struct {
+	Article *Article
+	Rank    float64
+	Snippet string
+}
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" ... \""
+
+// SearchArticles ищет по search_vector (см. миграцию 007). С фильтром по языку запрос
+// разбирается в конфигурации этого языка, без него - во всех сразу, чтобы находились
+// статьи на любом языке. Домен совпадает сам с собой и со всеми поддоменами.
+func (r *Repository) SearchArticles(ctx context.Context, p SearchParams) ([]*SearchHit, error) {
+	tsquery := "crawler_websearch_all($1)"
+	if p.Language != "" {
+		tsquery = "websearch_to_tsquery(crawler_ts_config($2), $1)"
+	}
+	domains := make([]string, 0, len(p.Domains))
+	for _, d := range p.Domains {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			domains = append(domains, strings.TrimPrefix(d, "www."))
+		}
+	}
+	query := `
+SELECT ` + articleColumns + `,
+  ts_rank_cd(a.search_vector, q.query) AS rank,
+  ts_headline(crawler_ts_config(a.language), a.body, q.query, '` + headlineOptions + `') AS snippet
+FROM articles a, (SELECT ` + tsquery + ` AS query) q
+WHERE a.search_vector @@ q.query
+  AND ($2 = '' OR a.language = $2)
+  AND (cardinality($3::text[]) = 0 OR EXISTS (
+    SELECT 1 FROM unnest($3::text[]) d WHERE a.domain = d OR a.domain LIKE '%.' || d))
+  AND ($4::timestamptz IS NULL OR coalesce(a.published_at, a.created_at) >= $4)
+  AND ($5::timestamptz IS NULL OR coalesce(a.published_at, a.created_at) < $5)
+ORDER BY rank DESC, a.id DESC
+LIMIT $6 OFFSET $7`
+	rows, err := r.pool.Query(ctx, query, p.Query, p.Language, domains, p.From, p.To, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*SearchHit
+	for rows.Next() {
+		var h SearchHit
+		a, err := scanArticle(rows, &h.Rank, &h.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		h.Article = a
+		res = append(res, &h)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"ArticleCrawler/internal/tracing"
+)
+
+type ImportState string
+
+const (
+	ImportRunning  ImportState = "running"
+	ImportFinished ImportState = "finished"
+	ImportFailed   ImportState = "failed"
+)
+
+// SitemapImport - загрузка URL из карт сайта. Sitemaps - корневые карты, с которых она началась.
+// UrlsMatched - сколько URL прошло фильтры, UrlsEnqueued - сколько из них стало новыми задачами
+// (остальные уже сохранены или ждут в очереди).
+type SitemapImport struct {
+	ID              int64
+	Sitemaps        []string
+	State           ImportState
+	SitemapsFetched int
+	SitemapsFailed  int
+	URLsFound       int
+	URLsMatched     int
+	URLsEnqueued    int
+	LastError       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	FinishedAt      *time.Time
+}
+
+// ImportProgress - приращения счетчиков импорта после одной карты.
+type ImportProgress struct {
+	Fetched  int
+	Failed   int
+	Found    int
+	Matched  int
+	Enqueued int
+	Err      string
+}
+
+const importColumns = `id, sitemaps, state, sitemaps_fetched, sitemaps_failed, urls_found, urls_matched, urls_enqueued,
+  coalesce(last_error, ''), created_at, updated_at, finished_at`
+
+func (r *Repository) CreateSitemapImport(ctx context.Context, sitemaps []string, workerID string) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, "INSERT INTO sitemap_imports (sitemaps, state, worker_id) VALUES ($1, $2, $3) RETURNING id",
+		sitemaps, ImportRunning, workerID).Scan(&id)
+	return id, err
+}
+
+func (r *Repository) GetSitemapImport(ctx context.Context, id int64) (*SitemapImport, error) {
+	var s SitemapImport
+	err := r.pool.QueryRow(ctx, "SELECT "+importColumns+" FROM sitemap_imports WHERE id = $1", id).Scan(
+		&s.ID, &s.Sitemaps, &s.State, &s.SitemapsFetched, &s.SitemapsFailed, &s.URLsFound, &s.URLsMatched, &s.URLsEnqueued,
+		&s.LastError, &s.CreatedAt, &s.UpdatedAt, &s.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// AddImportProgress прибавляет p к счетчикам импорта. Непустая p.Err становится last_error.
+func (r *Repository) AddImportProgress(ctx context.Context, id int64, p ImportProgress) error {
+	_, err := r.pool.Exec(ctx, `
+UPDATE sitemap_imports SET
+  sitemaps_fetched = sitemaps_fetched + $2,
+  sitemaps_failed = sitemaps_failed + $3,
+  urls_found = urls_found + $4,
+  urls_matched = urls_matched + $5,
+  urls_enqueued = urls_enqueued + $6,
+  last_error = coalesce(NULLIF($7, ''), last_error),
+  updated_at = now()
+WHERE id = $1`, id, p.Fetched, p.Failed, p.Found, p.Matched, p.Enqueued, p.Err)
+	return err
+}
+
+func (r *Repository) FinishSitemapImport(ctx context.Context, id int64, state ImportState, errText string) error {
+	_, err := r.pool.Exec(ctx, `
+UPDATE sitemap_imports SET state = $2, last_error = coalesce(NULLIF($3, ''), last_error), finished_at = now(), updated_at = now()
+WHERE id = $1`, id, state, errText)
+	return err
+}
+
+// FailInterruptedImports помечает failed импорты, которые workerID не закончил до остановки:
+// прочитанные карты уже поставили свои URL, остальные можно отправить заново.
+func (r *Repository) FailInterruptedImports(ctx context.Context, workerID string) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `
+UPDATE sitemap_imports SET state = $2, last_error = 'interrupted by restart', finished_at = now(), updated_at = now()
+WHERE worker_id = $1 AND state = $3`, workerID, ImportFailed, ImportRunning)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// EnqueueURLs ставит в очередь URL, для которых еще нет ни статьи, ни задачи в работе.
+// Возвращает число новых задач.
+func (r *Repository) EnqueueURLs(ctx context.Context, urls []string) (int, error) {
+	tag, err := r.pool.Exec(ctx, `
+INSERT INTO crawl_jobs (url, state, trace_parent)
+SELECT DISTINCT u, $2, NULLIF($3, '') FROM unnest($1::text[]) AS u
+WHERE NOT EXISTS (SELECT 1 FROM articles a WHERE a.url = u)
+  AND NOT EXISTS (SELECT 1 FROM crawl_jobs j WHERE j.url = u AND j.state IN ('queued', 'fetching', 'parsing'))`,
+		urls, JobQueued, tracing.Inject(ctx))
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ArticleCrawler/internal/tracing"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrSourceExists - лента с таким URL уже добавлена.
+var ErrSourceExists = errors.New("source already exists")
+
+// Source - лента RSS/Atom/JSON Feed, которую опрашивает SourcePoller.
+// Format и Title заполняются после первого успешного опроса.
+type Source struct {
+	ID           int64
+	URL          string
+	Format       string
+	Title        string
+	Interval     time.Duration
+	ETag         string
+	LastModified string
+	NextPollAt   time.Time
+	LastPolledAt *time.Time
+	LastError    string
+	Entries      int // сколько записей ленты уже поставлено в очередь
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type SourceEntry struct {
+	URL         string
+	Title       string
+	PublishedAt *time.Time
+}
+
+// SourcePoll - итог одного опроса ленты. При NotModified или ошибке формат, заголовок
+// и валидаторы остаются прежними.
+type SourcePoll struct {
+	Format       string
+	Title        string
+	ETag         string
+	LastModified string
+	NotModified  bool
+	Err          string
+}
+
+const sourceColumns = `s.id, s.url, coalesce(s.format, ''), coalesce(s.title, ''), s.interval_seconds,
+  coalesce(s.etag, ''), coalesce(s.last_modified, ''), s.next_poll_at, s.last_polled_at, coalesce(s.last_error, ''),
+  (SELECT count(*) FROM source_entries e WHERE e.source_id = s.id), s.created_at, s.updated_at`
+
+func scanSource(row interface{ Scan(...interface{}) error }) (*Source, error) {
+	var s Source
+	var interval int
+	err := row.Scan(&s.ID, &s.URL, &s.Format, &s.Title, &interval, &s.ETag, &s.LastModified,
+		&s.NextPollAt, &s.LastPolledAt, &s.LastError, &s.Entries, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	s.Interval = time.Duration(interval) * time.Second
+	return &s, nil
+}
+
+// AddSource добавляет ленту, первый опрос - при ближайшем проходе поллера.
+func (r *Repository) AddSource(ctx context.Context, url string, interval time.Duration) (*Source, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, "INSERT INTO sources (url, interval_seconds) VALUES ($1, $2) ON CONFLICT (url) DO NOTHING RETURNING id",
+		url, int(interval.Seconds())).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSourceExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetSource(ctx, id)
+}
+
+func (r *Repository) GetSource(ctx context.Context, id int64) (*Source, error) {
+	return scanSource(r.pool.QueryRow(ctx, "SELECT "+sourceColumns+" FROM sources s WHERE s.id = $1", id))
+}
+
+func (r *Repository) ListSources(ctx context.Context, limit, offset int32) ([]*Source, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+sourceColumns+" FROM sources s ORDER BY s.id LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*Source
+	for rows.Next() {
+		s, err := scanSource(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+// RemoveSource удаляет ленту и список ее записей. Статьи и задачи остаются, ссылка на ленту в них обнуляется.
+func (r *Repository) RemoveSource(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM sources WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ClaimDueSources выбирает до limit лент, которым пора на опрос, и откладывает их следующий опрос
+// на lease: если реплика упадет посреди опроса, лента вернется к опросу по истечении lease,
+// а другие реплики тем временем ее не возьмут.
+func (r *Repository) ClaimDueSources(ctx context.Context, limit int, lease time.Duration) ([]*Source, error) {
+	rows, err := r.pool.Query(ctx, `
+WITH claimed AS (
+  UPDATE sources SET next_poll_at = now() + make_interval(secs => $2)
+  WHERE id IN (
+    SELECT id FROM sources
+    WHERE next_poll_at <= now()
+    ORDER BY next_poll_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING *
+)
+SELECT `+sourceColumns+` FROM claimed s ORDER BY s.id`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*Source
+	for rows.Next() {
+		s, err := scanSource(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+// FinishSourcePoll записывает итог опроса и назначает следующий через интервал ленты.
+func (r *Repository) FinishSourcePoll(ctx context.Context, id int64, p SourcePoll) error {
+	keep := p.NotModified || p.Err != ""
+	_, err := r.pool.Exec(ctx, `
+UPDATE sources SET
+  format = CASE WHEN $2 THEN format ELSE NULLIF($3, '') END,
+  title = CASE WHEN $2 THEN title ELSE NULLIF($4, '') END,
+  etag = CASE WHEN $2 THEN etag ELSE NULLIF($5, '') END,
+  last_modified = CASE WHEN $2 THEN last_modified ELSE NULLIF($6, '') END,
+  last_error = NULLIF($7, ''),
+  last_polled_at = now(),
+  next_poll_at = now() + make_interval(secs => interval_seconds),
+  updated_at = now()
+WHERE id = $1`, id, keep, p.Format, p.Title, p.ETag, p.LastModified, p.Err)
+	return err
+}
+
+// EnqueueSourceEntries запоминает записи ленты и ставит в очередь задачи для тех, которых еще не было.
+// Возвращает число новых задач.
+func (r *Repository) EnqueueSourceEntries(ctx context.Context, sourceID int64, entries []SourceEntry) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	traceParent := tracing.Inject(ctx)
+	enqueued := 0
+	for _, e := range entries {
+		tag, err := tx.Exec(ctx, "INSERT INTO source_entries (source_id, url, title, published_at) VALUES ($1, $2, NULLIF($3, ''), $4) ON CONFLICT DO NOTHING",
+			sourceID, e.URL, e.Title, e.PublishedAt)
+		if err != nil {
+			return 0, err
+		}
+		if tag.RowsAffected() == 0 {
+			continue
+		}
+		_, err = tx.Exec(ctx, "INSERT INTO crawl_jobs (url, state, source_id, trace_parent) VALUES ($1, $2, $3, NULLIF($4, ''))",
+			e.URL, JobQueued, sourceID, traceParent)
+		if err != nil {
+			return 0, err
+		}
+		enqueued++
+	}
+	return enqueued, tx.Commit(ctx)
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"ArticleCrawler/internal/tracing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// У pgx v4 нет хука для трассировки, поэтому пул и транзакции обернуты вручную:
+// каждый запрос получает свой спан, для Query он закрывается вместе с rows.
+var tracer = otel.Tracer("ArticleCrawler/db")
+
+func startQuery(ctx context.Context, sql string) (context.Context, trace.Span) {
+	stmt := strings.Join(strings.Fields(sql), " ")
+	op := stmt
+	if i := strings.IndexByte(op, ' '); i > 0 {
+		op = op[:i]
+	}
+	return tracer.Start(ctx, "db."+strings.ToLower(op), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", stmt),
+	))
+}
+
+type tracedPool struct {
+	*pgxpool.Pool
+}
+
+func (p *tracedPool) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, span := startQuery(ctx, sql)
+	defer span.End()
+	tag, err := p.Pool.Exec(ctx, sql, args...)
+	tracing.RecordError(span, err)
+	return tag, err
+}
+
+func (p *tracedPool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := startQuery(ctx, sql)
+	rows, err := p.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		span.End()
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (p *tracedPool) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, span := startQuery(ctx, sql)
+	return &tracedRow{row: p.Pool.QueryRow(ctx, sql, args...), span: span}
+}
+
+func (p *tracedPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedTx{Tx: tx}, nil
+}
+
+type tracedTx struct {
+	pgx.Tx
+}
+
+func (t *tracedTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, span := startQuery(ctx, sql)
+	defer span.End()
+	tag, err := t.Tx.Exec(ctx, sql, args...)
+	tracing.RecordError(span, err)
+	return tag, err
+}
+
+func (t *tracedTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := startQuery(ctx, sql)
+	rows, err := t.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		span.End()
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (t *tracedTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, span := startQuery(ctx, sql)
+	return &tracedRow{row: t.Tx.QueryRow(ctx, sql, args...), span: span}
+}
+
+type tracedRows struct {
+	pgx.Rows
+	span trace.Span
+	done bool
+}
+
+func (r *tracedRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.finish()
+	return false
+}
+
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+	r.finish()
+}
+
+func (r *tracedRows) finish() {
+	if r.done {
+		return
+	}
+	r.done = true
+	tracing.RecordError(r.span, r.Rows.Err())
+	r.span.End()
+}
+
+type tracedRow struct {
+	row  pgx.Row
+	span trace.Span
+}
+
+func (r *tracedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	if err != pgx.ErrNoRows {
+		tracing.RecordError(r.span, err)
+	}
+	r.span.End()
+	return err
+}
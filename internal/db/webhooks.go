@@ -0,0 +1,294 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Webhook - подписка на события статей по HTTP. Фильтры те же, что у StreamNewArticles,
+// EventTypes пуст - все типы событий. Подписка выключается (Enabled = false) после
+// заданного числа неудачных доставок подряд, причина - в DisabledReason.
+type Webhook struct {
+	ID                  int64
+	URL                 string
+	Secret              string
+	Domains             []string
+	Language            string
+	TitleQuery          string
+	TitleRegex          string
+	MinReadTime         int32
+	SourceID            int64
+	EventTypes          []string
+	Enabled             bool
+	ConsecutiveFailures int
+	DisabledReason      string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+type DeliveryState string
+
+const (
+	DeliveryPending   DeliveryState = "pending"
+	DeliveryDelivered DeliveryState = "delivered"
+	DeliveryFailed    DeliveryState = "failed"
+)
+
+// WebhookDelivery - отправка одного события одной подписке. URL и Secret берутся из подписки
+// при выборке доставок на отправку.
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     int64
+	EventSeq      int64
+	EventType     string
+	ArticleID     int64
+	State         DeliveryState
+	Attempts      int
+	NextAttemptAt time.Time
+	LastStatus    int
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeliveredAt   *time.Time
+	URL           string
+	Secret        string
+}
+
+// WebhookAttempt - итог попытки доставки. Retry > 0 - повторить через Retry, иначе неудачная доставка
+// считается окончательно проваленной. После DisableAfter неудач подряд подписка выключается.
+type WebhookAttempt struct {
+	Status       int
+	Err          string
+	Delivered    bool
+	Retry        time.Duration
+	DisableAfter int
+}
+
+const webhookColumns = `id, url, secret, domains, coalesce(language, ''), coalesce(title_query, ''), coalesce(title_regex, ''),
+  min_read_time, coalesce(source_id, 0), event_types, enabled, consecutive_failures, coalesce(disabled_reason, ''), created_at, updated_at`
+
+func scanWebhook(row interface{ Scan(...interface{}) error }) (*Webhook, error) {
+	var w Webhook
+	err := row.Scan(&w.ID, &w.URL, &w.Secret, &w.Domains, &w.Language, &w.TitleQuery, &w.TitleRegex,
+		&w.MinReadTime, &w.SourceID, &w.EventTypes, &w.Enabled, &w.ConsecutiveFailures, &w.DisabledReason, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_seq, d.event_type, d.article_id, d.state, d.attempts, d.next_attempt_at,
+  coalesce(d.last_status, 0), coalesce(d.last_error, ''), d.created_at, d.updated_at, d.delivered_at`
+
+func scanDelivery(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	dest := []interface{}{&d.ID, &d.WebhookID, &d.EventSeq, &d.EventType, &d.ArticleID, &d.State, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatus, &d.LastError, &d.CreatedAt, &d.UpdatedAt, &d.DeliveredAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *Repository) CreateWebhook(ctx context.Context, w *Webhook) (*Webhook, error) {
+	if w.Domains == nil {
+		w.Domains = []string{}
+	}
+	if w.EventTypes == nil {
+		w.EventTypes = []string{}
+	}
+	return scanWebhook(r.pool.QueryRow(ctx, `
+INSERT INTO webhooks (url, secret, domains, language, title_query, title_regex, min_read_time, source_id, event_types)
+VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, NULLIF($8, 0), $9)
+RETURNING `+webhookColumns,
+		w.URL, w.Secret, w.Domains, w.Language, w.TitleQuery, w.TitleRegex, w.MinReadTime, w.SourceID, w.EventTypes))
+}
+
+func (r *Repository) GetWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	return scanWebhook(r.pool.QueryRow(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE id = $1", id))
+}
+
+func (r *Repository) ListWebhooks(ctx context.Context, limit, offset int32) ([]*Webhook, error) {
+	return r.queryWebhooks(ctx, "SELECT "+webhookColumns+" FROM webhooks ORDER BY id LIMIT $1 OFFSET $2", limit, offset)
+}
+
+// ListEnabledWebhooks возвращает подписки, которым надо отправлять события.
+func (r *Repository) ListEnabledWebhooks(ctx context.Context) ([]*Webhook, error) {
+	return r.queryWebhooks(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE enabled ORDER BY id")
+}
+
+func (r *Repository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]*Webhook, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, w)
+	}
+	return res, rows.Err()
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом ее доставок.
+func (r *Repository) DeleteWebhook(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// EnableWebhook включает подписку и сбрасывает счетчик неудач. Доставки, ждавшие повтора,
+// отправляются снова.
+func (r *Repository) EnableWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	return scanWebhook(r.pool.QueryRow(ctx, `
+UPDATE webhooks SET enabled = true, consecutive_failures = 0, disabled_reason = NULL, updated_at = now()
+WHERE id = $1
+RETURNING `+webhookColumns, id))
+}
+
+// CreateWebhookDeliveries ставит событие на доставку подпискам webhookIDs. Повтор того же события
+// (его получает хаб каждой реплики) ничего не добавляет. Возвращает число новых доставок.
+func (r *Repository) CreateWebhookDeliveries(ctx context.Context, webhookIDs []int64, seq int64, eventType string, articleID int64) (int, error) {
+	tag, err := r.pool.Exec(ctx, `
+INSERT INTO webhook_deliveries (webhook_id, event_seq, event_type, article_id)
+SELECT w, $2, $3, $4 FROM unnest($1::bigint[]) AS w
+ON CONFLICT (webhook_id, event_seq) DO NOTHING`, webhookIDs, seq, eventType, articleID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// WebhookCursor возвращает номер последнего события журнала, для которого доставки уже заведены.
+// При первом вызове курсор ставится на конец журнала: события до включения вебхуков не рассылаются.
+func (r *Repository) WebhookCursor(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.pool.QueryRow(ctx, `
+INSERT INTO webhook_cursor (routed_seq)
+SELECT coalesce(max(seq), 0) FROM article_events
+ON CONFLICT (id) DO UPDATE SET routed_seq = webhook_cursor.routed_seq
+RETURNING routed_seq`).Scan(&seq)
+	return seq, err
+}
+
+// AdvanceWebhookCursor сдвигает курсор вперед до seq. Курсор общий для реплик: каждая заводит
+// доставки для всех событий, поэтому отставшая реплика не возвращает его назад.
+func (r *Repository) AdvanceWebhookCursor(ctx context.Context, seq int64) error {
+	_, err := r.pool.Exec(ctx, `
+UPDATE webhook_cursor SET routed_seq = greatest(routed_seq, $1), updated_at = now()
+WHERE routed_seq < $1`, seq)
+	return err
+}
+
+// ClaimWebhookDeliveries выбирает до limit доставок, которым пора, у включенных подписок и
+// откладывает их следующую попытку на lease: если реплика упадет посреди отправки,
+// доставка повторится после lease, а другие реплики тем временем ее не возьмут.
+func (r *Repository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, `
+WITH claimed AS (
+  UPDATE webhook_deliveries SET next_attempt_at = now() + make_interval(secs => $2)
+  WHERE id IN (
+    SELECT d.id FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+    WHERE d.state = $3 AND d.next_attempt_at <= now() AND w.enabled
+    ORDER BY d.next_attempt_at
+    LIMIT $1
+    FOR UPDATE OF d SKIP LOCKED
+  )
+  RETURNING *
+)
+SELECT `+deliveryColumns+`, w.url, w.secret FROM claimed d JOIN webhooks w ON w.id = d.webhook_id ORDER BY d.id`,
+		limit, lease.Seconds(), DeliveryPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*WebhookDelivery
+	for rows.Next() {
+		var url, secret string
+		d, err := scanDelivery(rows, &url, &secret)
+		if err != nil {
+			return nil, err
+		}
+		d.URL, d.Secret = url, secret
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+// FinishWebhookAttempt записывает итог попытки в доставку и счетчик неудач подписки.
+// Возвращает true, если подписка выключена этой неудачей.
+func (r *Repository) FinishWebhookAttempt(ctx context.Context, d *WebhookDelivery, a WebhookAttempt) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	state := DeliveryDelivered
+	switch {
+	case a.Delivered:
+	case a.Retry > 0:
+		state = DeliveryPending
+	default:
+		state = DeliveryFailed
+	}
+	_, err = tx.Exec(ctx, `
+UPDATE webhook_deliveries SET
+  state = $2,
+  attempts = attempts + 1,
+  last_status = NULLIF($3, 0),
+  last_error = NULLIF($4, ''),
+  next_attempt_at = now() + make_interval(secs => $5),
+  delivered_at = CASE WHEN $2 = 'delivered' THEN now() END,
+  updated_at = now()
+WHERE id = $1`, d.ID, state, a.Status, a.Err, a.Retry.Seconds())
+	if err != nil {
+		return false, err
+	}
+	disabled := false
+	if a.Delivered {
+		_, err = tx.Exec(ctx, "UPDATE webhooks SET consecutive_failures = 0, updated_at = now() WHERE id = $1 AND consecutive_failures > 0", d.WebhookID)
+	} else {
+		reason := fmt.Sprintf("disabled after %d consecutive failed deliveries, last: %s", a.DisableAfter, a.Err)
+		err = tx.QueryRow(ctx, `
+UPDATE webhooks w SET
+  consecutive_failures = w.consecutive_failures + 1,
+  enabled = w.enabled AND w.consecutive_failures + 1 < $2,
+  disabled_reason = CASE WHEN w.enabled AND w.consecutive_failures + 1 >= $2 THEN $3 ELSE w.disabled_reason END,
+  updated_at = now()
+FROM (SELECT enabled FROM webhooks WHERE id = $1 FOR UPDATE) old
+WHERE w.id = $1
+RETURNING old.enabled AND NOT w.enabled`, d.WebhookID, a.DisableAfter, reason).Scan(&disabled)
+	}
+	if err != nil {
+		return false, err
+	}
+	return disabled, tx.Commit(ctx)
+}
+
+// ListWebhookDeliveries - журнал доставок подписки, новые первыми. Пустой state - все.
+func (r *Repository) ListWebhookDeliveries(ctx context.Context, webhookID int64, state DeliveryState, limit, offset int32) ([]*WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT `+deliveryColumns+` FROM webhook_deliveries d
+WHERE d.webhook_id = $1 AND ($2 = '' OR d.state = $2)
+ORDER BY d.id DESC LIMIT $3 OFFSET $4`, webhookID, state, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
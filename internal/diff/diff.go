@@ -0,0 +1,195 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type OpType string
+
+const (
+	Equal  OpType = "equal"
+	Insert OpType = "insert"
+	Delete OpType = "delete"
+)
+
+type Op struct {
+	Type OpType
+	Text string
+}
+
+// MaxTokens - предел числа различающихся токенов (обоих текстов вместе, без общего начала и конца).
+// Время сравнения растет как (N+M)·D, поэтому сильно переписанные большие тексты не сравниваются.
+const MaxTokens = 20000
+
+// ErrTooLarge - различающаяся часть текстов больше MaxTokens.
+var ErrTooLarge = errors.New("texts differ in too many tokens to diff")
+
+// Lines сравнивает тексты построчно.
+func Lines(a, b string) ([]Op, error) {
+	return diffTokens(splitLines(a), splitLines(b))
+}
+
+// Words сравнивает тексты по словам; пробелы и переводы строк остаются частью токенов,
+// поэтому склейка Text всех Equal+Insert операций дает b, а Equal+Delete - a.
+func Words(a, b string) ([]Op, error) {
+	return diffTokens(splitWords(a), splitWords(b))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitWords режет текст на слова вместе с идущими за ними пробелами.
+func splitWords(s string) []string {
+	var res []string
+	start := 0
+	inSpace := false
+	for i, r := range s {
+		sp := unicode.IsSpace(r)
+		if !sp && inSpace {
+			res = append(res, s[start:i])
+			start = i
+		}
+		inSpace = sp
+	}
+	if start < len(s) {
+		res = append(res, s[start:])
+	}
+	return res
+}
+
+// diffTokens - алгоритм Майерса в линейной памяти: середина оптимального пути (средняя змейка)
+// ищется встречными проходами с начала и с конца, половины до и после нее сравниваются рекурсивно.
+func diffTokens(a, b []string) ([]Op, error) {
+	pre, suf := commonEnds(a, b)
+	if len(a)+len(b)-2*(pre+suf) > MaxTokens {
+		return nil, ErrTooLarge
+	}
+	return merge(compare(a, b, nil)), nil
+}
+
+// commonEnds возвращает длины общего начала и общего конца a и b (не перекрывающихся).
+func commonEnds(a, b []string) (int, int) {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	return pre, suf
+}
+
+func compare(a, b []string, ops []Op) []Op {
+	// общий префикс и суффикс отрезаем заранее, обычно правки локальные; после этого
+	// оба куска непусты только при D >= 2, и каждая половина меньше целого
+	pre, suf := commonEnds(a, b)
+	ops = appendOps(ops, Equal, a[:pre])
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	switch {
+	case len(ma) == 0:
+		ops = appendOps(ops, Insert, mb)
+	case len(mb) == 0:
+		ops = appendOps(ops, Delete, ma)
+	default:
+		x, y, u, v := middleSnake(ma, mb)
+		ops = compare(ma[:x], mb[:y], ops)
+		ops = appendOps(ops, Equal, ma[x:u])
+		ops = compare(ma[u:], mb[v:], ops)
+	}
+	return appendOps(ops, Equal, a[len(a)-suf:])
+}
+
+func appendOps(ops []Op, t OpType, tokens []string) []Op {
+	for _, tok := range tokens {
+		ops = append(ops, Op{Type: t, Text: tok})
+	}
+	return ops
+}
+
+// middleSnake возвращает змейку (x, y) -> (u, v), через которую проходит оптимальный путь.
+// vf[k] - самый дальний x прямого прохода на диагонали k = x - y, vb[k] - то же для обратного
+// прохода по перевернутым текстам; храним только фронты, O(N+M) памяти.
+func middleSnake(a, b []string) (int, int, int, int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	vf := make([]int, 2*offset+1)
+	vb := make([]int, 2*offset+1)
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+			// прямой путь встретил обратный, сделавший d-1 шагов
+			if odd && k >= delta-(d-1) && k <= delta+(d-1) && vf[offset+k]+vb[offset+delta-k] >= n {
+				return x0, y0, x, y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && vb[offset+k-1] < vb[offset+k+1]) {
+				x = vb[offset+k+1]
+			} else {
+				x = vb[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			vb[offset+k] = x
+			// обратный путь встретил прямой, сделавший d шагов
+			if !odd && delta-k >= -d && delta-k <= d && vb[offset+k]+vf[offset+delta-k] >= n {
+				return n - x, m - y, n - x0, m - y0
+			}
+		}
+	}
+	// пути встречаются не позже чем за ceil((N+M)/2) шагов
+	panic("diff: middle snake not found")
+}
+
+// merge склеивает соседние операции одного типа.
+func merge(ops []Op) []Op {
+	var res []Op
+	for i := 0; i < len(ops); {
+		j := i + 1
+		for j < len(ops) && ops[j].Type == ops[i].Type {
+			j++
+		}
+		if j == i+1 {
+			res = append(res, ops[i])
+		} else {
+			var sb strings.Builder
+			for _, op := range ops[i:j] {
+				sb.WriteString(op.Text)
+			}
+			res = append(res, Op{Type: ops[i].Type, Text: sb.String()})
+		}
+		i = j
+	}
+	return res
+}
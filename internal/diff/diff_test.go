@@ -0,0 +1,187 @@
+package diff
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// rebuild склеивает операции обратно в исходный (Equal+Delete) и новый (Equal+Insert) тексты.
+func rebuild(ops []Op) (string, string) {
+	var a, b strings.Builder
+	for _, op := range ops {
+		switch op.Type {
+		case Equal:
+			a.WriteString(op.Text)
+			b.WriteString(op.Text)
+		case Delete:
+			a.WriteString(op.Text)
+		case Insert:
+			b.WriteString(op.Text)
+		}
+	}
+	return a.String(), b.String()
+}
+
+// changed - число токенов в Insert и Delete операциях.
+func changed(ops []Op, split func(string) []string) int {
+	n := 0
+	for _, op := range ops {
+		if op.Type != Equal {
+			n += len(split(op.Text))
+		}
+	}
+	return n
+}
+
+// lcs - длина наибольшей общей подпоследовательности, для проверки минимальности diff.
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+var cases = []struct {
+	name string
+	a, b string
+}{
+	{"empty", "", ""},
+	{"insert all", "", "one\ntwo\n"},
+	{"delete all", "one\ntwo\n", ""},
+	{"equal", "same\ntext\n", "same\ntext\n"},
+	{"replace middle", "a\nb\nc\n", "a\nx\nc\n"},
+	{"no trailing newline", "a\nb", "a\nb\nc"},
+	{"words", "the quick brown fox jumps", "the slow brown cat jumps high"},
+	{"whitespace change", "one  two\tthree", "one two three\n"},
+	{"reordered", "a b c d e f", "f e d c b a"},
+	{"unicode", "Привет, мир! Как дела?", "Привет, новый мир! Как дела"},
+}
+
+func TestLinesRebuildsBothTexts(t *testing.T) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ops, err := Lines(tc.a, tc.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a, b := rebuild(ops)
+			if a != tc.a || b != tc.b {
+				t.Fatalf("rebuilt %q / %q, want %q / %q", a, b, tc.a, tc.b)
+			}
+		})
+	}
+}
+
+func TestWordsRebuildsBothTexts(t *testing.T) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ops, err := Words(tc.a, tc.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a, b := rebuild(ops)
+			if a != tc.a || b != tc.b {
+				t.Fatalf("rebuilt %q / %q, want %q / %q", a, b, tc.a, tc.b)
+			}
+		})
+	}
+}
+
+func TestOpsAreMerged(t *testing.T) {
+	ops, err := Words("a b c d", "a x y d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(ops); i++ {
+		if ops[i].Type == ops[i-1].Type {
+			t.Fatalf("adjacent %s operations are not merged: %+v", ops[i].Type, ops)
+		}
+	}
+}
+
+func TestRandomEditsAreMinimal(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	words := []string{"a ", "b ", "c ", "d ", "e "}
+	gen := func(n int) string {
+		var sb strings.Builder
+		for i := 0; i < n; i++ {
+			sb.WriteString(words[rnd.Intn(len(words))])
+		}
+		return sb.String()
+	}
+	for i := 0; i < 500; i++ {
+		a, b := gen(rnd.Intn(40)), gen(rnd.Intn(40))
+		ops, err := Words(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ra, rb := rebuild(ops)
+		if ra != a || rb != b {
+			t.Fatalf("rebuilt %q / %q, want %q / %q", ra, rb, a, b)
+		}
+		ta, tb := splitWords(a), splitWords(b)
+		if got, want := changed(ops, splitWords), len(ta)+len(tb)-2*lcs(ta, tb); got != want {
+			t.Fatalf("diff of %q and %q changes %d tokens, minimal is %d", a, b, got, want)
+		}
+	}
+}
+
+func TestLargeRewriteIsRejected(t *testing.T) {
+	var a, b strings.Builder
+	for i := 0; i < MaxTokens; i++ {
+		a.WriteString("old ")
+		b.WriteString("new ")
+	}
+	if _, err := Words(a.String(), b.String()); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("got %v, want ErrTooLarge", err)
+	}
+	// общие начало и конец не считаются: большой текст с локальной правкой сравнивается
+	same := strings.Repeat("word ", MaxTokens)
+	ops, err := Words(same+"old "+same, same+"new "+same)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed(ops, splitWords) != 2 {
+		t.Fatalf("local edit changes %d tokens, want 2", changed(ops, splitWords))
+	}
+}
+
+func TestLargeDiffMemory(t *testing.T) {
+	// тексты по 5000 слов, различается примерно половина: прежняя реализация с копией фронта
+	// на каждом шаге выделяла здесь сотни мегабайт
+	words := []string{"alpha ", "beta ", "gamma ", "delta ", "epsilon ", "zeta ", "eta ", "theta ", "iota ", "kappa "}
+	rnd := rand.New(rand.NewSource(2))
+	var a, b strings.Builder
+	for i := 0; i < 5000; i++ {
+		w := words[rnd.Intn(len(words))]
+		a.WriteString(w)
+		if rnd.Intn(2) == 0 {
+			w = words[rnd.Intn(len(words))]
+		}
+		b.WriteString(w)
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, err := Words(a.String(), b.String()); err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	if n := after.TotalAlloc - before.TotalAlloc; n > 16<<20 {
+		t.Fatalf("diff allocated %d bytes", n)
+	}
+}
@@ -0,0 +1,228 @@
+// Package feed разбирает ленты RSS 2.0 (и RSS 1.0/RDF), Atom и JSON Feed в общий список записей.
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+// ErrUnknownFormat - документ не похож ни на одну из поддерживаемых лент.
+var ErrUnknownFormat = errors.New("not an RSS, Atom or JSON feed")
+
+type Entry struct {
+	URL       string
+	Title     string
+	Published *time.Time
+}
+
+type Feed struct {
+	Format  string
+	Title   string
+	Entries []Entry
+}
+
+// Parse определяет формат по содержимому и возвращает записи со ссылками, разрешенными
+// относительно base (URL самой ленты). Записи без ссылки пропускаются, повторы отбрасываются.
+func Parse(body []byte, base string) (*Feed, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")))
+	var f *Feed
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		f, err = parseJSON(trimmed)
+	} else {
+		f, err = parseXML(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(f.Entries))
+	entries := f.Entries[:0]
+	for _, e := range f.Entries {
+		link := strings.TrimSpace(e.URL)
+		if link == "" {
+			continue
+		}
+		u, err := baseURL.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		e.URL = u.String()
+		if seen[e.URL] {
+			continue
+		}
+		seen[e.URL] = true
+		e.Title = strings.TrimSpace(e.Title)
+		entries = append(entries, e)
+	}
+	f.Entries = entries
+	f.Title = strings.TrimSpace(f.Title)
+	return f, nil
+}
+
+// xmlFeed покрывает все три XML-формата: теги сопоставляются по локальному имени,
+// поэтому пространства имен RDF и Atom не мешают.
+type xmlFeed struct {
+	XMLName xml.Name
+	// RSS 2.0: <rss><channel>...<item>
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	// RSS 1.0: <item> лежат рядом с <channel>
+	Items []rssItem `xml:"item"`
+	// Atom
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+	GUID  struct {
+		Value       string `xml:",chardata"`
+		IsPermaLink string `xml:"isPermaLink,attr"`
+	} `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+	Date    string `xml:"date"` // dc:date
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+func parseXML(body []byte) (*Feed, error) {
+	var doc xmlFeed
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	// ленты нередко объявляют windows-1251 и подобные кодировки
+	dec.CharsetReader = charset.NewReaderLabel
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+	}
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "rss", "rdf":
+		f := &Feed{Format: FormatRSS, Title: doc.Channel.Title}
+		for _, it := range append(doc.Channel.Items, doc.Items...) {
+			link := it.Link
+			// guid без isPermaLink="false" по спецификации - постоянная ссылка на запись
+			if link == "" && it.GUID.IsPermaLink != "false" {
+				link = it.GUID.Value
+			}
+			f.Entries = append(f.Entries, Entry{URL: link, Title: it.Title, Published: parseDate(firstNonEmpty(it.PubDate, it.Date))})
+		}
+		return f, nil
+	case "feed":
+		f := &Feed{Format: FormatAtom, Title: doc.Title}
+		for _, e := range doc.Entries {
+			f.Entries = append(f.Entries, Entry{URL: atomLink(e), Title: e.Title, Published: parseDate(firstNonEmpty(e.Published, e.Updated))})
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("%w: root element <%s>", ErrUnknownFormat, doc.XMLName.Local)
+}
+
+// atomLink выбирает rel="alternate" (или ссылку без rel), предпочитая HTML.
+func atomLink(e atomEntry) string {
+	var link string
+	for _, l := range e.Links {
+		if l.Rel != "" && l.Rel != "alternate" {
+			continue
+		}
+		if l.Type == "" || l.Type == "text/html" {
+			return l.Href
+		}
+		if link == "" {
+			link = l.Href
+		}
+	}
+	return link
+}
+
+type jsonFeed struct {
+	Version string `json:"version"`
+	Title   string `json:"title"`
+	Items   []struct {
+		ID            string `json:"id"`
+		URL           string `json:"url"`
+		ExternalURL   string `json:"external_url"`
+		Title         string `json:"title"`
+		DatePublished string `json:"date_published"`
+		DateModified  string `json:"date_modified"`
+	} `json:"items"`
+}
+
+func parseJSON(body []byte) (*Feed, error) {
+	var doc jsonFeed
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+	}
+	if !strings.Contains(doc.Version, "jsonfeed.org") {
+		return nil, fmt.Errorf("%w: unexpected JSON Feed version %q", ErrUnknownFormat, doc.Version)
+	}
+	f := &Feed{Format: FormatJSON, Title: doc.Title}
+	for _, it := range doc.Items {
+		f.Entries = append(f.Entries, Entry{
+			URL:       firstNonEmpty(it.URL, it.ExternalURL),
+			Title:     it.Title,
+			Published: parseDate(firstNonEmpty(it.DatePublished, it.DateModified)),
+		})
+	}
+	return f, nil
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
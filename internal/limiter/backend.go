@@ -0,0 +1,110 @@
+package limiter
+
+import (
+	"context"
+	"time"
+
+	"ArticleCrawler/internal/db"
+
+	"golang.org/x/time/rate"
+)
+
+// Backend создает ведра токенов для доменов. Ведро живет в состоянии домена
+// и выселяется вместе с ним.
+type Backend interface {
+	Name() string
+	Bucket(domain string, l Limit) Bucket
+}
+
+// Bucket - ведро токенов одного домена.
+type Bucket interface {
+	// Take забирает токен, если он есть, и возвращает 0; иначе - через сколько появится следующий.
+	// l - текущий лимит домена, он меняется между вызовами вместе с переопределениями,
+	// адаптивным замедлением и Crawl-delay.
+	Take(ctx context.Context, l Limit) (time.Duration, error)
+	// Idle - ведро полное, его можно выбросить, не дав домену лишних запросов.
+	Idle(now time.Time) bool
+}
+
+// Memory - ведра в памяти процесса: каждая реплика считает лимиты сама.
+type Memory struct{}
+
+func (Memory) Name() string { return "memory" }
+
+func (Memory) Bucket(_ string, l Limit) Bucket {
+	return &memoryBucket{lim: rate.NewLimiter(rate.Limit(l.RPS), l.Burst)}
+}
+
+type memoryBucket struct {
+	lim *rate.Limiter
+}
+
+func (b *memoryBucket) Take(_ context.Context, l Limit) (time.Duration, error) {
+	b.apply(l)
+	now := time.Now()
+	r := b.lim.ReserveN(now, 1)
+	if !r.OK() {
+		return time.Duration(float64(time.Second) / l.RPS), nil
+	}
+	wait := r.DelayFrom(now)
+	if wait > 0 {
+		r.CancelAt(now)
+	}
+	return wait, nil
+}
+
+func (b *memoryBucket) reserveN(l Limit, n int) *rate.Reservation {
+	b.apply(l)
+	return b.lim.ReserveN(time.Now(), n)
+}
+
+func (b *memoryBucket) apply(l Limit) {
+	if b.lim.Limit() != rate.Limit(l.RPS) {
+		b.lim.SetLimit(rate.Limit(l.RPS))
+	}
+	if b.lim.Burst() != l.Burst {
+		b.lim.SetBurst(l.Burst)
+	}
+}
+
+func (b *memoryBucket) Idle(now time.Time) bool {
+	return b.lim.TokensAt(now) >= float64(b.lim.Burst())
+}
+
+// postgresTimeout - сколько ждать ответа общего хранилища, прежде чем считать его недоступным.
+const postgresTimeout = 2 * time.Second
+
+// Postgres - ведра в таблице rate_limit_buckets, общие для всех реплик с одной БД.
+// Локально ничего не хранится, поэтому такие ведра всегда можно выселить.
+type Postgres struct {
+	repo *db.Repository
+}
+
+func NewPostgres(repo *db.Repository) *Postgres {
+	return &Postgres{repo: repo}
+}
+
+func (p *Postgres) Name() string { return "postgres" }
+
+func (p *Postgres) Bucket(domain string, _ Limit) Bucket {
+	return postgresBucket{repo: p.repo, domain: domain}
+}
+
+// Sweep удаляет из таблицы ведра, простаивающие дольше idle.
+func (p *Postgres) Sweep(ctx context.Context, idle time.Duration) (int64, error) {
+	return p.repo.DeleteIdleRateBuckets(ctx, idle)
+}
+
+type postgresBucket struct {
+	repo   *db.Repository
+	domain string
+}
+
+func (b postgresBucket) Take(ctx context.Context, l Limit) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+	interval := time.Duration(float64(time.Second) / l.RPS)
+	return b.repo.TakeRateToken(ctx, b.domain, interval, time.Duration(l.Burst)*interval)
+}
+
+func (postgresBucket) Idle(time.Time) bool { return true }
@@ -1,16 +1,76 @@
 package limiter
 
 import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/time/rate"
 )
 
+// Limit - лимит домена: запросов в секунду и размер всплеска.
+type Limit struct {
+	RPS   float64
+	Burst int
+}
+
+// Adaptive настраивает автоматическое замедление домена по ответам сервера.
+type Adaptive struct {
+	Enabled bool
+	// MinRPS - ниже этого адаптивное замедление не опускает (Crawl-delay и Retry-After могут).
+	MinRPS float64
+	// QuietPeriod - сколько должно пройти без замедлений до очередного шага восстановления.
+	QuietPeriod time.Duration
+	// LatencyFactor - замедляться, когда сглаженная задержка ответа выросла во столько раз
+	// относительно лучшей замеченной.
+	LatencyFactor float64
+}
+
+const (
+	throttleFactor = 0.5  // на 429/503
+	latencyFactor  = 0.75 // на рост задержки
+	recoverFactor  = 1.5  // шаг восстановления
+	latencyAlpha   = 0.2  // вес нового замера в сглаженной задержке
+	// задержки меньше этой не считаются признаком перегрузки, как бы они ни выросли
+	latencyFloor = 250 * time.Millisecond
+	// через сколько повторить, если общее хранилище ведер не ответило
+	backendRetry = time.Second
+)
+
+type domainState struct {
+	mu           sync.Mutex
+	bucket       Bucket
+	local        *memoryBucket // запасное ведро, если общее хранилище недоступно; в режиме memory это и есть bucket
+	base         Limit
+	eff          Limit  // итоговый лимит, см. applyLocked
+	pattern      string // ключ переопределения, под который попал домен; "" - лимит по умолчанию
+	crawlDelay   time.Duration
+	factor       float64
+	blockedUntil time.Time
+	lastChange   time.Time
+	latency      time.Duration
+	bestLatency  time.Duration
+}
+
 type DomainLimiter struct {
-	m          sync.Map // здесь хранится [string]*rate.Limiter
+	domains    *store
 	defaultRPS int
 	burst      int
+
+	mu        sync.RWMutex
+	overrides map[string]Limit // "example.com" или "*.example.com" (только поддомены)
+	adaptive  Adaptive
+	backend   Backend
+	failOpen  bool
+
+	backendDown   atomic.Bool
+	backendErrors atomic.Int64
 }
 
 func NewDomainLimiter(defaultRPS, burst int) *DomainLimiter {
@@ -20,26 +80,462 @@ func NewDomainLimiter(defaultRPS, burst int) *DomainLimiter {
 	if burst <= 0 {
 		burst = 5
 	}
-	return &DomainLimiter{
+	d := &DomainLimiter{
 		defaultRPS: defaultRPS,
 		burst:      burst,
+		backend:    Memory{},
 	}
+	d.domains = newStore(d.newState)
+	return d
 }
 
-func (d *DomainLimiter) getLimiter(domain string) *rate.Limiter {
-	if v, ok := d.m.Load(domain); ok {
-		return v.(*rate.Limiter)
+// SetBackend меняет хранилище ведер токенов, уже известные домены начинают с новых ведер.
+// failOpen определяет, что делать, когда хранилище не отвечает: true - считать по локальным
+// ведрам этой реплики, false - не пускать запросы, пока хранилище не вернется.
+func (d *DomainLimiter) SetBackend(b Backend, failOpen bool) {
+	if b == nil {
+		b = Memory{}
+	}
+	d.mu.Lock()
+	d.backend, d.failOpen = b, failOpen
+	d.mu.Unlock()
+	d.backendDown.Store(false)
+	d.domains.rangeAll(func(domain string, st *domainState) {
+		st.mu.Lock()
+		st.bucket, st.local = d.buckets(b, domain, st.eff)
+		st.mu.Unlock()
+	})
+}
+
+func (d *DomainLimiter) buckets(b Backend, domain string, l Limit) (Bucket, *memoryBucket) {
+	local := Memory{}.Bucket(domain, l).(*memoryBucket)
+	if _, ok := b.(Memory); ok {
+		return local, local
+	}
+	return b.Bucket(domain, l), local
+}
+
+func (d *DomainLimiter) backendConfig() (Backend, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.backend, d.failOpen
+}
+
+// SetEviction ограничивает число отслеживаемых доменов (0 - без ограничения)
+// и время простоя, после которого домен забывается (0 - не забывать).
+// Сдерживаемые домены не выселяются ни по одному из условий.
+func (d *DomainLimiter) SetEviction(maxDomains int, idleTTL time.Duration) {
+	if maxDomains < 0 {
+		maxDomains = 0
+	}
+	if idleTTL < 0 {
+		idleTTL = 0
+	}
+	d.domains.maxDomains.Store(int64(maxDomains))
+	d.domains.idleTTL.Store(int64(idleTTL))
+}
+
+// bucketSweeper - хранилище, из которого нужно удалять давно полные ведра (Postgres).
+type bucketSweeper interface {
+	Sweep(ctx context.Context, idle time.Duration) (int64, error)
+}
+
+// Run периодически выселяет простаивающие домены, пока ctx не отменен.
+func (d *DomainLimiter) Run(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.domains.sweep(time.Now())
+			d.sweepBackend(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *DomainLimiter) sweepBackend(ctx context.Context) {
+	b, _ := d.backendConfig()
+	sw, ok := b.(bucketSweeper)
+	if !ok {
+		return
+	}
+	idle := time.Duration(d.domains.idleTTL.Load())
+	if idle <= 0 {
+		idle = time.Hour
+	}
+	if _, err := sw.Sweep(ctx, idle); err != nil && ctx.Err() == nil {
+		log.Printf("[limiter] sweep %s buckets: %v", b.Name(), err)
 	}
-	l := rate.NewLimiter(rate.Limit(d.defaultRPS), d.burst)
-	actual, _ := d.m.LoadOrStore(domain, l)
-	return actual.(*rate.Limiter)
 }
 
-func (d *DomainLimiter) Allow(domain string) bool {
-	l := d.getLimiter(domain)
-	return l.Allow()
+// StoreStats - сколько доменов отслеживается сейчас и сколько было выселено с момента запуска.
+type StoreStats struct {
+	Tracked           int
+	MaxDomains        int
+	IdleEvictions     int64
+	CapacityEvictions int64
+	Backend           string
+	BackendErrors     int64
+}
+
+func (d *DomainLimiter) StoreStats() StoreStats {
+	b, _ := d.backendConfig()
+	return StoreStats{
+		Tracked:           int(d.domains.count.Load()),
+		MaxDomains:        int(d.domains.maxDomains.Load()),
+		IdleEvictions:     d.domains.idleEvictions.Load(),
+		CapacityEvictions: d.domains.capacityEvictions.Load(),
+		Backend:           b.Name(),
+		BackendErrors:     d.backendErrors.Load(),
+	}
+}
+
+// SetOverrides заменяет переопределения лимитов и применяет их к уже известным доменам.
+// Ключ "example.com" действует только на сам хост, "*.example.com" - на все его поддомены.
+func (d *DomainLimiter) SetOverrides(overrides map[string]Limit) {
+	m := make(map[string]Limit, len(overrides))
+	for k, v := range overrides {
+		m[strings.ToLower(strings.TrimSpace(k))] = v
+	}
+	d.mu.Lock()
+	d.overrides = m
+	d.mu.Unlock()
+	d.reapply()
+}
+
+// SetDefault меняет лимит по умолчанию для доменов без переопределений, включая уже известные.
+func (d *DomainLimiter) SetDefault(defaultRPS, burst int) {
+	if defaultRPS <= 0 {
+		defaultRPS = 2
+	}
+	if burst <= 0 {
+		burst = 5
+	}
+	d.mu.Lock()
+	d.defaultRPS, d.burst = defaultRPS, burst
+	d.mu.Unlock()
+	d.reapply()
+}
+
+// reapply пересчитывает лимиты известных доменов после изменения настроек.
+func (d *DomainLimiter) reapply() {
+	minRPS := d.minRPS()
+	d.domains.rangeAll(func(domain string, st *domainState) {
+		base, pattern := d.limitFor(domain)
+		st.mu.Lock()
+		st.base, st.pattern = base, pattern
+		st.applyLocked(minRPS)
+		st.mu.Unlock()
+	})
+}
+
+func (d *DomainLimiter) SetAdaptive(a Adaptive) {
+	if a.QuietPeriod <= 0 {
+		a.QuietPeriod = time.Minute
+	}
+	if a.LatencyFactor <= 1 {
+		a.LatencyFactor = 3
+	}
+	d.mu.Lock()
+	d.adaptive = a
+	d.mu.Unlock()
+	d.reapply()
+}
+
+func (d *DomainLimiter) adaptiveConfig() Adaptive {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.adaptive
+}
+
+func (d *DomainLimiter) minRPS() float64 {
+	return d.adaptiveConfig().MinRPS
+}
+
+// limitFor ищет переопределение сначала для самого хоста, затем "*." для каждого родительского домена.
+func (d *DomainLimiter) limitFor(domain string) (Limit, string) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	domain = strings.ToLower(domain)
+	if l, ok := d.overrides[domain]; ok {
+		return d.normalize(l), domain
+	}
+	for h := domain; ; {
+		i := strings.IndexByte(h, '.')
+		if i < 0 {
+			break
+		}
+		h = h[i+1:]
+		if l, ok := d.overrides["*."+h]; ok {
+			return d.normalize(l), "*." + h
+		}
+	}
+	return Limit{RPS: float64(d.defaultRPS), Burst: d.burst}, ""
+}
+
+func (d *DomainLimiter) normalize(l Limit) Limit {
+	if l.RPS <= 0 {
+		l.RPS = float64(d.defaultRPS)
+	}
+	if l.Burst <= 0 {
+		l.Burst = d.burst
+		if l.RPS < 1 {
+			l.Burst = 1
+		}
+	}
+	return l
+}
+
+func (d *DomainLimiter) getState(domain string) *domainState {
+	return d.domains.get(domain)
+}
+
+func (d *DomainLimiter) newState(domain string) *domainState {
+	base, pattern := d.limitFor(domain)
+	b, _ := d.backendConfig()
+	st := &domainState{
+		base:    base,
+		eff:     base,
+		pattern: pattern,
+		factor:  1,
+	}
+	st.bucket, st.local = d.buckets(b, domain, base)
+	return st
+}
+
+// busyLocked - домен еще сдерживается: закрыт по Retry-After, адаптивно замедлен
+// или не накопил полный burst. Такое состояние нельзя выбрасывать.
+func (s *domainState) busyLocked(now time.Time) bool {
+	return now.Before(s.blockedUntil) || s.factor < 1 || !s.bucket.Idle(now) || !s.local.Idle(now)
+}
+
+// applyLocked пересчитывает итоговый лимит: настроенный, умноженный на адаптивный коэффициент,
+// но не быстрее Crawl-delay.
+func (s *domainState) applyLocked(minRPS float64) {
+	r := s.base.RPS * s.factor
+	if r < minRPS && s.factor < 1 {
+		r = minRPS
+		if r > s.base.RPS {
+			r = s.base.RPS
+		}
+	}
+	burst := s.base.Burst
+	if s.factor < 1 {
+		burst = 1
+	}
+	if s.crawlDelay > 0 {
+		if cd := 1 / s.crawlDelay.Seconds(); cd < r {
+			r = cd
+			burst = 1
+		}
+	}
+	s.eff = Limit{RPS: r, Burst: burst}
+}
+
+// Allow забирает токен домена, если он есть.
+func (d *DomainLimiter) Allow(ctx context.Context, domain string) bool {
+	return d.Take(ctx, domain) == 0
+}
+
+// Take забирает токен домена и возвращает 0, а если токена нет - через сколько стоит
+// попробовать снова: до конца Retry-After или до следующего токена в ведре.
+func (d *DomainLimiter) Take(ctx context.Context, domain string) time.Duration {
+	st := d.getState(domain)
+	st.mu.Lock()
+	blocked := time.Until(st.blockedUntil)
+	l, bucket, local := st.eff, st.bucket, st.local
+	st.mu.Unlock()
+	if blocked > 0 {
+		return blocked
+	}
+	wait, err := bucket.Take(ctx, l)
+	if err == nil {
+		if d.backendDown.CompareAndSwap(true, false) {
+			log.Printf("[limiter] rate limit backend is back")
+		}
+		return wait
+	}
+	if ctx.Err() != nil {
+		return backendRetry
+	}
+	d.backendErrors.Add(1)
+	_, failOpen := d.backendConfig()
+	if d.backendDown.CompareAndSwap(false, true) {
+		mode := "denying requests until it recovers"
+		if failOpen {
+			mode = "falling back to local limits"
+		}
+		log.Printf("[limiter] rate limit backend unavailable, %s: %v", mode, err)
+	}
+	if !failOpen {
+		return backendRetry
+	}
+	wait, _ = local.Take(ctx, l)
+	return wait
+}
+
+// BlockedFor - сколько еще домен закрыт по Retry-After.
+func (d *DomainLimiter) BlockedFor(domain string) time.Duration {
+	st := d.getState(domain)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if wait := time.Until(st.blockedUntil); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// SetCrawlDelay ограничивает домен одним запросом за delay, если это медленнее текущего лимита.
+func (d *DomainLimiter) SetCrawlDelay(domain string, delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+	st := d.getState(domain)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.crawlDelay == delay {
+		return
+	}
+	st.crawlDelay = delay
+	st.applyLocked(d.minRPS())
+}
+
+// Observe учитывает ответ домена. Retry-After соблюдается всегда; замедление на 429/503
+// и рост задержки, а также постепенное восстановление после QuietPeriod - только в адаптивном режиме.
+// status == 0 означает ошибку транспорта, такой замер пропускается.
+func (d *DomainLimiter) Observe(domain string, status int, latency, retryAfter time.Duration) {
+	st := d.getState(domain)
+	a := d.adaptiveConfig()
+	now := time.Now()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if retryAfter > 0 {
+		if until := now.Add(retryAfter); until.After(st.blockedUntil) {
+			st.blockedUntil = until
+		}
+	}
+	if !a.Enabled || status == 0 {
+		return
+	}
+	if status == 429 || status == 503 {
+		st.slowDownLocked(now, throttleFactor, a.MinRPS)
+		return
+	}
+	if latency > 0 {
+		if st.latency == 0 {
+			st.latency = latency
+		} else {
+			st.latency = time.Duration(latencyAlpha*float64(latency) + (1-latencyAlpha)*float64(st.latency))
+		}
+		if st.bestLatency == 0 || st.latency < st.bestLatency {
+			st.bestLatency = st.latency
+		}
+		if st.latency > latencyFloor && float64(st.latency) > a.LatencyFactor*float64(st.bestLatency) &&
+			now.Sub(st.lastChange) >= a.QuietPeriod/4 {
+			st.slowDownLocked(now, latencyFactor, a.MinRPS)
+			return
+		}
+	}
+	if st.factor < 1 && status < 500 && now.Sub(st.lastChange) >= a.QuietPeriod {
+		st.factor *= recoverFactor
+		if st.factor > 1 {
+			st.factor = 1
+		}
+		st.lastChange = now
+		st.applyLocked(a.MinRPS)
+	}
+}
+
+// ParseRetryAfter разбирает заголовок Retry-After: число секунд или HTTP-дата.
+// Для пустого, некорректного или уже прошедшего значения возвращает 0.
+func ParseRetryAfter(h string, now time.Time) time.Duration {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return 0
+	}
+	if sec, err := strconv.Atoi(h); err == nil {
+		if sec <= 0 {
+			return 0
+		}
+		return time.Duration(sec) * time.Second
+	}
+	t, err := http.ParseTime(h)
+	if err != nil {
+		return 0
+	}
+	if d := t.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+func (s *domainState) slowDownLocked(now time.Time, mult, minRPS float64) {
+	s.factor *= mult
+	if minRPS > 0 && s.base.RPS*s.factor < minRPS {
+		s.factor = minRPS / s.base.RPS
+		if s.factor > 1 {
+			s.factor = 1
+		}
+	}
+	s.lastChange = now
+	s.applyLocked(minRPS)
 }
 
 func (d *DomainLimiter) ReserveN(domain string, n int) *rate.Reservation {
-	return d.getLimiter(domain).ReserveN(time.Now(), n)
+	st := d.getState(domain)
+	st.mu.Lock()
+	l := st.eff
+	st.mu.Unlock()
+	// резервирование возможно только в локальном ведре
+	return st.local.reserveN(l, n)
+}
+
+// DomainStats - текущее состояние лимита домена.
+type DomainStats struct {
+	Domain        string
+	Pattern       string
+	ConfiguredRPS float64
+	EffectiveRPS  float64
+	Burst         int
+	Factor        float64
+	CrawlDelay    time.Duration
+	BlockedUntil  time.Time
+	Latency       time.Duration
+}
+
+func (s *domainState) stats(domain string) DomainStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return DomainStats{
+		Domain:        domain,
+		Pattern:       s.pattern,
+		ConfiguredRPS: s.base.RPS,
+		EffectiveRPS:  s.eff.RPS,
+		Burst:         s.eff.Burst,
+		Factor:        s.factor,
+		CrawlDelay:    s.crawlDelay,
+		BlockedUntil:  s.blockedUntil,
+		Latency:       s.latency,
+	}
+}
+
+// Stats возвращает состояние известных доменов, отсортированное по имени.
+// Если domain не пуст - только его, даже если к нему еще не было запросов.
+func (d *DomainLimiter) Stats(domain string) []DomainStats {
+	if domain != "" {
+		domain = strings.ToLower(domain)
+		if st, ok := d.domains.load(domain); ok {
+			return []DomainStats{st.stats(domain)}
+		}
+		base, pattern := d.limitFor(domain)
+		return []DomainStats{{Domain: domain, Pattern: pattern, ConfiguredRPS: base.RPS, EffectiveRPS: base.RPS, Burst: base.Burst, Factor: 1}}
+	}
+	var res []DomainStats
+	d.domains.rangeAll(func(domain string, st *domainState) {
+		res = append(res, st.stats(domain))
+	})
+	sort.Slice(res, func(i, j int) bool { return res[i].Domain < res[j].Domain })
+	return res
 }
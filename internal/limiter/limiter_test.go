@@ -0,0 +1,29 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTakeReturnsWaitUntilNextToken(t *testing.T) {
+	l := NewDomainLimiter(10, 1)
+	ctx := context.Background()
+	if wait := l.Take(ctx, "example.com"); wait != 0 {
+		t.Fatalf("first request waits %s", wait)
+	}
+	wait := l.Take(ctx, "example.com")
+	if wait <= 50*time.Millisecond || wait > 100*time.Millisecond {
+		t.Fatalf("wait for the next token is %s, want about 100ms", wait)
+	}
+	// отказ не расходует токен: после ожидания запрос проходит
+	time.Sleep(wait)
+	if wait := l.Take(ctx, "example.com"); wait != 0 {
+		t.Fatalf("request after the wait is denied for %s more", wait)
+	}
+
+	l.Observe("blocked.example", 503, 0, time.Hour)
+	if wait := l.Take(ctx, "blocked.example"); wait < 59*time.Minute {
+		t.Fatalf("wait for a domain under Retry-After is %s", wait)
+	}
+}
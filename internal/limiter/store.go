@@ -0,0 +1,162 @@
+package limiter
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	storeShards = 64
+	// при переполнении с хвоста шарда просматривается не больше стольких кандидатов
+	evictScan = 8
+	// как часто Run выселяет домены, простаивающие дольше idleTTL
+	sweepInterval = 30 * time.Second
+)
+
+type storeEntry struct {
+	domain   string
+	st       *domainState
+	lastUsed time.Time
+}
+
+// shard - LRU-список доменов: спереди недавно использованные, сзади кандидаты на выселение.
+type shard struct {
+	mu    sync.Mutex
+	items map[string]*list.Element
+	lru   *list.List
+}
+
+// store хранит состояние доменов с ограничением по числу и времени простоя.
+// Домен, который еще сдерживается (см. domainState.busyLocked), не выселяется никогда:
+// иначе при следующем запросе он получил бы свежий лимит и полный burst.
+// Поэтому maxDomains - мягкая граница, ее можно превысить, если все кандидаты заняты.
+type store struct {
+	shards   [storeShards]shard
+	newState func(domain string) *domainState
+
+	maxDomains atomic.Int64 // 0 - без ограничения
+	idleTTL    atomic.Int64 // 0 - не выселять по простою
+
+	count             atomic.Int64
+	idleEvictions     atomic.Int64
+	capacityEvictions atomic.Int64
+}
+
+func newStore(newState func(domain string) *domainState) *store {
+	s := &store{newState: newState}
+	for i := range s.shards {
+		s.shards[i].items = make(map[string]*list.Element)
+		s.shards[i].lru = list.New()
+	}
+	return s
+}
+
+// shardFor - FNV-1a по имени домена, без аллокаций hash.Hash.
+func (s *store) shardFor(domain string) *shard {
+	h := uint32(2166136261)
+	for i := 0; i < len(domain); i++ {
+		h ^= uint32(domain[i])
+		h *= 16777619
+	}
+	return &s.shards[h%storeShards]
+}
+
+// get возвращает состояние домена, создавая его при необходимости, и отмечает его использование.
+func (s *store) get(domain string) *domainState {
+	now := time.Now()
+	sh := s.shardFor(domain)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if el, ok := sh.items[domain]; ok {
+		e := el.Value.(*storeEntry)
+		e.lastUsed = now
+		sh.lru.MoveToFront(el)
+		return e.st
+	}
+	e := &storeEntry{domain: domain, st: s.newState(domain), lastUsed: now}
+	sh.items[domain] = sh.lru.PushFront(e)
+	if max := s.maxDomains.Load(); s.count.Add(1) > max && max > 0 {
+		s.evictLocked(sh, now)
+	}
+	return e.st
+}
+
+// load ищет домен без создания и без отметки об использовании.
+func (s *store) load(domain string) (*domainState, bool) {
+	sh := s.shardFor(domain)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if el, ok := sh.items[domain]; ok {
+		return el.Value.(*storeEntry).st, true
+	}
+	return nil, false
+}
+
+// evictLocked освобождает одно место в шарде, выселяя самый давно использованный свободный домен.
+// Только что добавленный домен стоит первым и не рассматривается.
+func (s *store) evictLocked(sh *shard, now time.Time) {
+	el := sh.lru.Back()
+	for i := 0; i < evictScan && el != nil && el != sh.lru.Front(); i++ {
+		prev := el.Prev()
+		if s.removeIfIdleLocked(sh, el, now) {
+			s.capacityEvictions.Add(1)
+			return
+		}
+		el = prev
+	}
+}
+
+func (s *store) removeIfIdleLocked(sh *shard, el *list.Element, now time.Time) bool {
+	e := el.Value.(*storeEntry)
+	e.st.mu.Lock()
+	busy := e.st.busyLocked(now)
+	e.st.mu.Unlock()
+	if busy {
+		return false
+	}
+	sh.lru.Remove(el)
+	delete(sh.items, e.domain)
+	s.count.Add(-1)
+	return true
+}
+
+// sweep выселяет домены, к которым не обращались дольше idleTTL.
+func (s *store) sweep(now time.Time) {
+	ttl := time.Duration(s.idleTTL.Load())
+	if ttl <= 0 {
+		return
+	}
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		for el := sh.lru.Back(); el != nil; {
+			prev := el.Prev()
+			if now.Sub(el.Value.(*storeEntry).lastUsed) < ttl {
+				break
+			}
+			if s.removeIfIdleLocked(sh, el, now) {
+				s.idleEvictions.Add(1)
+			}
+			el = prev
+		}
+		sh.mu.Unlock()
+	}
+}
+
+// rangeAll вызывает fn для каждого домена. fn выполняется без блокировки шарда.
+func (s *store) rangeAll(fn func(domain string, st *domainState)) {
+	var entries []*storeEntry
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		for el := sh.lru.Front(); el != nil; el = el.Next() {
+			entries = append(entries, el.Value.(*storeEntry))
+		}
+		sh.mu.Unlock()
+	}
+	for _, e := range entries {
+		fn(e.domain, e.st)
+	}
+}
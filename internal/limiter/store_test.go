@@ -0,0 +1,177 @@
+package limiter
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// throttled заводит три домена, которые сдерживаются каждый по своей причине.
+func throttled(t *testing.T, l *DomainLimiter) {
+	t.Helper()
+	ctx := context.Background()
+	l.SetAdaptive(Adaptive{Enabled: true, MinRPS: 0.1, LatencyFactor: 3, QuietPeriod: time.Hour})
+	l.Observe("blocked.example", 200, 0, time.Hour)
+	l.Observe("slow.example", 429, 0, 0)
+	for i := 0; i < 5; i++ {
+		if !l.Allow(ctx, "drained.example") {
+			t.Fatalf("burst of drained.example ended after %d requests", i)
+		}
+	}
+}
+
+func checkThrottled(t *testing.T, l *DomainLimiter) {
+	t.Helper()
+	if l.BlockedFor("blocked.example") <= 0 {
+		t.Error("Retry-After of blocked.example is forgotten")
+	}
+	if f := l.Stats("slow.example")[0].Factor; f >= 1 {
+		t.Errorf("slow.example factor is %v, adaptive slowdown is forgotten", f)
+	}
+	if l.Allow(context.Background(), "drained.example") {
+		t.Error("drained.example got a fresh burst")
+	}
+}
+
+func TestCapacityEvictionKeepsThrottledDomains(t *testing.T) {
+	l := NewDomainLimiter(2, 5)
+	l.SetEviction(16, 0)
+	throttled(t, l)
+	// поток новых доменов без запросов: они свободны и выселяются вместо сдерживаемых
+	for i := 0; i < 5000; i++ {
+		l.BlockedFor("site" + strconv.Itoa(i) + ".example.org")
+	}
+	st := l.StoreStats()
+	if st.CapacityEvictions == 0 {
+		t.Fatal("no domain was evicted")
+	}
+	if st.Tracked > 16+storeShards {
+		t.Errorf("tracked %d domains with max_domains 16", st.Tracked)
+	}
+	checkThrottled(t, l)
+}
+
+func TestCapacityEvictionWhenAllBusy(t *testing.T) {
+	// все кандидаты сдерживаются: граница превышается, но ни один домен не теряет состояние
+	l := NewDomainLimiter(2, 1)
+	l.SetEviction(4, 0)
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		l.Allow(ctx, "busy"+strconv.Itoa(i)+".example")
+	}
+	if st := l.StoreStats(); st.CapacityEvictions != 0 || st.Tracked != 100 {
+		t.Fatalf("tracked %d, evicted %d; want all 100 kept", st.Tracked, st.CapacityEvictions)
+	}
+	for i := 0; i < 100; i++ {
+		if l.Allow(ctx, "busy"+strconv.Itoa(i)+".example") {
+			t.Fatalf("busy%d.example got a fresh burst", i)
+		}
+	}
+}
+
+func TestIdleSweepKeepsThrottledDomains(t *testing.T) {
+	l := NewDomainLimiter(2, 5)
+	l.SetEviction(0, time.Second)
+	throttled(t, l)
+	l.BlockedFor("idle.example")
+	// через минуту ведро drained.example снова полное, а Retry-After и замедление еще действуют
+	l.domains.sweep(time.Now().Add(time.Minute))
+	if _, ok := l.domains.load("idle.example"); ok {
+		t.Error("idle domain is not swept")
+	}
+	for _, d := range []string{"blocked.example", "slow.example"} {
+		if _, ok := l.domains.load(d); !ok {
+			t.Errorf("%s is swept while throttled", d)
+		}
+	}
+	if l.BlockedFor("blocked.example") <= 0 {
+		t.Error("Retry-After of blocked.example is forgotten")
+	}
+	if f := l.Stats("slow.example")[0].Factor; f >= 1 {
+		t.Errorf("slow.example factor is %v, adaptive slowdown is forgotten", f)
+	}
+}
+
+// syncMapLimiter - лимитер в том виде, в каком он был до LRU-хранилища: sync.Map с одним
+// *rate.Limiter на хост навсегда.
+type syncMapLimiter struct {
+	m     sync.Map
+	rps   int
+	burst int
+}
+
+func (d *syncMapLimiter) Allow(_ context.Context, domain string) bool {
+	if v, ok := d.m.Load(domain); ok {
+		return v.(*rate.Limiter).Allow()
+	}
+	v, _ := d.m.LoadOrStore(domain, rate.NewLimiter(rate.Limit(d.rps), d.burst))
+	return v.(*rate.Limiter).Allow()
+}
+
+func (d *syncMapLimiter) count() int {
+	n := 0
+	d.m.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+type allower interface {
+	Allow(ctx context.Context, domain string) bool
+}
+
+// Нагрузка похожа на обход со сбором ссылок: 70% вызовов идут в 1000 горячих доменов,
+// остальные - каждый раз в новый хост.
+// go test ./internal/limiter -run '^$' -bench Store -benchtime 4000000x
+func BenchmarkStore(b *testing.B) {
+	const hot = 1000
+	hotDomains := make([]string, hot)
+	for i := range hotDomains {
+		hotDomains[i] = "hot" + strconv.Itoa(i) + ".example.com"
+	}
+	runs := []struct {
+		name    string
+		new     func() allower
+		tracked func(l allower) int
+	}{
+		{"sync.Map", func() allower {
+			return &syncMapLimiter{rps: 2, burst: 5}
+		}, func(l allower) int { return l.(*syncMapLimiter).count() }},
+		{"LRU", func() allower {
+			return NewDomainLimiter(2, 5)
+		}, func(l allower) int { return l.(*DomainLimiter).StoreStats().Tracked }},
+		{"LRU-max100000", func() allower {
+			l := NewDomainLimiter(2, 5)
+			l.SetEviction(100000, 0)
+			return l
+		}, func(l allower) int { return l.(*DomainLimiter).StoreStats().Tracked }},
+	}
+	for _, r := range runs {
+		b.Run(r.name, func(b *testing.B) {
+			l := r.new()
+			ctx := context.Background()
+			var next, seed atomic.Int64
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				rnd := rand.New(rand.NewSource(seed.Add(1)))
+				for pb.Next() {
+					if rnd.Intn(10) < 7 {
+						l.Allow(ctx, hotDomains[rnd.Intn(hot)])
+					} else {
+						l.Allow(ctx, "site"+strconv.FormatInt(next.Add(1), 10)+".example.org")
+					}
+				}
+			})
+			b.StopTimer()
+			b.ReportMetric(float64(r.tracked(l)), "domains")
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Package metrics описывает Prometheus-метрики сервиса. Все метрики регистрируются
+// в реестре по умолчанию и отдаются через /metrics.
+package metrics
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "crawler"
+
+var (
+	Submissions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "submissions_total",
+		Help:      "URLs put into the job queue, by source (api, recrawl, crawl, feed, sitemap).",
+	}, []string{"source"})
+
+	JobsFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_finished_total",
+		Help:      "Jobs that reached a final state.",
+	}, []string{"state"})
+
+	FetchAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_attempts_total",
+		Help:      "HTTP attempts by domain and status code (\"error\" for transport errors).",
+	}, []string{"domain", "code"})
+
+	FetchRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_retries_total",
+		Help:      "Repeated HTTP attempts after a failed one.",
+	}, []string{"domain"})
+
+	StageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "stage_duration_seconds",
+		Help:      "Time spent handling one item in a pipeline stage.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"stage"})
+
+	Dropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dropped_total",
+		Help:      "Results a pipeline stage could not hand to the next one.",
+	}, []string{"stage"})
+
+	SourcePolls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_polls_total",
+		Help:      "Feed polls by result (ok, not_modified, error).",
+	}, []string{"result"})
+
+	HubSubscribers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "hub_subscribers",
+		Help:      "Active article event subscribers.",
+	})
+
+	HubSlowSkips = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hub_slow_subscriber_skips_total",
+		Help:      "Events not delivered to a subscriber because its buffer was full.",
+	})
+
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook delivery attempts by result (ok, error).",
+	}, []string{"result"})
+
+	HubCatchUps = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hub_subscriber_catch_ups_total",
+		Help:      "Times a subscriber fell behind live delivery and switched to reading the event log.",
+	})
+
+	HubPublishDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hub_publish_dropped_total",
+		Help:      "Events dropped because the hub publish channel was full.",
+	})
+)
+
+// RegisterChannel публикует текущую заполненность и емкость канала между этапами.
+func RegisterChannel(name string, length, capacity func() int) {
+	labels := prometheus.Labels{"channel": name}
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "channel_depth",
+		Help:        "Items waiting in a channel between pipeline stages.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(length()) })
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "channel_capacity",
+		Help:        "Buffer size of a channel between pipeline stages.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(capacity()) })
+}
+
+// RegisterLimiter публикует число доменов, которые помнит DomainLimiter, выселения по причинам
+// и ошибки общего хранилища лимитов.
+func RegisterLimiter(tracked func() int, evictions func(reason string) int64, backendErrors func() int64) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "limiter_domains",
+		Help:      "Domains currently tracked by the rate limiter.",
+	}, func() float64 { return float64(tracked()) })
+	for _, reason := range []string{"idle", "capacity"} {
+		reason := reason
+		promauto.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "limiter_evictions_total",
+			Help:        "Domains evicted from the rate limiter, by reason (idle, capacity).",
+			ConstLabels: prometheus.Labels{"reason": reason},
+		}, func() float64 { return float64(evictions(reason)) })
+	}
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "limiter_backend_errors_total",
+		Help:      "Failed token requests to the shared rate limit backend.",
+	}, func() float64 { return float64(backendErrors()) })
+}
+
+// RegisterDBPool публикует статистику пула соединений pgx.
+func RegisterDBPool(stat func() *pgxpool.Stat) {
+	prometheus.MustRegister(&poolCollector{stat: stat})
+}
+
+type poolCollector struct {
+	stat func() *pgxpool.Stat
+}
+
+var (
+	poolAcquired       = prometheus.NewDesc(namespace+"_db_pool_acquired_conns", "Connections currently in use.", nil, nil)
+	poolIdle           = prometheus.NewDesc(namespace+"_db_pool_idle_conns", "Idle connections.", nil, nil)
+	poolTotal          = prometheus.NewDesc(namespace+"_db_pool_total_conns", "All connections, including ones being opened.", nil, nil)
+	poolMax            = prometheus.NewDesc(namespace+"_db_pool_max_conns", "Maximum pool size.", nil, nil)
+	poolAcquires       = prometheus.NewDesc(namespace+"_db_pool_acquires_total", "Successful connection acquires.", nil, nil)
+	poolEmptyAcquires  = prometheus.NewDesc(namespace+"_db_pool_empty_acquires_total", "Acquires that had to wait for a connection.", nil, nil)
+	poolCancelAcquires = prometheus.NewDesc(namespace+"_db_pool_canceled_acquires_total", "Acquires canceled by context.", nil, nil)
+	poolAcquireSeconds = prometheus.NewDesc(namespace+"_db_pool_acquire_seconds_total", "Total time spent acquiring connections.", nil, nil)
+)
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{poolAcquired, poolIdle, poolTotal, poolMax, poolAcquires, poolEmptyAcquires, poolCancelAcquires, poolAcquireSeconds} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotal, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMax, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCancelAcquires, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireSeconds, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
@@ -0,0 +1,332 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/metrics"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Области рекурсивного обхода: какие найденные ссылки считаются частью обхода.
+const (
+	ScopeHost   = "host"   // тот же хост, что у затравки
+	ScopeDomain = "domain" // домен затравки (без www.) и все его поддомены
+	ScopePrefix = "prefix" // URL начинается с каталога затравки
+)
+
+const (
+	DefaultCrawlDepth = 2
+	DefaultCrawlPages = 1000
+	// со страницы берется не больше стольких ссылок, остальные отбрасываются
+	maxLinksPerPage = 1000
+)
+
+// ErrCrawlOptions - параметры обхода некорректны, повтор с теми же параметрами не поможет.
+var ErrCrawlOptions = errors.New("invalid crawl options")
+
+// CrawlRef связывает задачу с рекурсивным обходом. ID == 0 - обычная задача без обхода.
+type CrawlRef struct {
+	ID    int64
+	Depth int
+}
+
+// CrawlOptions - параметры обхода. Нулевые MaxDepth и MaxPages заменяются значениями по умолчанию.
+// Include и Exclude - регулярные выражения по полному URL: ссылка должна подойти хотя бы
+// под один Include (если они заданы) и ни под один Exclude. Затравка под них не проверяется.
+type CrawlOptions struct {
+	MaxDepth int
+	MaxPages int
+	Scope    string
+	Include  []string
+	Exclude  []string
+}
+
+// crawlScope - скомпилированные правила одного обхода.
+type crawlScope struct {
+	maxDepth int
+	scope    string
+	host     string
+	prefix   string
+	include  []*regexp.Regexp
+	exclude  []*regexp.Regexp
+}
+
+func newCrawlScope(seed string, scope string, maxDepth int, include, exclude []string) (*crawlScope, error) {
+	u, err := url.Parse(seed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: seed url: %v", ErrCrawlOptions, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: seed url must be absolute http(s), got %q", ErrCrawlOptions, seed)
+	}
+	s := &crawlScope{maxDepth: maxDepth, scope: scope, host: strings.ToLower(u.Hostname())}
+	switch scope {
+	case ScopeHost:
+	case ScopeDomain:
+		s.host = strings.TrimPrefix(s.host, "www.")
+	case ScopePrefix:
+		p := *u
+		p.RawQuery, p.Fragment = "", ""
+		if i := strings.LastIndexByte(p.Path, '/'); i >= 0 {
+			p.Path = p.Path[:i+1]
+		} else {
+			p.Path = "/"
+		}
+		p.RawPath = ""
+		s.prefix = p.String()
+	default:
+		return nil, fmt.Errorf("%w: scope must be host, domain or prefix, got %q", ErrCrawlOptions, scope)
+	}
+	var errs []error
+	compile := func(patterns []string) []*regexp.Regexp {
+		var res []*regexp.Regexp
+		for _, p := range patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: pattern %q: %v", ErrCrawlOptions, p, err))
+				continue
+			}
+			res = append(res, re)
+		}
+		return res
+	}
+	s.include = compile(include)
+	s.exclude = compile(exclude)
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *crawlScope) allows(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	switch s.scope {
+	case ScopeHost:
+		if host != s.host {
+			return false
+		}
+	case ScopeDomain:
+		if host != s.host && !strings.HasSuffix(host, "."+s.host) {
+			return false
+		}
+	case ScopePrefix:
+		if !strings.HasPrefix(link, s.prefix) {
+			return false
+		}
+	}
+	for _, re := range s.exclude {
+		if re.MatchString(link) {
+			return false
+		}
+	}
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, re := range s.include {
+		if re.MatchString(link) {
+			return true
+		}
+	}
+	return false
+}
+
+// Crawls ведет рекурсивные обходы: ставит затравку и ссылки, найденные парсером, в общую очередь задач.
+// Посещенные URL и лимит страниц хранятся в БД, поэтому обход может идти на нескольких репликах.
+type Crawls struct {
+	repo *db.Repository
+	jobs *JobQueue
+
+	mu     sync.Mutex
+	scopes map[int64]*crawlScope
+}
+
+func NewCrawls(repo *db.Repository, jobs *JobQueue) *Crawls {
+	return &Crawls{repo: repo, jobs: jobs, scopes: make(map[int64]*crawlScope)}
+}
+
+// Start проверяет параметры, создает обход и ставит затравку в очередь.
+// Возвращает id обхода и id задачи затравки.
+func (c *Crawls) Start(ctx context.Context, seed string, opts CrawlOptions) (int64, int64, error) {
+	if opts.MaxDepth < 0 || opts.MaxPages < 0 {
+		return 0, 0, fmt.Errorf("%w: max_depth and max_pages must not be negative", ErrCrawlOptions)
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = DefaultCrawlDepth
+	}
+	if opts.MaxPages == 0 {
+		opts.MaxPages = DefaultCrawlPages
+	}
+	if opts.Scope == "" {
+		opts.Scope = ScopeHost
+	}
+	seed = normalizeLink(seed)
+	sc, err := newCrawlScope(seed, opts.Scope, opts.MaxDepth, opts.Include, opts.Exclude)
+	if err != nil {
+		return 0, 0, err
+	}
+	crawlID, jobID, err := c.repo.CreateCrawl(ctx, &db.Crawl{
+		SeedURL:  seed,
+		MaxDepth: opts.MaxDepth,
+		MaxPages: opts.MaxPages,
+		Scope:    opts.Scope,
+		Include:  opts.Include,
+		Exclude:  opts.Exclude,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	c.mu.Lock()
+	c.scopes[crawlID] = sc
+	c.mu.Unlock()
+	metrics.Submissions.WithLabelValues("api").Inc()
+	c.jobs.Wake()
+	log.Printf("[crawl] started crawl %d from %s (scope=%s depth=%d pages=%d)", crawlID, seed, opts.Scope, opts.MaxDepth, opts.MaxPages)
+	return crawlID, jobID, nil
+}
+
+// Cancel останавливает обход. false - обход уже не шел.
+func (c *Crawls) Cancel(ctx context.Context, id int64) (bool, error) {
+	ok, err := c.repo.CancelCrawl(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	c.forget(id)
+	if ok {
+		log.Printf("[crawl] canceled crawl %d", id)
+	}
+	return ok, nil
+}
+
+func (c *Crawls) forget(id int64) {
+	c.mu.Lock()
+	delete(c.scopes, id)
+	c.mu.Unlock()
+}
+
+// scope возвращает правила обхода, загружая их из БД, если обход начат на другой реплике
+// или до перезапуска. nil - обход уже не идет.
+func (c *Crawls) scope(ctx context.Context, id int64) (*crawlScope, error) {
+	c.mu.Lock()
+	sc, ok := c.scopes[id]
+	c.mu.Unlock()
+	if ok {
+		return sc, nil
+	}
+	cr, err := c.repo.GetCrawl(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cr.State != db.CrawlRunning {
+		return nil, nil
+	}
+	sc, err = newCrawlScope(cr.SeedURL, cr.Scope, cr.MaxDepth, cr.Include, cr.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.scopes[id] = sc
+	c.mu.Unlock()
+	return sc, nil
+}
+
+// Discover ставит в очередь ссылки, найденные на странице обхода ref, если они в области обхода
+// и глубина еще не исчерпана. Ошибки только логируются: страница при этом обработана нормально.
+func (c *Crawls) Discover(ctx context.Context, ref CrawlRef, links []string) {
+	if c == nil || ref.ID == 0 || len(links) == 0 {
+		return
+	}
+	sc, err := c.scope(ctx, ref.ID)
+	if err != nil {
+		log.Printf("[crawl] load crawl %d: %v", ref.ID, err)
+		return
+	}
+	if sc == nil || ref.Depth >= sc.maxDepth {
+		return
+	}
+	var in []string
+	for _, l := range links {
+		if sc.allows(l) {
+			in = append(in, l)
+		}
+	}
+	if len(in) == 0 {
+		return
+	}
+	n, running, err := c.repo.EnqueueCrawlLinks(ctx, ref.ID, ref.Depth+1, in)
+	if err != nil {
+		log.Printf("[crawl] enqueue links for crawl %d: %v", ref.ID, err)
+		return
+	}
+	if !running {
+		c.forget(ref.ID)
+		return
+	}
+	if n > 0 {
+		metrics.Submissions.WithLabelValues("crawl").Add(float64(n))
+		c.jobs.Wake()
+	}
+}
+
+// extractLinks возвращает абсолютные http(s)-ссылки страницы без фрагментов и повторов.
+// Относительные ссылки разрешаются от <base href>, если он есть, иначе от base.
+// Ссылки с rel="nofollow" пропускаются.
+func extractLinks(doc *goquery.Document, base string) []string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil
+	}
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if b, err := baseURL.Parse(strings.TrimSpace(href)); err == nil {
+			baseURL = b
+		}
+	}
+	seen := make(map[string]bool)
+	var links []string
+	doc.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		for _, rel := range strings.Fields(strings.ToLower(a.AttrOr("rel", ""))) {
+			if rel == "nofollow" {
+				return true
+			}
+		}
+		u, err := baseURL.Parse(strings.TrimSpace(a.AttrOr("href", "")))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return true
+		}
+		link := normalizeLink(u.String())
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+		return len(links) < maxLinksPerPage
+	})
+	return links
+}
+
+// normalizeLink убирает фрагмент и приводит схему и хост к нижнему регистру,
+// чтобы одна страница не попадала в посещенные под разными написаниями.
+func normalizeLink(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return raw
+	}
+	u.Fragment, u.RawFragment = "", ""
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if u.Path == "" && u.Host != "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
@@ -3,27 +3,52 @@ package pipeline
 import (
     "context"
     "strings"
-    "log"
     "crypto/sha256"
     "encoding/hex"
+    "time"
     "github.com/abadojack/whatlanggo"
+    "ArticleCrawler/internal/db"
+    "ArticleCrawler/internal/simhash"
+
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/trace"
 )
 
 type EnrichResult struct {
+    JobID           int64
     URL             string
     Title           string
     Body            string
     Summary         string
     ContentHash     string
+    SimHash         uint64
     Language        string
     ReadTimeMinutes int32
+    Meta            Metadata
+    Validators      Validators
+    NotModified     bool
+    Trace           trace.SpanContext
     Err             error
 }
 
-type Enricher struct{}
+type Enricher struct {
+    jobs    *JobQueue
+    drops   stageDrops
+    workers workerPool[ParseResult]
+}
+
+func NewEnricher(jobs *JobQueue) *Enricher {
+    return &Enricher{jobs: jobs, drops: stageDrops{stage: db.StageEnrich, jobs: jobs}}
+}
+
+// Resize меняет число горутин этапа на ходу.
+func (e *Enricher) Resize(workers int) {
+    e.workers.Resize(workers)
+}
 
-func NewEnricher() *Enricher {
-    return &Enricher{}
+// Dropped - сколько результатов этап потерял с момента запуска.
+func (e *Enricher) Dropped() int64 {
+    return e.drops.count()
 }
 
 func summarize(s string, n int) string {
@@ -42,33 +67,46 @@ func readTimeMinutes(s string) int32 {
     return int32(rt)
 }
 
-func (e *Enricher) Enrich(ctx context.Context, in <-chan ParseResult, out chan<- EnrichResult) {
-    for pr := range in {
-        go e.handleOne(ctx, pr, out)
+// Enrich обрабатывает результаты парсинга не более чем в workers горутинах и закрывает out,
+// когда in закрыт или ctx отменен.
+func (e *Enricher) Enrich(ctx context.Context, workers int, in <-chan ParseResult, out chan<- EnrichResult) {
+    runWorkers(ctx, &e.workers, workers, in, out, func(pr ParseResult) {
+        e.handleOne(ctx, pr, out)
+    })
+}
+
+func (e *Enricher) emit(ctx context.Context, out chan<- EnrichResult, res EnrichResult) {
+    if !send(ctx, out, res) {
+        e.drops.drop(ctx, res.JobID, res.URL, dropStopped)
     }
 }
 
 func (e *Enricher) handleOne(ctx context.Context, pr ParseResult, out chan<- EnrichResult) {
+    defer observeStage(db.StageEnrich, time.Now())
     if pr.Err != nil {
-        select {
-        case out <- EnrichResult{URL: pr.URL, Err: pr.Err}:
-        default:
-            log.Printf("[enricher] dropping error for %s", pr.URL)
-        }
+        e.emit(ctx, out, EnrichResult{JobID: pr.JobID, URL: pr.URL, Trace: pr.Trace, Err: pr.Err})
         return
     }
+    if pr.NotModified {
+        e.emit(ctx, out, EnrichResult{JobID: pr.JobID, URL: pr.URL, Validators: pr.Validators, NotModified: true, Trace: pr.Trace})
+        return
+    }
+    ctx, span := startStage(ctx, pr.Trace, "enrich", pr.JobID, pr.URL)
+    defer span.End()
     summary := summarize(pr.Body, 400)
     h := sha256.Sum256([]byte(pr.Body))
     ch := hex.EncodeToString(h[:])
+    _, lspan := tracer.Start(ctx, "language.detect")
     langInfo := whatlanggo.Detect(pr.Body)
     lang := whatlanggo.LangToString(langInfo.Lang)
+    lspan.SetAttributes(attribute.String("language", lang))
+    lspan.End()
     rt := readTimeMinutes(pr.Body)
-    select {
-    case out <- EnrichResult{
-        URL: pr.URL, Title: pr.Title, Body: pr.Body, Summary: summary,
-        ContentHash: ch, Language: lang, ReadTimeMinutes: rt,
-    }:
-    default:
-        log.Printf("[enricher] dropping enrich for %s", pr.URL)
-    }
+    sh := simhash.Compute(pr.Body)
+    e.jobs.Record(ctx, pr.JobID, db.StageEnrich, db.StageOK, "")
+    e.emit(ctx, out, EnrichResult{
+        JobID: pr.JobID, URL: pr.URL, Title: pr.Title, Body: pr.Body, Summary: summary,
+        ContentHash: ch, SimHash: sh, Language: lang, ReadTimeMinutes: rt, Meta: pr.Meta,
+        Validators: pr.Validators, Trace: pr.Trace,
+    })
 }
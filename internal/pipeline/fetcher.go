@@ -3,47 +3,124 @@ package pipeline
 import (
     "context"
     "crypto/sha256"
+    "encoding/hex"
     "fmt"
     "io"
     "net/http"
     "net/url"
+    "strconv"
+    "sync/atomic"
     "time"
     "log"
+    "ArticleCrawler/internal/db"
     "ArticleCrawler/internal/limiter"
+    "ArticleCrawler/internal/metrics"
+    "ArticleCrawler/internal/robots"
+    "ArticleCrawler/internal/tracing"
+    "ArticleCrawler/internal/urlfilter"
+
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/trace"
 )
 
+// Validators - данные прошлого ответа для условного запроса при повторном скачивании.
+type Validators struct {
+    ETag         string
+    LastModified string
+    ResponseHash string
+}
+
 type FetchJob struct {
-    URL string
+    JobID      int64
+    URL        string
+    Validators Validators
+    Crawl      CrawlRef
+    Trace      trace.SpanContext
 }
 
 type FetchResult struct {
-    URL        string
-    Body       []byte
-    StatusCode int
-    Err        error
+    JobID       int64
+    URL         string
+    Body        []byte
+    StatusCode  int
+    Validators  Validators
+    NotModified bool
+    RetryAfter  time.Duration
+    Crawl       CrawlRef
+    Trace       trace.SpanContext
+    Err         error
 }
 
+// maxThrottleWait - дольше этого воркер не ждет домен, закрытый по Retry-After:
+// задача завершается ошибкой и вернется при следующей перепроверке или отправке.
+const maxThrottleWait = 30 * time.Second
+
+// maxLimiterSleep - дольше этого воркер не спит до следующего токена, не спросив лимитер снова:
+// лимит домена могут поднять перезагрузкой конфига или восстановлением после замедления.
+const maxLimiterSleep = 5 * time.Second
+
 type Fetcher struct {
     client *http.Client
     limiter *limiter.DomainLimiter
-    baseBackoff time.Duration
-    maxRetries int
+    jobs *JobQueue
+    robots *robots.Cache
+    userAgent string
+    baseBackoff atomic.Int64 // time.Duration
+    maxRetries atomic.Int64
+    filter atomic.Pointer[urlfilter.Filter]
+    drops stageDrops
+    workers workerPool[FetchJob]
 }
 
-func NewFetcher(l *limiter.DomainLimiter, baseBackoff time.Duration, maxRetries int) *Fetcher {
-    return &Fetcher{
+// rb == nil отключает проверку robots.txt.
+func NewFetcher(l *limiter.DomainLimiter, jobs *JobQueue, rb *robots.Cache, userAgent string, baseBackoff time.Duration, maxRetries int) *Fetcher {
+    f := &Fetcher{
         client: &http.Client{Timeout: 15 * time.Second},
         limiter: l,
-        baseBackoff: baseBackoff,
-        maxRetries: maxRetries,
+        jobs: jobs,
+        robots: rb,
+        userAgent: userAgent,
+        drops: stageDrops{stage: db.StageFetch, jobs: jobs},
     }
+    f.SetBackoff(baseBackoff, maxRetries)
+    return f
+}
+
+// SetBackoff меняет паузу перед первым повтором и число попыток для следующих задач.
+func (f *Fetcher) SetBackoff(base time.Duration, maxRetries int) {
+    f.baseBackoff.Store(int64(base))
+    f.maxRetries.Store(int64(maxRetries))
+}
+
+// SetFilter заменяет фильтр URL, nil пропускает все.
+func (f *Fetcher) SetFilter(filter *urlfilter.Filter) {
+    f.filter.Store(filter)
+}
+
+// Resize меняет число горутин этапа на ходу.
+func (f *Fetcher) Resize(workers int) {
+    f.workers.Resize(workers)
 }
 
-func (f *Fetcher) fetchOnce(ctx context.Context, u string) (*FetchResult, error) {
+// Dropped - сколько результатов этап потерял с момента запуска.
+func (f *Fetcher) Dropped() int64 {
+    return f.drops.count()
+}
+
+func (f *Fetcher) fetchOnce(ctx context.Context, u string, v Validators) (*FetchResult, error) {
     req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
     if err != nil {
         return nil, err
     }
+    if f.userAgent != "" {
+        req.Header.Set("User-Agent", f.userAgent)
+    }
+    if v.ETag != "" {
+        req.Header.Set("If-None-Match", v.ETag)
+    }
+    if v.LastModified != "" {
+        req.Header.Set("If-Modified-Since", v.LastModified)
+    }
     resp, err := f.client.Do(req)
     if err != nil {
         return nil, err
@@ -53,7 +130,17 @@ func (f *Fetcher) fetchOnce(ctx context.Context, u string) (*FetchResult, error)
     if err != nil {
         return nil, err
     }
-    return &FetchResult{URL: u, Body: b, StatusCode: resp.StatusCode, Err: nil}, nil
+    res := &FetchResult{URL: u, Body: b, StatusCode: resp.StatusCode, Err: nil}
+    res.RetryAfter = limiter.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+    res.Validators.ETag = resp.Header.Get("ETag")
+    res.Validators.LastModified = resp.Header.Get("Last-Modified")
+    if resp.StatusCode == http.StatusNotModified {
+        res.NotModified = true
+    } else {
+        h := sha256.Sum256(b)
+        res.Validators.ResponseHash = hex.EncodeToString(h[:])
+    }
+    return res, nil
 }
 
 func domainFromURL(raw string) string {
@@ -64,56 +151,165 @@ func domainFromURL(raw string) string {
     return u.Hostname()
 }
 
-func (f *Fetcher) Fetch(ctx context.Context, in <-chan FetchJob, out chan<- FetchResult, done <-chan struct{}) {
-    for job := range in {
-        go f.handleOne(ctx, job, out)
+// Fetch обрабатывает задачи не более чем в workers горутинах и закрывает out,
+// когда in закрыт или ctx отменен.
+func (f *Fetcher) Fetch(ctx context.Context, workers int, in <-chan FetchJob, out chan<- FetchResult) {
+    runWorkers(ctx, &f.workers, workers, in, out, func(job FetchJob) {
+        f.handleOne(ctx, job, out)
+    })
+}
+
+func (f *Fetcher) emit(ctx context.Context, out chan<- FetchResult, res FetchResult) {
+    if !send(ctx, out, res) {
+        f.drops.drop(ctx, res.JobID, res.URL, dropStopped)
     }
 }
 
-func (f *Fetcher) handleOne(ctx context.Context, job FetchJob, out chan<- FetchResult) {
-    domain := domainFromURL(job.URL)
+// waitLimiter ждет разрешения DomainLimiter для домена. Ошибка - ctx отменен раньше
+// или домен закрыт по Retry-After дольше maxThrottleWait.
+func waitLimiter(ctx context.Context, lim *limiter.DomainLimiter, domain string) error {
+    ctx, span := tracer.Start(ctx, "ratelimit.wait", trace.WithAttributes(attribute.String("domain", domain)))
+    defer span.End()
     for {
-        if f.limiter.Allow(domain) {
-            break
+        if wait := lim.BlockedFor(domain); wait > maxThrottleWait {
+            err := fmt.Errorf("domain %s throttled for %s (Retry-After)", domain, wait.Round(time.Second))
+            tracing.RecordError(span, err)
+            return err
+        }
+        // лимитер сам говорит, когда появится следующий токен: с общим ведром в Postgres
+        // ожидающая задача не делает запрос к БД на каждом шаге опроса
+        wait := lim.Take(ctx, domain)
+        if wait == 0 {
+            return nil
         }
+        if wait > maxLimiterSleep {
+            wait = maxLimiterSleep
+        }
+        t := time.NewTimer(wait)
         select {
-        case <-time.After(200 * time.Millisecond):
+        case <-t.C:
         case <-ctx.Done():
+            t.Stop()
+            return ctx.Err()
+        }
+    }
+}
+
+// fail записывает ошибку этапа и передает ее дальше, чтобы StoreWorker завершил задачу.
+func (f *Fetcher) fail(ctx context.Context, span trace.Span, job FetchJob, out chan<- FetchResult, err error) {
+    tracing.RecordError(span, err)
+    f.jobs.Record(ctx, job.JobID, db.StageFetch, db.StageError, err.Error())
+    f.emit(ctx, out, FetchResult{JobID: job.JobID, URL: job.URL, Trace: job.Trace, Err: err})
+}
+
+// wait ждет лимита домена перед попыткой. false - задача уже отброшена (ctx отменен)
+// или завершена ошибкой, продолжать нельзя.
+func (f *Fetcher) wait(ctx context.Context, span trace.Span, job FetchJob, out chan<- FetchResult, domain string) bool {
+    err := waitLimiter(ctx, f.limiter, domain)
+    if err == nil {
+        return true
+    }
+    if ctx.Err() != nil {
+        f.drops.drop(ctx, job.JobID, job.URL, dropStopped)
+        return false
+    }
+    log.Printf("[fetcher] giving up on %s: %v", job.URL, err)
+    f.fail(ctx, span, job, out, err)
+    return false
+}
+
+func (f *Fetcher) handleOne(ctx context.Context, job FetchJob, out chan<- FetchResult) {
+    defer observeStage(db.StageFetch, time.Now())
+    ctx, span := startStage(ctx, job.Trace, "fetch", job.JobID, job.URL)
+    defer span.End()
+    domain := domainFromURL(job.URL)
+    if err := f.filter.Load().Check(job.URL); err != nil {
+        log.Printf("[fetcher] skipping %s: %v", job.URL, err)
+        f.fail(ctx, span, job, out, err)
+        return
+    }
+    if f.robots != nil {
+        rctx, rspan := tracer.Start(ctx, "robots.check")
+        delay, err := f.robots.Check(rctx, job.URL)
+        tracing.RecordError(rspan, err)
+        rspan.End()
+        f.limiter.SetCrawlDelay(domain, delay)
+        if err != nil {
+            log.Printf("[fetcher] skipping %s: %v", job.URL, err)
+            f.fail(ctx, span, job, out, err)
             return
         }
     }
 
     var lastErr error
     var res *FetchResult
-    backoff := f.baseBackoff
-    for attempt := 0; attempt < f.maxRetries; attempt++ {
-        rr, err := f.fetchOnce(ctx, job.URL)
+    backoff := time.Duration(f.baseBackoff.Load())
+    maxRetries := int(f.maxRetries.Load())
+    for attempt := 0; attempt < maxRetries; attempt++ {
+        // каждая попытка, включая повторы, проходит через лимит домена
+        if !f.wait(ctx, span, job, out, domain) {
+            return
+        }
+        if attempt > 0 {
+            metrics.FetchRetries.WithLabelValues(domain).Inc()
+        }
+        actx, aspan := tracer.Start(ctx, "http.attempt", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.Int("attempt", attempt+1)))
+        started := time.Now()
+        rr, err := f.fetchOnce(actx, job.URL, job.Validators)
+        code := "error"
+        if err == nil {
+            code = strconv.Itoa(rr.StatusCode)
+            aspan.SetAttributes(attribute.Int("http.response.status_code", rr.StatusCode))
+            f.limiter.Observe(domain, rr.StatusCode, time.Since(started), rr.RetryAfter)
+        }
+        tracing.RecordError(aspan, err)
+        aspan.End()
+        metrics.FetchAttempts.WithLabelValues(domain, code).Inc()
         if err == nil && rr.StatusCode >= 200 && rr.StatusCode < 400 {
             res = rr
             lastErr = nil
             break
         }
         lastErr = err
+        if attempt == maxRetries-1 {
+            break
+        }
+        // Retry-After, если он дольше backoff, выдерживает waitLimiter перед следующей попыткой
+        _, bspan := tracer.Start(ctx, "fetch.backoff", trace.WithAttributes(attribute.String("backoff", backoff.String())))
         select {
         case <-time.After(backoff):
+            bspan.End()
             backoff = backoff * 2
         case <-ctx.Done():
+            bspan.End()
+            f.drops.drop(ctx, job.JobID, job.URL, dropStopped)
             return
         }
     }
     if res == nil && lastErr != nil {
-        out <- FetchResult{URL: job.URL, Body: nil, StatusCode: 0, Err: lastErr}
+        f.fail(ctx, span, job, out, lastErr)
         return
     }
     if res == nil {
-        out <- FetchResult{URL: job.URL, Body: nil, StatusCode: 0, Err: fmt.Errorf("failed to fetch")}
+        f.fail(ctx, span, job, out, fmt.Errorf("failed to fetch"))
         return
     }
-    h := sha256.Sum256(res.Body)
-    log.Printf("[fetcher] fetched %s status=%d hash=%x", job.URL, res.StatusCode, h[:6])
-    select {
-    case out <- *res:
-    default:
-        log.Printf("[fetcher] dropping result for %s due to full channel", job.URL)
+    res.JobID = job.JobID
+    res.Crawl = job.Crawl
+    res.Trace = job.Trace
+    if job.Validators.ResponseHash != "" && res.Validators.ResponseHash == job.Validators.ResponseHash {
+        res.NotModified = true
+    }
+    if res.NotModified {
+        res.Body = nil
+        log.Printf("[fetcher] not modified %s status=%d", job.URL, res.StatusCode)
+        f.jobs.Record(ctx, job.JobID, db.StageFetch, db.StageNotModified, "")
+    } else {
+        log.Printf("[fetcher] fetched %s status=%d hash=%.12s", job.URL, res.StatusCode, res.Validators.ResponseHash)
+        f.jobs.Record(ctx, job.JobID, db.StageFetch, db.StageOK, "")
+    }
+    if !f.jobs.Advance(ctx, job.JobID, db.JobParsing) {
+        return
     }
+    f.emit(ctx, out, *res)
 }
@@ -0,0 +1,311 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/metrics"
+)
+
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+)
+
+// сколько событий журнала читается за один запрос при догоне подписчика
+const replayBatch = 500
+
+// Event - сообщение хаба: новая статья или новая ревизия уже существующей.
+// Seq - номер события в журнале article_events, 0 - событие не попало в журнал.
+type Event struct {
+	Seq     int64
+	Type    string
+	Article *db.Article
+}
+
+// EventLog - журнал событий, по которому подписчики догоняют пропущенное; в работе это *db.Repository.
+type EventLog interface {
+	ArticleEventsSince(ctx context.Context, seq int64, limit int) ([]*db.ArticleEvent, error)
+	LastArticleEventSeq(ctx context.Context) (int64, error)
+	GetArticleByID(ctx context.Context, id int64) (*db.Article, error)
+}
+
+// Hub раздает события подписчикам. С журналом событий подписчик, который не успевает читать,
+// не теряет события: хаб перестает класть их в его канал, и подписчик дочитывает их из журнала.
+// Без журнала такие события пропускаются.
+type Hub struct {
+	events    EventLog
+	subs      map[string]*subscription
+	addCh     chan *subscription
+	removeCh  chan string
+	resumeCh  chan string
+	resyncCh  chan struct{}
+	publishCh chan *Event
+	dropped   atomic.Bool
+	listening atomic.Bool
+}
+
+type subscription struct {
+	id     string
+	ch     chan *Event
+	lag    chan struct{}
+	filter *eventFilter
+	// lagged - подписчик догоняет по журналу, события в ch не кладутся; меняется только в run
+	lagged bool
+}
+
+// events == nil - без журнала: since в Subscribe не поддерживается, медленные подписчики пропускают события.
+func NewHub(events EventLog) *Hub {
+	h := &Hub{
+		events:    events,
+		subs:      make(map[string]*subscription),
+		addCh:     make(chan *subscription),
+		removeCh:  make(chan string),
+		resumeCh:  make(chan string),
+		resyncCh:  make(chan struct{}, 1),
+		publishCh: make(chan *Event, 100),
+	}
+	go h.run()
+	return h
+}
+
+func (h *Hub) run() {
+	for {
+		select {
+		case s := <-h.addCh:
+			h.subs[s.id] = s
+			metrics.HubSubscribers.Set(float64(len(h.subs)))
+		case id := <-h.removeCh:
+			if s, ok := h.subs[id]; ok {
+				close(s.ch)
+				delete(h.subs, id)
+			}
+			metrics.HubSubscribers.Set(float64(len(h.subs)))
+		case id := <-h.resumeCh:
+			if s, ok := h.subs[id]; ok {
+				s.lagged = false
+			}
+		case <-h.resyncCh:
+			for _, s := range h.subs {
+				h.setLagged(s)
+			}
+		case ev := <-h.publishCh:
+			if h.events != nil && h.dropped.Swap(false) {
+				// события, не попавшие в publishCh, есть только в журнале
+				for _, s := range h.subs {
+					h.setLagged(s)
+				}
+			}
+			for id, s := range h.subs {
+				if s.lagged || !s.filter.match(ev.Article) {
+					continue
+				}
+				select {
+				case s.ch <- ev:
+				default:
+					if h.events != nil {
+						h.setLagged(s)
+						continue
+					}
+					log.Printf("[hub] skipping slow subscriber %s", id)
+					metrics.HubSlowSkips.Inc()
+				}
+			}
+		}
+	}
+}
+
+func (h *Hub) setLagged(s *subscription) {
+	if s.lagged {
+		return
+	}
+	s.lagged = true
+	metrics.HubCatchUps.Inc()
+	select {
+	case s.lag <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe подписывает id на события, прошедшие filter. Фильтр проверяется в хабе,
+// так что неподходящие события не занимают место в канале подписчика.
+// since > 0 - сначала отдать из журнала события с номером больше since, затем живые.
+// since == 0 - только новые события: если подписчик отстанет раньше, чем получит первое,
+// он догоняет журнал с того места, где тот был при подписке, а не с начала.
+func (h *Hub) Subscribe(ctx context.Context, id string, filter SubscriptionFilter, since int64) (*Subscription, error) {
+	ef, err := compileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if since < 0 {
+		return nil, fmt.Errorf("%w: since_sequence must not be negative", ErrSubscriptionFilter)
+	}
+	if since > 0 && h.events == nil {
+		return nil, fmt.Errorf("%w: since_sequence needs the event log", ErrSubscriptionFilter)
+	}
+	last := since
+	if since == 0 && h.events != nil {
+		if last, err = h.events.LastArticleEventSeq(ctx); err != nil {
+			return nil, fmt.Errorf("read event log: %w", err)
+		}
+	}
+	s := &subscription{id: id, ch: make(chan *Event, 10), lag: make(chan struct{}, 1), filter: ef, lagged: since > 0}
+	h.addCh <- s
+	return &Subscription{hub: h, sub: s, last: last, catchUp: since > 0}, nil
+}
+
+func (h *Hub) Unsubscribe(id string) {
+	h.removeCh <- id
+}
+
+// Listen раздает события, о которых сообщает Postgres, в том числе сохраненные другими репликами.
+// Пока Listen работает, события этой реплики тоже приходят через уведомления (см. Listening).
+// После каждого переподключения подписчики догоняют по журналу то, что могли пропустить.
+func (h *Hub) Listen(ctx context.Context, l *db.Listener) {
+	if h.events == nil {
+		log.Printf("[hub] listen needs the event log, events are published locally")
+		return
+	}
+	h.listening.Store(true)
+	defer h.listening.Store(false)
+	l.Run(ctx, h.resync, func(n db.EventNotification) {
+		art, err := h.events.GetArticleByID(ctx, n.ArticleID)
+		if err != nil {
+			log.Printf("[hub] load article %d for event %d: %v", n.ArticleID, n.Seq, err)
+			return
+		}
+		h.Publish(&Event{Seq: n.Seq, Type: n.Type, Article: art})
+	})
+}
+
+// Listening - хаб получает события через LISTEN, и публиковать их напрямую не нужно.
+func (h *Hub) Listening() bool {
+	return h.listening.Load()
+}
+
+// resync переводит всех подписчиков на догон по журналу.
+func (h *Hub) resync() {
+	if h.events == nil {
+		return
+	}
+	select {
+	case h.resyncCh <- struct{}{}:
+	default:
+	}
+}
+
+func (h *Hub) Publish(ev *Event) {
+	select {
+	case h.publishCh <- ev:
+	default:
+		log.Printf("[hub] publish channel full, dropping %s event for %s", ev.Type, ev.Article.URL)
+		metrics.HubPublishDropped.Inc()
+		h.dropped.Store(true)
+	}
+}
+
+// Subscription - подписка на хаб. Next отдает события по порядку: сначала догоняет по журналу,
+// потом читает живые. Доставка "хотя бы один раз": на стыке журнала и живых событий
+// событие изредка может прийти дважды, клиенты отбрасывают повторы по Seq.
+type Subscription struct {
+	hub     *Hub
+	sub     *subscription
+	last    int64 // номер последнего события, прочитанного из журнала или отданного
+	pending []*Event
+	catchUp bool
+	// resumed - хаб снова кладет события в канал, остался контрольный запрос к журналу
+	resumed bool
+	// номера событий из двух последних пачек журнала: они же могут прийти живыми
+	prevBatch, lastBatch map[int64]bool
+}
+
+// Next возвращает следующее событие. nil без ошибки - подписка закрыта.
+func (s *Subscription) Next(ctx context.Context) (*Event, error) {
+	for {
+		if len(s.pending) > 0 {
+			ev := s.pending[0]
+			s.pending = s.pending[1:]
+			return ev, nil
+		}
+		if s.catchUp {
+			if err := s.replay(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		select {
+		case ev, ok := <-s.sub.ch:
+			if !ok {
+				return nil, nil
+			}
+			if ev.Seq != 0 && ev.Seq <= s.last && (s.lastBatch[ev.Seq] || s.prevBatch[ev.Seq]) {
+				continue
+			}
+			if ev.Seq > s.last {
+				s.last = ev.Seq
+			}
+			return ev, nil
+		case <-s.sub.lag:
+			// все, что лежит в канале, уже есть в журнале
+			if !s.drain() {
+				return nil, nil
+			}
+			s.catchUp, s.resumed = true, false
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// drain очищает канал подписки. false - хаб закрыл подписку.
+func (s *Subscription) drain() bool {
+	for {
+		select {
+		case _, ok := <-s.sub.ch:
+			if !ok {
+				return false
+			}
+		default:
+			return true
+		}
+	}
+}
+
+// replay читает следующую пачку журнала. Когда журнал дочитан, просит хаб снова класть события
+// в канал и делает еще один запрос: за события, сохраненные между последней пачкой и этим моментом.
+func (s *Subscription) replay(ctx context.Context) error {
+	evs, err := s.hub.events.ArticleEventsSince(ctx, s.last, replayBatch)
+	if err != nil {
+		return fmt.Errorf("replay events after %d: %w", s.last, err)
+	}
+	batch := make(map[int64]bool, len(evs))
+	for _, e := range evs {
+		batch[e.Seq] = true
+		s.last = e.Seq
+		if s.sub.filter.match(e.Article) {
+			s.pending = append(s.pending, &Event{Seq: e.Seq, Type: e.Type, Article: e.Article})
+		}
+	}
+	s.prevBatch, s.lastBatch = s.lastBatch, batch
+	if len(evs) < replayBatch {
+		if s.resumed {
+			s.catchUp = false
+			return nil
+		}
+		select {
+		case s.hub.resumeCh <- s.sub.id:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.resumed = true
+	}
+	return nil
+}
+
+// Close отписывается от хаба.
+func (s *Subscription) Close() {
+	s.hub.Unsubscribe(s.sub.id)
+}
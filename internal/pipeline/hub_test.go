@@ -0,0 +1,158 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"ArticleCrawler/internal/db"
+)
+
+// memEventLog - журнал событий в памяти вместо article_events.
+type memEventLog struct {
+	mu     sync.Mutex
+	events []*db.ArticleEvent
+}
+
+func (l *memEventLog) ArticleEventsSince(_ context.Context, seq int64, limit int) ([]*db.ArticleEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var res []*db.ArticleEvent
+	for _, e := range l.events {
+		if e.Seq > seq && len(res) < limit {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func (l *memEventLog) LastArticleEventSeq(context.Context) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.events) == 0 {
+		return 0, nil
+	}
+	return l.events[len(l.events)-1].Seq, nil
+}
+
+func (l *memEventLog) GetArticleByID(_ context.Context, id int64) (*db.Article, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.events {
+		if e.Article.ID == id {
+			return e.Article, nil
+		}
+	}
+	return nil, fmt.Errorf("article %d not found", id)
+}
+
+// append записывает событие в журнал, как AppendArticleEvent, и возвращает его для публикации.
+func (l *memEventLog) append(eventType string, a *db.Article) *Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	seq := int64(len(l.events) + 1)
+	l.events = append(l.events, &db.ArticleEvent{Seq: seq, Type: eventType, Article: a})
+	return &Event{Seq: seq, Type: eventType, Article: a}
+}
+
+func testArticle(id int64) *db.Article {
+	return &db.Article{ID: id, URL: fmt.Sprintf("https://example.com/%d", id), Title: "Article", Language: "en"}
+}
+
+// nextSeqs читает n событий и возвращает их номера.
+func nextSeqs(t *testing.T, sub *Subscription, n int) []int64 {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var seqs []int64
+	for len(seqs) < n {
+		ev, err := sub.Next(ctx)
+		if err != nil || ev == nil {
+			t.Fatalf("after %v: event %v, error %v", seqs, ev, err)
+		}
+		seqs = append(seqs, ev.Seq)
+	}
+	return seqs
+}
+
+func expectNoEvent(t *testing.T, sub *Subscription) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if ev, err := sub.Next(ctx); ev != nil {
+		t.Fatalf("unexpected event %d (error %v)", ev.Seq, err)
+	}
+}
+
+func expectSeqs(t *testing.T, got []int64, from, to int64) {
+	t.Helper()
+	ok := len(got) == int(to-from+1)
+	for i := 0; ok && i < len(got); i++ {
+		ok = got[i] == from+int64(i)
+	}
+	if !ok {
+		t.Fatalf("got events %v, want %d..%d", got, from, to)
+	}
+}
+
+func TestSubscribeNewResyncBeforeFirstEvent(t *testing.T) {
+	events := &memEventLog{}
+	for i := int64(1); i <= 5; i++ {
+		events.append(EventCreated, testArticle(i))
+	}
+	hub := NewHub(events)
+	sub, err := hub.Subscribe(context.Background(), "new", SubscriptionFilter{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	// переподключение LISTEN до первого события: подписчик догоняет журнал, но только с момента подписки
+	hub.resync()
+	hub.Publish(events.append(EventCreated, testArticle(6)))
+	expectSeqs(t, nextSeqs(t, sub, 1), 6, 6)
+	expectNoEvent(t, sub)
+}
+
+func TestSubscribeNewLagBeforeFirstRead(t *testing.T) {
+	events := &memEventLog{}
+	for i := int64(1); i <= 5; i++ {
+		events.append(EventCreated, testArticle(i))
+	}
+	hub := NewHub(events)
+	sub, err := hub.Subscribe(context.Background(), "new", SubscriptionFilter{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	// канал подписчика на 10 событий переполняется раньше, чем он прочитает первое
+	for i := int64(6); i <= 30; i++ {
+		hub.Publish(events.append(EventUpdated, testArticle(i)))
+	}
+	// читать начинаем, только когда хаб перевел подписчика на догон по журналу
+	for deadline := time.Now().Add(5 * time.Second); len(sub.sub.lag) == 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber did not lag")
+		}
+	}
+	expectSeqs(t, nextSeqs(t, sub, 25), 6, 30)
+	expectNoEvent(t, sub)
+}
+
+func TestSubscribeSinceReplaysLog(t *testing.T) {
+	events := &memEventLog{}
+	for i := int64(1); i <= 5; i++ {
+		events.append(EventCreated, testArticle(i))
+	}
+	hub := NewHub(events)
+	sub, err := hub.Subscribe(context.Background(), "since", SubscriptionFilter{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	expectSeqs(t, nextSeqs(t, sub, 3), 3, 5)
+	hub.Publish(events.append(EventCreated, testArticle(6)))
+	expectSeqs(t, nextSeqs(t, sub, 1), 6, 6)
+	expectNoEvent(t, sub)
+}
@@ -0,0 +1,335 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type Metadata struct {
+	Author       string
+	PublishedAt  *time.Time
+	ModifiedAt   *time.Time
+	CanonicalURL string
+	SiteName     string
+	ImageURL     string
+	Section      string
+	Keywords     []string
+}
+
+var articleTypes = map[string]bool{
+	"Article":               true,
+	"NewsArticle":           true,
+	"ReportageNewsArticle":  true,
+	"AnalysisNewsArticle":   true,
+	"OpinionNewsArticle":    true,
+	"BackgroundNewsArticle": true,
+	"BlogPosting":           true,
+	"TechArticle":           true,
+	"ScholarlyArticle":      true,
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"January 2, 2006",
+}
+
+// extractMetadata собирает метаданные статьи из нескольких источников.
+// При конфликте приоритет такой: JSON-LD, OpenGraph (og:*, article:*), Twitter Cards,
+// микроразметка schema.org, обычные <meta name=...>.
+// Вызывать до extractReadable: тот удаляет <script> с JSON-LD.
+func extractMetadata(doc *goquery.Document, pageURL string) Metadata {
+	ld := jsonLDMetadata(doc)
+	og := openGraphMetadata(doc)
+	tw := twitterMetadata(doc)
+	md := microdataMetadata(doc)
+	mt := metaTagMetadata(doc)
+	sources := []Metadata{ld, og, tw, md, mt}
+
+	var m Metadata
+	for _, s := range sources {
+		m.Author = firstNonEmpty(m.Author, s.Author)
+		m.CanonicalURL = firstNonEmpty(m.CanonicalURL, s.CanonicalURL)
+		m.SiteName = firstNonEmpty(m.SiteName, s.SiteName)
+		m.ImageURL = firstNonEmpty(m.ImageURL, s.ImageURL)
+		m.Section = firstNonEmpty(m.Section, s.Section)
+		if m.PublishedAt == nil {
+			m.PublishedAt = s.PublishedAt
+		}
+		if m.ModifiedAt == nil {
+			m.ModifiedAt = s.ModifiedAt
+		}
+		if len(m.Keywords) == 0 {
+			m.Keywords = s.Keywords
+		}
+	}
+	m.CanonicalURL = resolveURL(pageURL, m.CanonicalURL)
+	m.ImageURL = resolveURL(pageURL, m.ImageURL)
+	return m
+}
+
+func jsonLDMetadata(doc *goquery.Document) Metadata {
+	var m Metadata
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		var raw interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s.Text())), &raw); err != nil {
+			return true
+		}
+		if node := findArticleNode(raw); node != nil {
+			m = ldNodeMetadata(node)
+			return false
+		}
+		return true
+	})
+	return m
+}
+
+// findArticleNode ищет объект типа Article/NewsArticle в массиве, @graph или самом объекте.
+func findArticleNode(v interface{}) map[string]interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			if n := findArticleNode(item); n != nil {
+				return n
+			}
+		}
+	case map[string]interface{}:
+		for _, typ := range ldStrings(t["@type"]) {
+			if articleTypes[typ] {
+				return t
+			}
+		}
+		if g, ok := t["@graph"]; ok {
+			return findArticleNode(g)
+		}
+	}
+	return nil
+}
+
+func ldNodeMetadata(n map[string]interface{}) Metadata {
+	m := Metadata{
+		Author:      strings.Join(ldNames(n["author"]), ", "),
+		PublishedAt: parseDate(ldString(n["datePublished"])),
+		ModifiedAt:  parseDate(ldString(n["dateModified"])),
+		Section:     strings.Join(ldStrings(n["articleSection"]), ", "),
+		Keywords:    splitKeywords(ldStrings(n["keywords"])),
+	}
+	if imgs := ldURLs(n["image"]); len(imgs) > 0 {
+		m.ImageURL = imgs[0]
+	}
+	if names := ldNames(n["publisher"]); len(names) > 0 {
+		m.SiteName = names[0]
+	}
+	if u := ldURLs(n["mainEntityOfPage"]); len(u) > 0 {
+		m.CanonicalURL = u[0]
+	} else {
+		m.CanonicalURL = ldString(n["url"])
+	}
+	return m
+}
+
+func ldString(v interface{}) string {
+	if s := ldStrings(v); len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
+
+func ldStrings(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		if s := strings.TrimSpace(t); s != "" {
+			return []string{s}
+		}
+	case []interface{}:
+		var res []string
+		for _, item := range t {
+			res = append(res, ldStrings(item)...)
+		}
+		return res
+	}
+	return nil
+}
+
+// ldNames достает имена из строки, объекта {"name": ...} или массива таких значений.
+func ldNames(v interface{}) []string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return ldStrings(t["name"])
+	case []interface{}:
+		var res []string
+		for _, item := range t {
+			res = append(res, ldNames(item)...)
+		}
+		return res
+	}
+	return ldStrings(v)
+}
+
+func ldURLs(v interface{}) []string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if u := ldStrings(t["url"]); len(u) > 0 {
+			return u
+		}
+		return ldStrings(t["@id"])
+	case []interface{}:
+		var res []string
+		for _, item := range t {
+			res = append(res, ldURLs(item)...)
+		}
+		return res
+	}
+	return ldStrings(v)
+}
+
+func openGraphMetadata(doc *goquery.Document) Metadata {
+	return Metadata{
+		Author:       metaContent(doc, `meta[property="article:author"]`, `meta[property="og:article:author"]`),
+		PublishedAt:  parseDate(metaContent(doc, `meta[property="article:published_time"]`, `meta[property="og:published_time"]`)),
+		ModifiedAt:   parseDate(metaContent(doc, `meta[property="article:modified_time"]`, `meta[property="og:updated_time"]`)),
+		CanonicalURL: metaContent(doc, `meta[property="og:url"]`),
+		SiteName:     metaContent(doc, `meta[property="og:site_name"]`),
+		ImageURL:     metaContent(doc, `meta[property="og:image:secure_url"]`, `meta[property="og:image"]`, `meta[property="og:image:url"]`),
+		Section:      metaContent(doc, `meta[property="article:section"]`),
+		Keywords:     metaContents(doc, `meta[property="article:tag"]`),
+	}
+}
+
+func twitterMetadata(doc *goquery.Document) Metadata {
+	return Metadata{
+		Author:   metaContent(doc, `meta[name="twitter:creator"]`),
+		SiteName: metaContent(doc, `meta[name="twitter:site"]`),
+		ImageURL: metaContent(doc, `meta[name="twitter:image"]`, `meta[name="twitter:image:src"]`),
+	}
+}
+
+func microdataMetadata(doc *goquery.Document) Metadata {
+	var m Metadata
+	scope := doc.Find(`[itemscope][itemtype*="Article"], [itemscope][itemtype*="BlogPosting"]`).First()
+	if scope.Length() == 0 {
+		return m
+	}
+	prop := func(name string) string {
+		s := scope.Find(`[itemprop="` + name + `"]`).First()
+		if s.Length() == 0 {
+			return ""
+		}
+		if n := s.Find(`[itemprop="name"]`).First(); n.Length() > 0 {
+			s = n
+		}
+		for _, attr := range []string{"content", "datetime", "href", "src"} {
+			if v, ok := s.Attr(attr); ok && strings.TrimSpace(v) != "" {
+				return strings.TrimSpace(v)
+			}
+		}
+		return normalizeSpace(s.Text())
+	}
+	m.Author = prop("author")
+	m.PublishedAt = parseDate(prop("datePublished"))
+	m.ModifiedAt = parseDate(prop("dateModified"))
+	m.ImageURL = prop("image")
+	m.Section = prop("articleSection")
+	m.Keywords = splitKeywords([]string{prop("keywords")})
+	m.SiteName = prop("publisher")
+	return m
+}
+
+func metaTagMetadata(doc *goquery.Document) Metadata {
+	canonical, _ := doc.Find(`link[rel="canonical"]`).First().Attr("href")
+	return Metadata{
+		Author:       metaContent(doc, `meta[name="author"]`, `meta[name="byl"]`, `meta[name="dc.creator"]`, `meta[name="DC.creator"]`),
+		PublishedAt:  parseDate(metaContent(doc, `meta[name="pubdate"]`, `meta[name="publish-date"]`, `meta[name="date"]`, `meta[name="dc.date"]`, `meta[name="DC.date.issued"]`, `meta[itemprop="datePublished"]`, `time[pubdate]`)),
+		ModifiedAt:   parseDate(metaContent(doc, `meta[name="last-modified"]`, `meta[name="dc.modified"]`)),
+		CanonicalURL: strings.TrimSpace(canonical),
+		Section:      metaContent(doc, `meta[name="section"]`),
+		Keywords:     splitKeywords([]string{metaContent(doc, `meta[name="keywords"]`, `meta[name="news_keywords"]`)}),
+	}
+}
+
+func metaContent(doc *goquery.Document, selectors ...string) string {
+	for _, sel := range selectors {
+		s := doc.Find(sel).First()
+		for _, attr := range []string{"content", "datetime"} {
+			if v, ok := s.Attr(attr); ok && strings.TrimSpace(v) != "" {
+				return strings.TrimSpace(v)
+			}
+		}
+	}
+	return ""
+}
+
+func metaContents(doc *goquery.Document, selector string) []string {
+	var res []string
+	doc.Find(selector).Each(func(i int, s *goquery.Selection) {
+		if v, ok := s.Attr("content"); ok && strings.TrimSpace(v) != "" {
+			res = append(res, strings.TrimSpace(v))
+		}
+	})
+	return res
+}
+
+func splitKeywords(values []string) []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		for _, k := range strings.Split(v, ",") {
+			k = strings.TrimSpace(k)
+			if k != "" && !seen[strings.ToLower(k)] {
+				seen[strings.ToLower(k)] = true
+				res = append(res, k)
+			}
+		}
+	}
+	return res
+}
+
+func parseDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
+
+func resolveURL(base, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
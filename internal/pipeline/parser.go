@@ -3,64 +3,146 @@ package pipeline
 import (
     "context"
     "strings"
-    "log"
     "github.com/PuerkitoBio/goquery"
     "bytes"
+    "sync/atomic"
+    "time"
+    "ArticleCrawler/internal/db"
+    "ArticleCrawler/internal/tracing"
+
+    "go.opentelemetry.io/otel/trace"
 )
 
 type ParseResult struct {
-    URL   string
-    Title string
-    Body  string
-    Err   error
+    JobID       int64
+    URL         string
+    Title       string
+    Body        string
+    Meta        Metadata
+    Validators  Validators
+    NotModified bool
+    Trace       trace.SpanContext
+    Err         error
+}
+
+type parserModes struct {
+    defaultMode string
+    domainModes map[string]string
+}
+
+type Parser struct {
+    jobs    *JobQueue
+    modes   atomic.Pointer[parserModes]
+    drops   stageDrops
+    crawls  *Crawls
+    workers workerPool[FetchResult]
+}
+
+// domainModes задает режим извлечения текста для домена и его поддоменов.
+func NewParser(jobs *JobQueue, defaultMode string, domainModes map[string]string) *Parser {
+    p := &Parser{jobs: jobs, drops: stageDrops{stage: db.StageParse, jobs: jobs}}
+    p.SetModes(defaultMode, domainModes)
+    return p
+}
+
+// SetModes заменяет режимы извлечения текста, следующие ответы разбираются уже по ним.
+func (p *Parser) SetModes(defaultMode string, domainModes map[string]string) {
+    if defaultMode == "" {
+        defaultMode = ModeReadability
+    }
+    modes := make(map[string]string, len(domainModes))
+    for d, m := range domainModes {
+        modes[strings.ToLower(d)] = m
+    }
+    p.modes.Store(&parserModes{defaultMode: defaultMode, domainModes: modes})
+}
+
+// SetCrawls включает сбор ссылок со страниц рекурсивных обходов. Вызывается до Parse.
+func (p *Parser) SetCrawls(c *Crawls) {
+    p.crawls = c
+}
+
+// Resize меняет число горутин этапа на ходу.
+func (p *Parser) Resize(workers int) {
+    p.workers.Resize(workers)
+}
+
+// Dropped - сколько результатов этап потерял с момента запуска.
+func (p *Parser) Dropped() int64 {
+    return p.drops.count()
 }
 
-type Parser struct{}
+func (p *Parser) modeFor(host string) string {
+    modes := p.modes.Load()
+    host = strings.ToLower(host)
+    for {
+        if m, ok := modes.domainModes[host]; ok {
+            return m
+        }
+        i := strings.IndexByte(host, '.')
+        if i < 0 {
+            return modes.defaultMode
+        }
+        host = host[i+1:]
+    }
+}
 
-func NewParser() *Parser {
-    return &Parser{}
+// Parse обрабатывает ответы не более чем в workers горутинах и закрывает out,
+// когда in закрыт или ctx отменен.
+func (p *Parser) Parse(ctx context.Context, workers int, in <-chan FetchResult, out chan<- ParseResult) {
+    runWorkers(ctx, &p.workers, workers, in, out, func(fr FetchResult) {
+        p.handleOne(ctx, fr, out)
+    })
 }
 
-func (p *Parser) Parse(ctx context.Context, in <-chan FetchResult, out chan<- ParseResult) {
-    for fr := range in {
-        go p.handleOne(ctx, fr, out)
+func (p *Parser) emit(ctx context.Context, out chan<- ParseResult, res ParseResult) {
+    if !send(ctx, out, res) {
+        p.drops.drop(ctx, res.JobID, res.URL, dropStopped)
     }
 }
 
 func (p *Parser) handleOne(ctx context.Context, fr FetchResult, out chan<- ParseResult) {
+    defer observeStage(db.StageParse, time.Now())
     if fr.Err != nil {
-        select {
-        case out <- ParseResult{URL: fr.URL, Err: fr.Err}:
-        default:
-            log.Printf("[parser] dropping error for %s", fr.URL)
-        }
+        p.emit(ctx, out, ParseResult{JobID: fr.JobID, URL: fr.URL, Trace: fr.Trace, Err: fr.Err})
+        return
+    }
+    if fr.NotModified {
+        p.emit(ctx, out, ParseResult{JobID: fr.JobID, URL: fr.URL, Validators: fr.Validators, NotModified: true, Trace: fr.Trace})
         return
     }
+    ctx, span := startStage(ctx, fr.Trace, "parse", fr.JobID, fr.URL)
+    defer span.End()
+    _, gspan := tracer.Start(ctx, "goquery.parse")
     doc, err := goquery.NewDocumentFromReader(bytes.NewReader(fr.Body))
+    tracing.RecordError(gspan, err)
+    gspan.End()
     if err != nil {
-        select {
-        case out <- ParseResult{URL: fr.URL, Err: err}:
-        default:
-            log.Printf("[parser] dropping parse error for %s", fr.URL)
-        }
+        tracing.RecordError(span, err)
+        p.jobs.Record(ctx, fr.JobID, db.StageParse, db.StageError, err.Error())
+        p.emit(ctx, out, ParseResult{JobID: fr.JobID, URL: fr.URL, Trace: fr.Trace, Err: err})
         return
     }
+    // ссылки собираются до extractReadable: она вырезает из документа навигацию, пагинацию и подвал
+    var links []string
+    if fr.Crawl.ID != 0 {
+        links = extractLinks(doc, fr.URL)
+    }
+    _, espan := tracer.Start(ctx, "extract")
     title := strings.TrimSpace(doc.Find("title").First().Text())
-    var bodyBuilder strings.Builder
-    doc.Find("p").Each(func(i int, s *goquery.Selection) {
-        txt := strings.TrimSpace(s.Text())
-        if txt != "" {
-            bodyBuilder.WriteString(txt)
-            bodyBuilder.WriteString("\n\n")
+    meta := extractMetadata(doc, fr.URL)
+    body := extractParagraphs(doc)
+    if p.modeFor(domainFromURL(fr.URL)) == ModeReadability {
+        if readable := extractReadable(doc); len(readable) >= minReadableLength || len(readable) >= len(body) {
+            body = readable
         }
-    })
-    body := strings.TrimSpace(bodyBuilder.String())
-    if body == "" {
-        body = strings.TrimSpace(doc.Text())
     }
-    select {
-    case out <- ParseResult{URL: fr.URL, Title: title, Body: body}:
-    default:
-        log.Printf("[parser] dropping parse result for %s", fr.URL)
+    espan.End()
+    if fr.Crawl.ID != 0 {
+        lctx, lspan := tracer.Start(ctx, "crawl.discover")
+        p.crawls.Discover(lctx, fr.Crawl, links)
+        lspan.End()
     }
+    p.jobs.Record(ctx, fr.JobID, db.StageParse, db.StageOK, "")
+    p.emit(ctx, out, ParseResult{JobID: fr.JobID, URL: fr.URL, Title: title, Body: body, Meta: meta, Validators: fr.Validators, Trace: fr.Trace})
 }
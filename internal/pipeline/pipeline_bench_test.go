@@ -0,0 +1,128 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ArticleCrawler/internal/limiter"
+)
+
+const benchPage = `<html><head><title>Bench article</title></head><body><article>` +
+	`<p>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.</p>` +
+	`<p>Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat.</p>` +
+	`</article></body></html>`
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// burstStats - пиковые значения за прогон всплеска URL.
+type burstStats struct {
+	done, failed   int64
+	peakGoroutines int64
+	peakHeap       uint64
+}
+
+// runBurst прогоняет total URL разом через fetch -> parse -> enrich без БД (очередь задач nil)
+// против локального HTTP-сервера и замеряет пиковое число горутин и память.
+func runBurst(tb testing.TB, total, workers, buffer int) burstStats {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, benchPage)
+	}))
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fetchJobs := make(chan FetchJob, buffer)
+	fetchResults := make(chan FetchResult, buffer)
+	parseResults := make(chan ParseResult, buffer)
+	enrichResults := make(chan EnrichResult, buffer)
+	f := NewFetcher(limiter.NewDomainLimiter(1000000, 1000000), nil, nil, "ArticleCrawler-bench", 10*time.Millisecond, 3)
+	p := NewParser(nil, ModeReadability, nil)
+	e := NewEnricher(nil)
+	go f.Fetch(ctx, workers, fetchJobs, fetchResults)
+	go p.Parse(ctx, workers, fetchResults, parseResults)
+	go e.Enrich(ctx, workers, parseResults, enrichResults)
+	go func() {
+		for i := 0; i < total; i++ {
+			fetchJobs <- FetchJob{JobID: int64(i + 1), URL: fmt.Sprintf("%s/article/%d", srv.URL, i)}
+		}
+		close(fetchJobs)
+	}()
+
+	var st burstStats
+	var peakGoroutines atomic.Int64
+	var peakHeap atomic.Uint64
+	stop := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		var ms runtime.MemStats
+		for {
+			if g := int64(runtime.NumGoroutine()); g > peakGoroutines.Load() {
+				peakGoroutines.Store(g)
+			}
+			runtime.ReadMemStats(&ms)
+			if ms.HeapInuse > peakHeap.Load() {
+				peakHeap.Store(ms.HeapInuse)
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	for er := range enrichResults {
+		if er.Err != nil {
+			st.failed++
+		}
+		st.done++
+	}
+	close(stop)
+	<-sampled
+	st.peakGoroutines, st.peakHeap = peakGoroutines.Load(), peakHeap.Load()
+	if st.done != int64(total) {
+		tb.Fatalf("completed %d of %d urls (dropped fetch=%d parse=%d enrich=%d)", st.done, total, f.Dropped(), p.Dropped(), e.Dropped())
+	}
+	return st
+}
+
+// BenchmarkPipelineBurst: одна итерация - один URL, все b.N URL подаются разом.
+// go test ./internal/pipeline -run '^$' -bench PipelineBurst -benchtime 100000x
+func BenchmarkPipelineBurst(b *testing.B) {
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			st := runBurst(b, b.N, workers, 100)
+			b.ReportMetric(float64(st.peakGoroutines), "peak-goroutines")
+			b.ReportMetric(float64(st.peakHeap)/(1<<20), "peak-heap-MiB")
+			b.ReportMetric(float64(st.failed), "errors")
+		})
+	}
+}
+
+// Число горутин пайплайна не должно расти вместе с числом URL во всплеске.
+func TestBurstGoroutinesBounded(t *testing.T) {
+	small := runBurst(t, 100, 4, 100)
+	large := runBurst(t, 1500, 4, 100)
+	if large.failed > 0 {
+		t.Fatalf("%d urls failed", large.failed)
+	}
+	if large.peakGoroutines > small.peakGoroutines+20 {
+		t.Fatalf("peak goroutines grew from %d to %d with the burst size", small.peakGoroutines, large.peakGoroutines)
+	}
+}
@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/metrics"
+)
+
+// runWorkers запускает в пуле p workers обработчиков, читающих из in, и закрывает out,
+// когда in закрыт или ctx отменен и все обработчики вернулись. Обработчик сам отправляет
+// результат в out через send, поэтому медленный следующий этап тормозит этот, а не теряет данные.
+func runWorkers[T, R any](ctx context.Context, p *workerPool[T], workers int, in <-chan T, out chan<- R, handle func(T)) {
+	p.run(ctx, workers, in, handle)
+	close(out)
+}
+
+// workerPool - обработчики одного этапа, число которых можно менять на ходу через Resize.
+type workerPool[T any] struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	ctx      context.Context
+	in       <-chan T
+	handle   func(T)
+	stops    []chan struct{} // по каналу на обработчик, закрытие останавливает его
+	started  bool
+	finished bool
+}
+
+// run запускает n обработчиков и ждет, пока все они не вернутся.
+func (p *workerPool[T]) run(ctx context.Context, n int, in <-chan T, handle func(T)) {
+	p.mu.Lock()
+	p.ctx, p.in, p.handle, p.started = ctx, in, handle, true
+	p.resizeLocked(n)
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// Resize доводит число обработчиков до n (минимум 1). Лишние обработчики дорабатывают
+// текущий элемент и выходят. До запуска этапа и после его остановки ничего не делает.
+func (p *workerPool[T]) Resize(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.started || p.finished {
+		return
+	}
+	p.resizeLocked(n)
+}
+
+func (p *workerPool[T]) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.stops)
+}
+
+func (p *workerPool[T]) resizeLocked(n int) {
+	if n <= 0 {
+		n = 1
+	}
+	for len(p.stops) < n {
+		stop := make(chan struct{})
+		p.stops = append(p.stops, stop)
+		p.wg.Add(1)
+		go p.work(stop)
+	}
+	for len(p.stops) > n {
+		last := len(p.stops) - 1
+		close(p.stops[last])
+		p.stops = p.stops[:last]
+	}
+}
+
+func (p *workerPool[T]) work(stop <-chan struct{}) {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		select {
+		case v, ok := <-p.in:
+			if !ok {
+				p.finish()
+				return
+			}
+			p.handle(v)
+		case <-stop:
+			return
+		case <-p.ctx.Done():
+			p.finish()
+			return
+		}
+	}
+}
+
+// finish отмечается до выхода обработчика, поэтому Resize не может добавить
+// обработчик в WaitGroup, ожидание которой уже закончилось.
+func (p *workerPool[T]) finish() {
+	p.mu.Lock()
+	p.finished = true
+	p.mu.Unlock()
+}
+
+// send блокируется, пока out не примет v. false - ctx отменен раньше.
+func send[R any](ctx context.Context, out chan<- R, v R) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// stageDrops считает результаты, которые этап так и не передал дальше. После перехода на
+// блокирующую отправку это происходит только при остановке пайплайна; сами задачи
+// остаются арендованными в crawl_jobs и возвращаются в очередь через RecoverJobs.
+type stageDrops struct {
+	stage string
+	jobs  *JobQueue
+	n     atomic.Int64
+}
+
+func (d *stageDrops) drop(ctx context.Context, jobID int64, url, reason string) {
+	d.n.Add(1)
+	metrics.Dropped.WithLabelValues(d.stage).Inc()
+	log.Printf("[%s] dropping %s: %s", d.stage, url, reason)
+	// ctx здесь обычно уже отменен, а запись в лог задачи терять не хочется
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	d.jobs.Record(rctx, jobID, d.stage, db.StageDropped, reason)
+}
+
+func (d *stageDrops) count() int64 {
+	return d.n.Load()
+}
+
+const dropStopped = "pipeline stopped"
+
+func observeStage(stage string, start time.Time) {
+	metrics.StageDuration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,183 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/metrics"
+	"ArticleCrawler/internal/tracing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type JobQueue struct {
+	repo         *db.Repository
+	workerID     string
+	lease        time.Duration
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	wake         chan struct{}
+}
+
+func NewJobQueue(repo *db.Repository, workerID string, lease, pollInterval time.Duration, batchSize, maxAttempts int) *JobQueue {
+	if lease <= 0 {
+		lease = 5 * time.Minute
+	}
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	return &JobQueue{
+		repo:         repo,
+		workerID:     workerID,
+		lease:        lease,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		maxAttempts:  maxAttempts,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+func (q *JobQueue) Lease() time.Duration {
+	return q.lease
+}
+
+func (q *JobQueue) Submit(ctx context.Context, url string) (int64, error) {
+	id, err := q.repo.EnqueueJob(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	metrics.Submissions.WithLabelValues("api").Inc()
+	q.Wake()
+	return id, nil
+}
+
+// Wake будит Run, чтобы новые задачи забрались без ожидания следующего опроса.
+func (q *JobQueue) Wake() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Advance, Finish и Record на nil-очереди ничего не делают: так этапы можно гонять без БД (pipeline_bench_test.go).
+// Advance возвращает false, если аренда задачи потеряна: задачу обрабатывает кто-то другой,
+// и дальше ее вести не нужно.
+func (q *JobQueue) Advance(ctx context.Context, id int64, state db.JobState) bool {
+	if q == nil {
+		return true
+	}
+	err := q.repo.AdvanceJob(ctx, id, q.workerID, state, q.lease)
+	if errors.Is(err, db.ErrLeaseLost) {
+		log.Printf("[queue] job %d: lease lost, dropping", id)
+		return false
+	}
+	if err != nil {
+		log.Printf("[queue] advance job %d to %s: %v", id, state, err)
+	}
+	return true
+}
+
+func (q *JobQueue) Finish(ctx context.Context, id int64, state db.JobState, articleID int64, errText string) {
+	if q == nil {
+		metrics.JobsFinished.WithLabelValues(string(state)).Inc()
+		return
+	}
+	err := q.repo.FinishJob(ctx, id, q.workerID, state, articleID, errText)
+	if errors.Is(err, db.ErrLeaseLost) {
+		log.Printf("[queue] job %d: lease lost, result %s discarded", id, state)
+		return
+	}
+	if err != nil {
+		log.Printf("[queue] finish job %d as %s: %v", id, state, err)
+	}
+	metrics.JobsFinished.WithLabelValues(string(state)).Inc()
+}
+
+// Record сохраняет статус этапа пайплайна для задачи, чтобы его можно было увидеть в GetJobStatus.
+func (q *JobQueue) Record(ctx context.Context, id int64, stage, status, errText string) {
+	if q == nil {
+		return
+	}
+	if err := q.repo.RecordJobEvent(ctx, id, stage, status, errText); err != nil {
+		log.Printf("[queue] record %s/%s for job %d: %v", stage, status, id, err)
+	}
+}
+
+// Run единственный пишет в out и закрывает его при остановке, следом по цепочке закрываются остальные этапы.
+func (q *JobQueue) Run(ctx context.Context, out chan<- FetchJob) {
+	defer close(out)
+	if n, err := q.repo.RecoverJobs(ctx, q.workerID); err != nil {
+		log.Printf("[queue] recover jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("[queue] recovered %d in-flight jobs for %s", n, q.workerID)
+	}
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := q.repo.RequeueExpiredJobs(ctx, q.maxAttempts); err != nil {
+			log.Printf("[queue] requeue expired: %v", err)
+		} else if n > 0 {
+			log.Printf("[queue] returned %d expired jobs to queue", n)
+		}
+		for q.dispatch(ctx, out) {
+		}
+		select {
+		case <-ticker.C:
+		case <-q.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatch забирает столько задач, сколько помещается в локальный буфер,
+// чтобы аренда не истекала, пока задача ждет своей очереди в канале.
+// Возвращает true, если был забран полный батч и стоит попробовать еще раз.
+func (q *JobQueue) dispatch(ctx context.Context, out chan<- FetchJob) bool {
+	free := cap(out) - len(out)
+	if free <= 0 {
+		return false
+	}
+	limit := q.batchSize
+	if free < limit {
+		limit = free
+	}
+	jobs, err := q.repo.ClaimJobs(ctx, q.workerID, limit, q.lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[queue] claim jobs: %v", err)
+		}
+		return false
+	}
+	for _, j := range jobs {
+		// задачи без сохраненного контекста (перепроверки) начинают здесь свой трейс
+		_, span := startStage(tracing.Extract(ctx, j.TraceParent), trace.SpanContext{}, "queue.dispatch", j.ID, j.URL)
+		span.End()
+		job := FetchJob{
+			JobID: j.ID,
+			URL:   j.URL,
+			Crawl: CrawlRef{ID: j.CrawlID, Depth: j.Depth},
+			Trace: span.SpanContext(),
+		}
+		// страницу обхода нужно получить целиком, чтобы собрать с нее ссылки, даже если статья не менялась
+		if j.CrawlID == 0 {
+			job.Validators = Validators{ETag: j.ETag, LastModified: j.LastModified, ResponseHash: j.ResponseHash}
+		}
+		select {
+		case out <- job:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return len(jobs) == limit
+}
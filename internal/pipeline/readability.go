@@ -0,0 +1,317 @@
+package pipeline
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	ModeReadability = "readability"
+	ModeParagraphs  = "paragraphs"
+)
+
+// minReadableLength - если выделенный текст короче и при этом короче результата
+// режима paragraphs, считаем что эвристика промахнулась.
+const minReadableLength = 250
+
+var (
+	unlikelyRe = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|consent|cookie|disqus|extra|footer|gdpr|header|legends|menu|modal|nav|newsletter|pager|pagination|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|tags|teaser|tool|widget|advert|^ad-|-ad-|recommend`)
+	maybeRe    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow|story|entry|post`)
+	positiveRe = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeRe = regexp.MustCompile(`(?i)hidden|banner|combx|comment|com-|contact|cookie|foot|footer|footnote|gdpr|masthead|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|social|tags|teaser|widget|recommend`)
+)
+
+var junkTags = "script, style, noscript, iframe, form, nav, aside, button, svg, canvas, input, select, textarea, template, dialog, object, embed"
+
+// extractParagraphs - исходный режим: все <p> подряд, если их нет - весь текст страницы.
+func extractParagraphs(doc *goquery.Document) string {
+	var b strings.Builder
+	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+		txt := strings.TrimSpace(s.Text())
+		if txt != "" {
+			b.WriteString(txt)
+			b.WriteString("\n\n")
+		}
+	})
+	body := strings.TrimSpace(b.String())
+	if body == "" {
+		body = strings.TrimSpace(doc.Text())
+	}
+	return body
+}
+
+// extractReadable выбирает основной узел статьи по оценке плотности текста и ссылок,
+// классам/id и подсказкам <article>/<main>, и возвращает его текст с сохранением
+// абзацев, заголовков и списков. Документ модифицируется.
+func extractReadable(doc *goquery.Document) string {
+	doc.Find(junkTags).Remove()
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		if isRoot(s) {
+			return
+		}
+		id, _ := s.Attr("id")
+		class, _ := s.Attr("class")
+		role, _ := s.Attr("role")
+		match := class + " " + id
+		if role == "navigation" || role == "banner" || role == "complementary" || role == "contentinfo" || role == "dialog" {
+			s.Remove()
+			return
+		}
+		if s.Is("header, footer") && s.Closest("article").Length() == 0 {
+			s.Remove()
+			return
+		}
+		if unlikelyRe.MatchString(match) && !maybeRe.MatchString(match) && s.Find("article, main").Length() == 0 {
+			s.Remove()
+		}
+	})
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(goquery.NewDocumentFromNode(n).Selection)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+	doc.Find("p, pre, td, blockquote").Each(func(i int, s *goquery.Selection) {
+		text := normalizeSpace(s.Text())
+		if len(text) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		node := s.Get(0)
+		addScore(node.Parent, score)
+		if node.Parent != nil {
+			addScore(node.Parent.Parent, score/2)
+			if node.Parent.Parent != nil {
+				addScore(node.Parent.Parent.Parent, score/3)
+			}
+		}
+	})
+
+	var top *html.Node
+	topScore := 0.0
+	for _, n := range candidates {
+		s := goquery.NewDocumentFromNode(n).Selection
+		score := scores[n] * (1 - linkDensity(s))
+		scores[n] = score
+		if top == nil || score > topScore {
+			top = n
+			topScore = score
+		}
+	}
+	if top == nil {
+		if main := doc.Find("article, main, [itemprop=articleBody]").First(); main.Length() > 0 {
+			top = main.Get(0)
+		} else {
+			return ""
+		}
+	}
+
+	// соседние блоки с приличной оценкой или длинные абзацы без ссылок тоже часть статьи
+	threshold := math.Max(10, topScore*0.2)
+	nodes := []*html.Node{top}
+	if top.Parent != nil {
+		nodes = nodes[:0]
+		for sib := top.Parent.FirstChild; sib != nil; sib = sib.NextSibling {
+			if sib.Type != html.ElementNode {
+				continue
+			}
+			if sib == top {
+				nodes = append(nodes, sib)
+				continue
+			}
+			if sc, ok := scores[sib]; ok && sc >= threshold {
+				nodes = append(nodes, sib)
+				continue
+			}
+			s := goquery.NewDocumentFromNode(sib).Selection
+			if sib.Data == "p" {
+				text := normalizeSpace(s.Text())
+				ld := linkDensity(s)
+				if (len(text) > 80 && ld < 0.25) || (len(text) > 0 && ld == 0 && strings.HasSuffix(text, ".")) {
+					nodes = append(nodes, sib)
+				}
+			}
+		}
+	}
+
+	var r textRenderer
+	for _, n := range nodes {
+		cleanNode(goquery.NewDocumentFromNode(n).Selection)
+		r.render(n)
+	}
+	return r.String()
+}
+
+func isRoot(s *goquery.Selection) bool {
+	return s.Is("html, body, article, main")
+}
+
+func initialScore(s *goquery.Selection) float64 {
+	score := 0.0
+	switch goquery.NodeName(s) {
+	case "article":
+		score += 30
+	case "main":
+		score += 20
+	case "div":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+	if prop, _ := s.Attr("itemprop"); strings.Contains(prop, "articleBody") {
+		score += 30
+	}
+	return score + classWeight(s)
+}
+
+func classWeight(s *goquery.Selection) float64 {
+	w := 0.0
+	for _, attr := range []string{"class", "id"} {
+		v, ok := s.Attr(attr)
+		if !ok || v == "" {
+			continue
+		}
+		if negativeRe.MatchString(v) {
+			w -= 25
+		}
+		if positiveRe.MatchString(v) {
+			w += 25
+		}
+	}
+	return w
+}
+
+func linkDensity(s *goquery.Selection) float64 {
+	total := len(normalizeSpace(s.Text()))
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	s.Find("a").Each(func(i int, a *goquery.Selection) {
+		links += len(normalizeSpace(a.Text()))
+	})
+	return float64(links) / float64(total)
+}
+
+// cleanNode убирает внутри выбранного узла блоки, которые выглядят как навигация или реклама.
+func cleanNode(s *goquery.Selection) {
+	s.Find("div, section, ul, ol, table, p").Each(func(i int, el *goquery.Selection) {
+		text := normalizeSpace(el.Text())
+		if classWeight(el) < 0 {
+			el.Remove()
+			return
+		}
+		ld := linkDensity(el)
+		if el.Is("ul, ol") && ld > 0.5 {
+			el.Remove()
+			return
+		}
+		if !el.Is("p") && len(text) < 25 && el.Find("img, h1, h2, h3, h4, h5, h6").Length() == 0 {
+			el.Remove()
+			return
+		}
+		if ld > 0.5 && len(text) < 200 {
+			el.Remove()
+		}
+	})
+}
+
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// textRenderer превращает дерево узлов в текст: абзацы разделяются пустой строкой,
+// заголовки помечаются "#", элементы списков - "-" или номером.
+type textRenderer struct {
+	blocks []string
+	inline strings.Builder
+}
+
+func (r *textRenderer) flush() {
+	t := normalizeSpace(r.inline.String())
+	r.inline.Reset()
+	if t != "" {
+		r.blocks = append(r.blocks, t)
+	}
+}
+
+func (r *textRenderer) render(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.inline.WriteString(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			r.render(c)
+		}
+		return
+	}
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.flush()
+		level, _ := strconv.Atoi(n.Data[1:])
+		if t := normalizeSpace(goquery.NewDocumentFromNode(n).Text()); t != "" {
+			r.blocks = append(r.blocks, strings.Repeat("#", level)+" "+t)
+		}
+	case "ul", "ol":
+		r.flush()
+		var items []string
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.Data != "li" {
+				continue
+			}
+			t := normalizeSpace(goquery.NewDocumentFromNode(c).Text())
+			if t == "" {
+				continue
+			}
+			if n.Data == "ol" {
+				items = append(items, strconv.Itoa(len(items)+1)+". "+t)
+			} else {
+				items = append(items, "- "+t)
+			}
+		}
+		if len(items) > 0 {
+			r.blocks = append(r.blocks, strings.Join(items, "\n"))
+		}
+	case "pre":
+		r.flush()
+		if t := strings.TrimSpace(goquery.NewDocumentFromNode(n).Text()); t != "" {
+			r.blocks = append(r.blocks, t)
+		}
+	case "br":
+		r.inline.WriteString(" ")
+	case "img", "picture", "video", "audio", "figure":
+	case "p", "div", "section", "article", "main", "blockquote", "table", "tr", "dl", "dd", "dt", "figcaption", "header", "footer", "li":
+		r.flush()
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			r.render(c)
+		}
+		r.flush()
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			r.render(c)
+		}
+	}
+}
+
+func (r *textRenderer) String() string {
+	r.flush()
+	return strings.Join(r.blocks, "\n\n")
+}
@@ -0,0 +1,84 @@
+package pipeline
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/metrics"
+)
+
+// RecrawlTier задает интервал перепроверки для статей не старше MaxAge.
+// MaxAge == 0 означает "без ограничения по возрасту".
+type RecrawlTier struct {
+	MaxAge   time.Duration
+	Interval time.Duration
+}
+
+type Recrawler struct {
+	repo         *db.Repository
+	jobs         *JobQueue
+	tiers        []RecrawlTier
+	pollInterval time.Duration
+	batchSize    int
+}
+
+func NewRecrawler(repo *db.Repository, jobs *JobQueue, tiers []RecrawlTier, pollInterval time.Duration, batchSize int) *Recrawler {
+	if len(tiers) == 0 {
+		tiers = []RecrawlTier{
+			{MaxAge: 24 * time.Hour, Interval: time.Hour},
+			{MaxAge: 7 * 24 * time.Hour, Interval: 24 * time.Hour},
+			{Interval: 7 * 24 * time.Hour},
+		}
+	}
+	if pollInterval <= 0 {
+		pollInterval = time.Minute
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &Recrawler{
+		repo:         repo,
+		jobs:         jobs,
+		tiers:        tiers,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+	}
+}
+
+// NextCheck возвращает время следующей перепроверки статьи по ее возрасту.
+// Если статья старше всех уровней расписания, используется интервал последнего уровня.
+func (r *Recrawler) NextCheck(createdAt, now time.Time) time.Time {
+	age := now.Sub(createdAt)
+	for _, t := range r.tiers {
+		if t.MaxAge == 0 || age < t.MaxAge {
+			return now.Add(t.Interval)
+		}
+	}
+	return now.Add(r.tiers[len(r.tiers)-1].Interval)
+}
+
+func (r *Recrawler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		n, err := r.repo.EnqueueDueRecrawls(ctx, r.batchSize, func(createdAt time.Time) time.Time {
+			return r.NextCheck(createdAt, time.Now())
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[recrawl] enqueue due articles: %v", err)
+			}
+		} else if n > 0 {
+			log.Printf("[recrawl] enqueued %d articles for recheck", n)
+			metrics.Submissions.WithLabelValues("recrawl").Add(float64(n))
+			r.jobs.Wake()
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
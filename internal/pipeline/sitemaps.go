@@ -0,0 +1,341 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/limiter"
+	"ArticleCrawler/internal/metrics"
+	"ArticleCrawler/internal/robots"
+	"ArticleCrawler/internal/sitemap"
+	"ArticleCrawler/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DefaultSitemapURLs = 50000
+	// одна загрузка читает не больше стольких карт, включая индексы
+	maxSitemapsPerImport = 1000
+	// глубина вложенности индексов: индекс -> индекс -> ... -> urlset
+	maxSitemapDepth = 3
+	// URL ставятся в очередь пачками, чтобы не держать всю карту в одном запросе
+	sitemapBatch = 500
+	// сколько загрузок может ждать своей очереди на реплике
+	maxPendingImports = 16
+)
+
+// ErrSitemapOptions - параметры загрузки карт некорректны.
+var ErrSitemapOptions = errors.New("invalid sitemap options")
+
+// ErrImportsBusy - на реплике уже ждет слишком много загрузок.
+var ErrImportsBusy = errors.New("too many sitemap imports pending")
+
+// SitemapOptions ограничивают, какие URL из карт попадут в очередь. Дата записи - <news:publication_date>,
+// если она есть, иначе <lastmod>; при заданном From или To записи без даты пропускаются.
+// Include и Exclude - регулярные выражения по полному URL. MaxURLs 0 - DefaultSitemapURLs.
+type SitemapOptions struct {
+	From    *time.Time
+	To      *time.Time
+	Include []string
+	Exclude []string
+	MaxURLs int
+}
+
+type sitemapImport struct {
+	id      int64
+	roots   []string
+	opts    SitemapOptions
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	trace   trace.SpanContext
+}
+
+// SitemapImporter читает карты сайта в фоне, по одной загрузке за раз, и ставит найденные
+// статьи в общую очередь задач. Запросы к картам проходят через DomainLimiter и robots.txt.
+type SitemapImporter struct {
+	repo      *db.Repository
+	jobs      *JobQueue
+	limiter   *limiter.DomainLimiter
+	robots    *robots.Cache
+	discovery *robots.Cache
+	client    *http.Client
+	userAgent string
+	workerID  string
+	pending   chan *sitemapImport
+}
+
+// rb == nil отключает проверку robots.txt, но карты из robots.txt все равно находятся.
+// workerID - тот же, что у очереди: по нему после рестарта находятся прерванные загрузки.
+func NewSitemapImporter(repo *db.Repository, jobs *JobQueue, lim *limiter.DomainLimiter, rb *robots.Cache, userAgent, workerID string) *SitemapImporter {
+	discovery := rb
+	if discovery == nil {
+		discovery = robots.NewCache(nil, userAgent, 0, nil)
+	}
+	return &SitemapImporter{
+		repo:      repo,
+		jobs:      jobs,
+		limiter:   lim,
+		robots:    rb,
+		discovery: discovery,
+		client:    &http.Client{Timeout: time.Minute},
+		userAgent: userAgent,
+		workerID:  workerID,
+		pending:   make(chan *sitemapImport, maxPendingImports),
+	}
+}
+
+// Discover возвращает карты, перечисленные в robots.txt сайта, или /sitemap.xml, если их там нет.
+func (s *SitemapImporter) Discover(ctx context.Context, site string) ([]string, error) {
+	u, err := url.Parse(site)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: site must be an absolute http(s) url, got %q", ErrSitemapOptions, site)
+	}
+	found, err := s.discovery.Sitemaps(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(found) > 0 {
+		return found, nil
+	}
+	return []string{u.Scheme + "://" + u.Host + "/sitemap.xml"}, nil
+}
+
+// Start создает загрузку и ставит ее в очередь реплики. Возвращает id загрузки.
+func (s *SitemapImporter) Start(ctx context.Context, roots []string, opts SitemapOptions) (int64, error) {
+	if len(roots) == 0 {
+		return 0, fmt.Errorf("%w: no sitemaps", ErrSitemapOptions)
+	}
+	for _, r := range roots {
+		if u, err := url.Parse(r); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return 0, fmt.Errorf("%w: sitemap must be an absolute http(s) url, got %q", ErrSitemapOptions, r)
+		}
+	}
+	if opts.MaxURLs < 0 {
+		return 0, fmt.Errorf("%w: max_urls must not be negative", ErrSitemapOptions)
+	}
+	if opts.MaxURLs == 0 {
+		opts.MaxURLs = DefaultSitemapURLs
+	}
+	if opts.From != nil && opts.To != nil && !opts.From.Before(*opts.To) {
+		return 0, fmt.Errorf("%w: from must be before to", ErrSitemapOptions)
+	}
+	imp := &sitemapImport{roots: roots, opts: opts, trace: trace.SpanContextFromContext(ctx)}
+	var errs []error
+	compile := func(patterns []string) []*regexp.Regexp {
+		var res []*regexp.Regexp
+		for _, p := range patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: pattern %q: %v", ErrSitemapOptions, p, err))
+				continue
+			}
+			res = append(res, re)
+		}
+		return res
+	}
+	imp.include = compile(opts.Include)
+	imp.exclude = compile(opts.Exclude)
+	if err := errors.Join(errs...); err != nil {
+		return 0, err
+	}
+	if len(s.pending) == cap(s.pending) {
+		return 0, ErrImportsBusy
+	}
+	id, err := s.repo.CreateSitemapImport(ctx, roots, s.workerID)
+	if err != nil {
+		return 0, err
+	}
+	imp.id = id
+	select {
+	case s.pending <- imp:
+	default:
+		s.repo.FinishSitemapImport(ctx, id, db.ImportFailed, ErrImportsBusy.Error())
+		return 0, ErrImportsBusy
+	}
+	return id, nil
+}
+
+// Run обрабатывает загрузки по одной, пока ctx не отменен. Загрузки, прерванные прошлой остановкой
+// этой реплики, помечаются failed.
+func (s *SitemapImporter) Run(ctx context.Context) {
+	if n, err := s.repo.FailInterruptedImports(ctx, s.workerID); err != nil {
+		log.Printf("[sitemap] mark interrupted imports: %v", err)
+	} else if n > 0 {
+		log.Printf("[sitemap] %d imports were interrupted by restart", n)
+	}
+	for {
+		select {
+		case imp := <-s.pending:
+			s.run(ctx, imp)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *SitemapImporter) run(ctx context.Context, imp *sitemapImport) {
+	ctx, span := tracer.Start(tracing.WithParent(ctx, imp.trace), "sitemap.import", trace.WithAttributes(attribute.Int64("sitemap_import.id", imp.id)))
+	defer span.End()
+	log.Printf("[sitemap] import %d: starting from %v", imp.id, imp.roots)
+
+	type item struct {
+		loc   string
+		depth int
+	}
+	queue := make([]item, 0, len(imp.roots))
+	seen := make(map[string]bool)
+	for _, r := range imp.roots {
+		queue = append(queue, item{loc: r})
+		seen[r] = true
+	}
+	matched, fetched, failed := 0, 0, 0
+	for len(queue) > 0 && matched < imp.opts.MaxURLs && fetched+failed < maxSitemapsPerImport {
+		it := queue[0]
+		queue = queue[1:]
+		doc, err := s.fetch(ctx, it.loc)
+		if ctx.Err() != nil {
+			s.repo.FinishSitemapImport(context.Background(), imp.id, db.ImportFailed, "interrupted by shutdown")
+			return
+		}
+		if err != nil {
+			failed++
+			tracing.RecordError(span, err)
+			log.Printf("[sitemap] import %d: %s: %v", imp.id, it.loc, err)
+			s.progress(ctx, imp.id, db.ImportProgress{Failed: 1, Err: fmt.Sprintf("%s: %v", it.loc, err)})
+			continue
+		}
+		fetched++
+		if doc.Index {
+			for _, ref := range doc.Sitemaps {
+				// карта, не менявшаяся с начала периода, не может содержать статей из него
+				if seen[ref.Loc] || it.depth+1 > maxSitemapDepth || (ref.LastMod != nil && imp.opts.From != nil && ref.LastMod.Before(*imp.opts.From)) {
+					continue
+				}
+				seen[ref.Loc] = true
+				queue = append(queue, item{loc: ref.Loc, depth: it.depth + 1})
+			}
+			s.progress(ctx, imp.id, db.ImportProgress{Fetched: 1})
+			continue
+		}
+		p := db.ImportProgress{Fetched: 1, Found: len(doc.URLs)}
+		var batch []string
+		for _, u := range doc.URLs {
+			if matched >= imp.opts.MaxURLs {
+				break
+			}
+			if !imp.allows(u) {
+				continue
+			}
+			matched++
+			p.Matched++
+			batch = append(batch, u.Loc)
+			if len(batch) == sitemapBatch {
+				p.Enqueued += s.enqueue(ctx, imp.id, batch)
+				batch = batch[:0]
+			}
+		}
+		if len(batch) > 0 {
+			p.Enqueued += s.enqueue(ctx, imp.id, batch)
+		}
+		s.progress(ctx, imp.id, p)
+	}
+	state := db.ImportFinished
+	if fetched == 0 {
+		state = db.ImportFailed
+	}
+	if err := s.repo.FinishSitemapImport(ctx, imp.id, state, ""); err != nil {
+		log.Printf("[sitemap] import %d: finish: %v", imp.id, err)
+	}
+	log.Printf("[sitemap] import %d %s: %d sitemaps read, %d failed, %d urls matched", imp.id, state, fetched, failed, matched)
+}
+
+func (imp *sitemapImport) allows(u sitemap.URL) bool {
+	if imp.opts.From != nil || imp.opts.To != nil {
+		d := u.Date()
+		if d == nil || (imp.opts.From != nil && d.Before(*imp.opts.From)) || (imp.opts.To != nil && !d.Before(*imp.opts.To)) {
+			return false
+		}
+	}
+	for _, re := range imp.exclude {
+		if re.MatchString(u.Loc) {
+			return false
+		}
+	}
+	if len(imp.include) == 0 {
+		return true
+	}
+	for _, re := range imp.include {
+		if re.MatchString(u.Loc) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SitemapImporter) enqueue(ctx context.Context, id int64, urls []string) int {
+	n, err := s.repo.EnqueueURLs(ctx, urls)
+	if err != nil {
+		log.Printf("[sitemap] import %d: enqueue %d urls: %v", id, len(urls), err)
+		return 0
+	}
+	if n > 0 {
+		metrics.Submissions.WithLabelValues("sitemap").Add(float64(n))
+		s.jobs.Wake()
+	}
+	return n
+}
+
+func (s *SitemapImporter) progress(ctx context.Context, id int64, p db.ImportProgress) {
+	if err := s.repo.AddImportProgress(ctx, id, p); err != nil {
+		log.Printf("[sitemap] import %d: save progress: %v", id, err)
+	}
+}
+
+// fetch скачивает и разбирает одну карту.
+func (s *SitemapImporter) fetch(ctx context.Context, loc string) (*sitemap.Document, error) {
+	ctx, span := tracer.Start(ctx, "sitemap.fetch", trace.WithAttributes(attribute.String("url.full", loc)))
+	defer span.End()
+	domain := domainFromURL(loc)
+	if s.robots != nil {
+		delay, err := s.robots.Check(ctx, loc)
+		s.limiter.SetCrawlDelay(domain, delay)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := waitLimiter(ctx, s.limiter, domain); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", loc, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.userAgent != "" {
+		req.Header.Set("User-Agent", s.userAgent)
+	}
+	started := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	s.limiter.Observe(domain, resp.StatusCode, time.Since(started), limiter.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("unexpected status %d", resp.StatusCode)
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	doc, err := sitemap.Parse(resp.Body)
+	tracing.RecordError(span, err)
+	return doc, err
+}
@@ -0,0 +1,199 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/feed"
+	"ArticleCrawler/internal/limiter"
+	"ArticleCrawler/internal/metrics"
+	"ArticleCrawler/internal/robots"
+	"ArticleCrawler/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// sourceLease - на сколько откладывается следующий опрос ленты, пока идет текущий
+	sourceLease = 5 * time.Minute
+	// лента больше этого размера обрезается и, скорее всего, не разберется
+	maxFeedSize = 10 << 20
+)
+
+// SourcePoller опрашивает ленты из таблицы sources, каждую со своим интервалом,
+// и ставит в очередь ссылки на новые записи. Запросы к лентам проходят через тот же
+// DomainLimiter и robots.txt, что и скачивание статей.
+type SourcePoller struct {
+	repo         *db.Repository
+	jobs         *JobQueue
+	limiter      *limiter.DomainLimiter
+	robots       *robots.Cache
+	client       *http.Client
+	userAgent    string
+	pollInterval time.Duration
+	batchSize    int
+	wake         chan struct{}
+}
+
+// rb == nil отключает проверку robots.txt. pollInterval - как часто искать ленты, которым пора на опрос.
+func NewSourcePoller(repo *db.Repository, jobs *JobQueue, lim *limiter.DomainLimiter, rb *robots.Cache, userAgent string, pollInterval time.Duration, batchSize int) *SourcePoller {
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+	return &SourcePoller{
+		repo:         repo,
+		jobs:         jobs,
+		limiter:      lim,
+		robots:       rb,
+		client:       &http.Client{Timeout: 15 * time.Second},
+		userAgent:    userAgent,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Wake будит Run, чтобы только что добавленная лента была опрошена без ожидания.
+func (p *SourcePoller) Wake() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *SourcePoller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+	for {
+		sources, err := p.repo.ClaimDueSources(ctx, p.batchSize, sourceLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[sources] claim due sources: %v", err)
+			}
+		}
+		// ленты разных доменов не должны ждать друг друга в лимитере
+		var wg sync.WaitGroup
+		for _, src := range sources {
+			wg.Add(1)
+			go func(src *db.Source) {
+				defer wg.Done()
+				p.poll(ctx, src)
+			}(src)
+		}
+		wg.Wait()
+		if len(sources) == p.batchSize {
+			continue
+		}
+		select {
+		case <-ticker.C:
+		case <-p.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *SourcePoller) poll(ctx context.Context, src *db.Source) {
+	ctx, span := tracer.Start(ctx, "source.poll", trace.WithNewRoot(), trace.WithAttributes(
+		attribute.Int64("source.id", src.ID),
+		attribute.String("url.full", src.URL),
+	))
+	defer span.End()
+	res, n, err := p.fetch(ctx, src)
+	if ctx.Err() != nil {
+		// следующий опрос случится по истечении sourceLease
+		return
+	}
+	result := "ok"
+	switch {
+	case err != nil:
+		tracing.RecordError(span, err)
+		res.Err = err.Error()
+		result = "error"
+		log.Printf("[sources] poll %s: %v", src.URL, err)
+	case res.NotModified:
+		result = "not_modified"
+	case n > 0:
+		log.Printf("[sources] %s: enqueued %d new entries", src.URL, n)
+	}
+	metrics.SourcePolls.WithLabelValues(result).Inc()
+	if err := p.repo.FinishSourcePoll(ctx, src.ID, res); err != nil {
+		log.Printf("[sources] save poll result for %s: %v", src.URL, err)
+	}
+}
+
+// fetch скачивает и разбирает ленту, ставит новые записи в очередь и возвращает их число.
+func (p *SourcePoller) fetch(ctx context.Context, src *db.Source) (db.SourcePoll, int, error) {
+	var res db.SourcePoll
+	domain := domainFromURL(src.URL)
+	if p.robots != nil {
+		delay, err := p.robots.Check(ctx, src.URL)
+		p.limiter.SetCrawlDelay(domain, delay)
+		if err != nil {
+			return res, 0, err
+		}
+	}
+	if err := waitLimiter(ctx, p.limiter, domain); err != nil {
+		return res, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", src.URL, nil)
+	if err != nil {
+		return res, 0, err
+	}
+	if p.userAgent != "" {
+		req.Header.Set("User-Agent", p.userAgent)
+	}
+	if src.ETag != "" {
+		req.Header.Set("If-None-Match", src.ETag)
+	}
+	if src.LastModified != "" {
+		req.Header.Set("If-Modified-Since", src.LastModified)
+	}
+	started := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return res, 0, err
+	}
+	defer resp.Body.Close()
+	p.limiter.Observe(domain, resp.StatusCode, time.Since(started), limiter.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+	if resp.StatusCode == http.StatusNotModified {
+		res.NotModified = true
+		return res, 0, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return res, 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return res, 0, err
+	}
+	f, err := feed.Parse(body, src.URL)
+	if err != nil {
+		return res, 0, err
+	}
+	entries := make([]db.SourceEntry, 0, len(f.Entries))
+	for _, e := range f.Entries {
+		entries = append(entries, db.SourceEntry{URL: e.URL, Title: e.Title, PublishedAt: e.Published})
+	}
+	n, err := p.repo.EnqueueSourceEntries(ctx, src.ID, entries)
+	if err != nil {
+		return res, 0, err
+	}
+	if n > 0 {
+		metrics.Submissions.WithLabelValues("feed").Add(float64(n))
+		p.jobs.Wake()
+	}
+	res.Format, res.Title = f.Format, f.Title
+	res.ETag, res.LastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	return res, n, nil
+}
@@ -2,116 +2,151 @@ package pipeline
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"time"
 
 	"ArticleCrawler/internal/db"
 )
 
-type Hub struct {
-	subs      map[string]chan *db.Article
-	addCh     chan subscription
-	removeCh  chan string
-	publishCh chan *db.Article
+type StoreWorker struct {
+	repo    *db.Repository
+	hub     *Hub
+	jobs    *JobQueue
+	recrawl *Recrawler
+	nearDup int
+	workers workerPool[EnrichResult]
 }
 
-type subscription struct {
-	id string
-	ch chan *db.Article
+// recrawl == nil отключает планирование перепроверок для новых статей.
+// nearDup - максимальное расстояние Хэмминга между SimHash почти-дубликатов, < 0 отключает их поиск.
+func NewStoreWorker(repo *db.Repository, hub *Hub, jobs *JobQueue, recrawl *Recrawler, nearDup int) *StoreWorker {
+	return &StoreWorker{repo: repo, hub: hub, jobs: jobs, recrawl: recrawl, nearDup: nearDup}
 }
 
-func NewHub() *Hub {
-	h := &Hub{
-		subs:      make(map[string]chan *db.Article),
-		addCh:     make(chan subscription),
-		removeCh:  make(chan string),
-		publishCh: make(chan *db.Article, 100),
-	}
-	go h.run()
-	return h
-}
-
-func (h *Hub) run() {
-	for {
+// Store сохраняет результаты не более чем в workers горутинах, пока in не закрыт или ctx не отменен.
+func (s *StoreWorker) Store(ctx context.Context, workers int, in <-chan EnrichResult) {
+	s.workers.run(ctx, workers, in, func(er EnrichResult) {
+		s.storeOne(ctx, er)
 		select {
-		case s := <-h.addCh:
-			h.subs[s.id] = s.ch
-		case id := <-h.removeCh:
-			if ch, ok := h.subs[id]; ok {
-				close(ch)
-				delete(h.subs, id)
-			}
-		case art := <-h.publishCh:
-			for id, ch := range h.subs {
-				select {
-				case ch <- art:
-				default:
-					log.Printf("[hub] skipping slow subscriber %s", id)
-				}
-			}
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
 		}
-	}
-}
-
-func (h *Hub) Subscribe(id string) <-chan *db.Article {
-	ch := make(chan *db.Article, 10)
-	h.addCh <- subscription{id: id, ch: ch}
-	return ch
+	})
 }
 
-func (h *Hub) Unsubscribe(id string) {
-	h.removeCh <- id
+// Resize меняет число горутин этапа на ходу.
+func (s *StoreWorker) Resize(workers int) {
+	s.workers.Resize(workers)
 }
 
-func (h *Hub) Publish(a *db.Article) {
-	select {
-	case h.publishCh <- a:
-	default:
-		log.Printf("[hub] publish channel full, dropping article %s", a.URL)
+func (s *StoreWorker) storeOne(ctx context.Context, er EnrichResult) {
+	defer observeStage(db.StageStore, time.Now())
+	ctx, span := startStage(ctx, er.Trace, "store", er.JobID, er.URL)
+	defer span.End()
+	if er.Err != nil {
+		log.Printf("[store] enrich error for %s: %v", er.URL, er.Err)
+		s.repo.RecordFetchAttempt(ctx, er.URL, false, 0, er.Err.Error())
+		s.jobs.Finish(ctx, er.JobID, db.JobFailed, 0, er.Err.Error())
+		return
 	}
-}
-
-type StoreWorker struct {
-	repo *db.Repository
-	hub  *Hub
-}
-
-func NewStoreWorker(repo *db.Repository, hub *Hub) *StoreWorker {
-	return &StoreWorker{repo: repo, hub: hub}
-}
-
-func (s *StoreWorker) Store(ctx context.Context, in <-chan EnrichResult, done <-chan struct{}) {
-	for er := range in {
-		if er.Err != nil {
-			log.Printf("[store] enrich error for %s: %v", er.URL, er.Err)
-			continue
-		}
-		art := &db.Article{
-			URL:             er.URL,
-			Title:           er.Title,
-			Body:            er.Body,
-			Summary:         er.Summary,
-			ContentHash:     er.ContentHash,
-			Language:        er.Language,
-			ReadTimeMinutes: er.ReadTimeMinutes,
+	if er.NotModified {
+		s.storeNotModified(ctx, er)
+		return
+	}
+	art := &db.Article{
+		URL:             er.URL,
+		Title:           er.Title,
+		Body:            er.Body,
+		Summary:         er.Summary,
+		ContentHash:     er.ContentHash,
+		SimHash:         er.SimHash,
+		Language:        er.Language,
+		ReadTimeMinutes: er.ReadTimeMinutes,
+		Author:          er.Meta.Author,
+		PublishedAt:     er.Meta.PublishedAt,
+		ModifiedAt:      er.Meta.ModifiedAt,
+		CanonicalURL:    er.Meta.CanonicalURL,
+		SiteName:        er.Meta.SiteName,
+		ImageURL:        er.Meta.ImageURL,
+		Section:         er.Meta.Section,
+		Keywords:        er.Meta.Keywords,
+		ETag:            er.Validators.ETag,
+		LastModified:    er.Validators.LastModified,
+		ResponseHash:    er.Validators.ResponseHash,
+	}
+	if s.recrawl != nil {
+		now := time.Now()
+		next := s.recrawl.NextCheck(now, now)
+		art.NextRecrawlAt = &next
+	}
+	status, err := s.repo.SaveArticle(ctx, art)
+	if err != nil {
+		log.Printf("[store] failed to save %s: %v", er.URL, err)
+		s.repo.RecordFetchAttempt(ctx, er.URL, false, 0, err.Error())
+		s.jobs.Record(ctx, er.JobID, db.StageStore, db.StageError, err.Error())
+		s.jobs.Finish(ctx, er.JobID, db.JobFailed, 0, err.Error())
+		return
+	}
+	s.repo.RecordFetchAttempt(ctx, er.URL, true, 200, "")
+	switch status {
+	case db.SaveInserted, db.SaveUpdated:
+		s.jobs.Record(ctx, er.JobID, db.StageStore, db.StageOK, "")
+		s.linkNearDuplicates(ctx, er.JobID, art)
+		s.jobs.Finish(ctx, er.JobID, db.JobStored, art.ID, "")
+		evType := EventCreated
+		if status == db.SaveUpdated {
+			evType = EventUpdated
 		}
-		inserted, err := s.repo.SaveArticle(ctx, art)
+		// событие сначала пишется в журнал: подписчик, догоняющий по журналу, не должен его пропустить.
+		// Если хаб слушает уведомления, событие придет ему через Postgres, как и остальным репликам.
+		seq, err := s.repo.AppendArticleEvent(ctx, evType, art.ID)
 		if err != nil {
-			log.Printf("[store] failed to save %s: %v", er.URL, err)
-			s.repo.RecordFetchAttempt(ctx, er.URL, false, 0, err.Error())
-			continue
-		}
-		s.repo.RecordFetchAttempt(ctx, er.URL, true, 200, "")
-		if inserted {
-			res, err := s.repo.ListArticles(ctx, 1, 0)
-			if err == nil && len(res) > 0 {
-				s.hub.Publish(res[0])
-			}
+			log.Printf("[store] failed to log %s event for %s: %v", evType, er.URL, err)
 		}
-		select {
-		case <-time.After(10 * time.Millisecond):
-		case <-ctx.Done():
+		if seq != 0 && s.hub.Listening() {
 			return
 		}
+		if saved, err := s.repo.GetArticleByID(ctx, art.ID); err == nil {
+			s.hub.Publish(&Event{Seq: seq, Type: evType, Article: saved})
+		}
+	case db.SaveUnchanged:
+		s.jobs.Record(ctx, er.JobID, db.StageStore, db.StageNotModified, "")
+		s.jobs.Finish(ctx, er.JobID, db.JobNotModified, art.ID, "")
+	case db.SaveDuplicate:
+		s.jobs.Record(ctx, er.JobID, db.StageStore, db.StageDuplicate, fmt.Sprintf("content_hash matches article %d", art.ID))
+		s.jobs.Finish(ctx, er.JobID, db.JobDuplicate, art.ID, "")
+	}
+}
+
+// linkNearDuplicates добавляет статью в кластер почти-дубликатов. Ошибка здесь не проваливает задачу:
+// статья уже сохранена, а кластер соберется при следующей перепроверке.
+func (s *StoreWorker) linkNearDuplicates(ctx context.Context, jobID int64, art *db.Article) {
+	if s.nearDup < 0 || art.SimHash == 0 {
+		return
+	}
+	clusterID, nearest, err := s.repo.LinkNearDuplicates(ctx, art.ID, art.SimHash, s.nearDup)
+	if err != nil {
+		log.Printf("[store] near-duplicate lookup failed for %s: %v", art.URL, err)
+		return
+	}
+	if clusterID != 0 {
+		s.jobs.Record(ctx, jobID, db.StageStore, db.StageNearDuplicate, fmt.Sprintf("near duplicate of article %d, cluster %d", nearest, clusterID))
+	}
+}
+
+// storeNotModified обрабатывает 304 или ответ с тем же хешем: статью не переписываем,
+// только обновляем last_checked_at и валидаторы.
+func (s *StoreWorker) storeNotModified(ctx context.Context, er EnrichResult) {
+	id, err := s.repo.TouchArticle(ctx, er.URL, er.Validators.ETag, er.Validators.LastModified, er.Validators.ResponseHash)
+	if err != nil {
+		log.Printf("[store] failed to touch %s: %v", er.URL, err)
+		s.jobs.Record(ctx, er.JobID, db.StageStore, db.StageError, err.Error())
+		s.jobs.Finish(ctx, er.JobID, db.JobFailed, 0, err.Error())
+		return
 	}
+	s.repo.RecordFetchAttempt(ctx, er.URL, true, http.StatusNotModified, "")
+	s.jobs.Record(ctx, er.JobID, db.StageStore, db.StageNotModified, "")
+	s.jobs.Finish(ctx, er.JobID, db.JobNotModified, id, "")
 }
@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ArticleCrawler/internal/db"
+)
+
+// ErrSubscriptionFilter - фильтр подписки некорректен.
+var ErrSubscriptionFilter = errors.New("invalid subscription filter")
+
+// SubscriptionFilter отбирает события хаба для подписчика. Пустые поля не фильтруют.
+// Domains - хосты статьи вместе с поддоменами ("www." не учитывается), TitleQuery - слова,
+// каждое из которых должно встречаться в заголовке без учета регистра, TitleRegex - регулярное
+// выражение по заголовку, MinReadTime - минимальное время чтения в минутах.
+type SubscriptionFilter struct {
+	Domains     []string
+	Language    string
+	TitleQuery  string
+	TitleRegex  string
+	MinReadTime int32
+	SourceID    int64
+}
+
+// eventFilter - SubscriptionFilter, подготовленный для проверки в хабе.
+type eventFilter struct {
+	domains     []string
+	language    string
+	words       []string
+	title       *regexp.Regexp
+	minReadTime int32
+	sourceID    int64
+}
+
+func compileFilter(f SubscriptionFilter) (*eventFilter, error) {
+	if f.MinReadTime < 0 {
+		return nil, fmt.Errorf("%w: min_read_time must not be negative", ErrSubscriptionFilter)
+	}
+	if f.SourceID < 0 {
+		return nil, fmt.Errorf("%w: source_id must not be negative", ErrSubscriptionFilter)
+	}
+	ef := &eventFilter{
+		language:    strings.ToLower(strings.TrimSpace(f.Language)),
+		words:       strings.Fields(strings.ToLower(f.TitleQuery)),
+		minReadTime: f.MinReadTime,
+		sourceID:    f.SourceID,
+	}
+	for _, d := range f.Domains {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			ef.domains = append(ef.domains, strings.TrimPrefix(d, "www."))
+		}
+	}
+	if f.TitleRegex != "" {
+		re, err := regexp.Compile(f.TitleRegex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: title_regex: %v", ErrSubscriptionFilter, err)
+		}
+		ef.title = re
+	}
+	return ef, nil
+}
+
+func (f *eventFilter) match(art *db.Article) bool {
+	if f == nil {
+		return true
+	}
+	if f.language != "" && strings.ToLower(art.Language) != f.language {
+		return false
+	}
+	if art.ReadTimeMinutes < f.minReadTime {
+		return false
+	}
+	if f.sourceID != 0 && art.SourceID != f.sourceID {
+		return false
+	}
+	if len(f.domains) > 0 {
+		host := strings.TrimPrefix(strings.ToLower(domainFromURL(art.URL)), "www.")
+		found := false
+		for _, d := range f.domains {
+			if host == d || strings.HasSuffix(host, "."+d) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.words) > 0 {
+		title := strings.ToLower(art.Title)
+		for _, w := range f.words {
+			if !strings.Contains(title, w) {
+				return false
+			}
+		}
+	}
+	return f.title == nil || f.title.MatchString(art.Title)
+}
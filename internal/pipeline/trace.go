@@ -0,0 +1,22 @@
+package pipeline
+
+import (
+	"context"
+
+	"ArticleCrawler/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("ArticleCrawler/pipeline")
+
+// startStage начинает спан этапа пайплайна. parent - контекст задачи, который едет
+// вместе с ней по каналам, поэтому спаны всех этапов оказываются в одном трейсе.
+func startStage(ctx context.Context, parent trace.SpanContext, name string, jobID int64, url string) (context.Context, trace.Span) {
+	return tracer.Start(tracing.WithParent(ctx, parent), name, trace.WithAttributes(
+		attribute.Int64("job.id", jobID),
+		attribute.String("url.full", url),
+	))
+}
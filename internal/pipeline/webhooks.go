@@ -0,0 +1,441 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/metrics"
+	"ArticleCrawler/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// подписки перечитываются из БД не реже этого: их могли изменить через другую реплику
+	webhookRefresh = 30 * time.Second
+	// ответ получателя читается только для текста ошибки
+	maxWebhookResponse = 1 << 10
+)
+
+// Заголовки запроса с событием. Подпись - HMAC-SHA256 от "<timestamp>.<тело>" с секретом подписки.
+const (
+	WebhookEventHeader     = "X-Crawler-Event"
+	WebhookDeliveryHeader  = "X-Crawler-Delivery"
+	WebhookTimestampHeader = "X-Crawler-Timestamp"
+	WebhookSignatureHeader = "X-Crawler-Signature"
+)
+
+// WebhookSignature возвращает значение WebhookSignatureHeader для тела body, отправленного в timestamp.
+// Получатель считает его так же и сравнивает через hmac.Equal.
+func WebhookSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ArticlePayload - статья в JSON-событиях, имена полей как в gRPC API.
+type ArticlePayload struct {
+	ID              int64      `json:"id"`
+	URL             string     `json:"url"`
+	Title           string     `json:"title"`
+	Body            string     `json:"body"`
+	Summary         string     `json:"summary"`
+	ContentHash     string     `json:"content_hash"`
+	Language        string     `json:"language"`
+	ReadTimeMinutes int32      `json:"read_time_minutes"`
+	Author          string     `json:"author,omitempty"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	ModifiedAt      *time.Time `json:"modified_at,omitempty"`
+	CanonicalURL    string     `json:"canonical_url,omitempty"`
+	SiteName        string     `json:"site_name,omitempty"`
+	ImageURL        string     `json:"image_url,omitempty"`
+	Section         string     `json:"section,omitempty"`
+	Keywords        []string   `json:"keywords,omitempty"`
+	ClusterID       int64      `json:"cluster_id,omitempty"`
+	SourceID        int64      `json:"source_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// EventPayload - событие хаба в JSON.
+type EventPayload struct {
+	Sequence int64          `json:"sequence"`
+	Type     string         `json:"type"`
+	Article  ArticlePayload `json:"article"`
+}
+
+func NewEventPayload(seq int64, eventType string, a *db.Article) EventPayload {
+	return EventPayload{
+		Sequence: seq,
+		Type:     eventType,
+		Article: ArticlePayload{
+			ID:              a.ID,
+			URL:             a.URL,
+			Title:           a.Title,
+			Body:            a.Body,
+			Summary:         a.Summary,
+			ContentHash:     a.ContentHash,
+			Language:        a.Language,
+			ReadTimeMinutes: a.ReadTimeMinutes,
+			Author:          a.Author,
+			PublishedAt:     a.PublishedAt,
+			ModifiedAt:      a.ModifiedAt,
+			CanonicalURL:    a.CanonicalURL,
+			SiteName:        a.SiteName,
+			ImageURL:        a.ImageURL,
+			Section:         a.Section,
+			Keywords:        a.Keywords,
+			ClusterID:       a.ClusterID,
+			SourceID:        a.SourceID,
+			CreatedAt:       a.CreatedAt,
+			UpdatedAt:       a.UpdatedAt,
+		},
+	}
+}
+
+// WebhookRetry - повторы неудачных доставок: BaseDelay перед первым повтором, дальше пауза
+// удваивается до MaxDelay. После DisableAfter неудач подряд подписка выключается.
+type WebhookRetry struct {
+	MaxAttempts  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	DisableAfter int
+}
+
+func (r WebhookRetry) delay(attempt int) time.Duration {
+	d := r.BaseDelay
+	for i := 1; i < attempt && d < r.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, r.MaxDelay)
+}
+
+// webhookStore - методы Repository, которыми пользуется диспетчер.
+type webhookStore interface {
+	WebhookCursor(ctx context.Context) (int64, error)
+	AdvanceWebhookCursor(ctx context.Context, seq int64) error
+	ListEnabledWebhooks(ctx context.Context) ([]*db.Webhook, error)
+	CreateWebhookDeliveries(ctx context.Context, webhookIDs []int64, seq int64, eventType string, articleID int64) (int, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*db.WebhookDelivery, error)
+	FinishWebhookAttempt(ctx context.Context, d *db.WebhookDelivery, a db.WebhookAttempt) (bool, error)
+	GetArticleByID(ctx context.Context, id int64) (*db.Article, error)
+}
+
+type webhookRoute struct {
+	id     int64
+	filter *eventFilter
+	types  map[string]bool
+}
+
+// WebhookDispatcher заводит доставки для событий хаба, подходящих под фильтры подписок,
+// и отправляет их. Доставки хранятся в webhook_deliveries, поэтому повторы переживают рестарт,
+// а отправлять их может любая реплика.
+type WebhookDispatcher struct {
+	repo         webhookStore
+	hub          *Hub
+	client       *http.Client
+	pollInterval time.Duration
+	batchSize    int
+	retry        WebhookRetry
+	wake         chan struct{}
+
+	mu       sync.Mutex
+	routes   []webhookRoute
+	loadedAt time.Time
+}
+
+func NewWebhookDispatcher(repo *db.Repository, hub *Hub, pollInterval time.Duration, batchSize int, timeout time.Duration, retry WebhookRetry) *WebhookDispatcher {
+	return newWebhookDispatcher(repo, hub, pollInterval, batchSize, timeout, retry)
+}
+
+func newWebhookDispatcher(repo webhookStore, hub *Hub, pollInterval time.Duration, batchSize int, timeout time.Duration, retry WebhookRetry) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:         repo,
+		hub:          hub,
+		client:       &http.Client{Timeout: timeout},
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		retry:        retry,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Reload заставляет перечитать подписки перед следующим событием.
+func (d *WebhookDispatcher) Reload() {
+	d.mu.Lock()
+	d.loadedAt = time.Time{}
+	d.mu.Unlock()
+}
+
+func (d *WebhookDispatcher) wakeUp() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run заводит доставки и отправляет их, пока ctx не отменен.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	go d.route(ctx)
+	d.dispatch(ctx)
+}
+
+// dispatch отправляет доставки, которым пора.
+func (d *WebhookDispatcher) dispatch(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	// аренда доставки с запасом перекрывает таймаут запроса
+	lease := 2*d.client.Timeout + time.Minute
+	for {
+		deliveries, err := d.repo.ClaimWebhookDeliveries(ctx, d.batchSize, lease)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[webhooks] claim deliveries: %v", err)
+		}
+		var wg sync.WaitGroup
+		for _, del := range deliveries {
+			wg.Add(1)
+			go func(del *db.WebhookDelivery) {
+				defer wg.Done()
+				d.deliver(ctx, del)
+			}(del)
+		}
+		wg.Wait()
+		if len(deliveries) == d.batchSize {
+			continue
+		}
+		select {
+		case <-ticker.C:
+		case <-d.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// route подписывается на хаб и заводит доставки для каждого события журнала. Подписка начинается
+// с сохраненного курсора, поэтому события, записанные пока диспетчер был остановлен, тоже доставляются.
+// События без номера не доставляются: по номеру доставки одного события с разных реплик склеиваются.
+func (d *WebhookDispatcher) route(ctx context.Context) {
+	var since int64
+	err := d.retryRouting(ctx, "load cursor", func() (err error) {
+		since, err = d.repo.WebhookCursor(ctx)
+		return err
+	})
+	if err != nil {
+		return
+	}
+	var sub *Subscription
+	err = d.retryRouting(ctx, "subscribe", func() (err error) {
+		sub, err = d.hub.Subscribe(ctx, fmt.Sprintf("webhooks-%d", time.Now().UnixNano()), SubscriptionFilter{}, since)
+		return err
+	})
+	if err != nil {
+		return
+	}
+	defer sub.Close()
+	for {
+		ev, err := sub.Next(ctx)
+		if ctx.Err() != nil || (ev == nil && err == nil) {
+			return
+		}
+		if err != nil {
+			// журнал недоступен: следующий Next снова попробует его дочитать
+			log.Printf("[webhooks] read events: %v", err)
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		if ev.Seq == 0 {
+			continue
+		}
+		// курсор сдвигается только после того, как доставки заведены, иначе событие потеряется
+		err = d.retryRouting(ctx, fmt.Sprintf("route event %d", ev.Seq), func() error {
+			return d.routeEvent(ctx, ev)
+		})
+		if err != nil {
+			return
+		}
+		if err := d.repo.AdvanceWebhookCursor(ctx, ev.Seq); err != nil && ctx.Err() == nil {
+			// следующее событие сдвинет курсор дальше; после рестарта повтор ничего не добавит
+			log.Printf("[webhooks] save cursor %d: %v", ev.Seq, err)
+		}
+	}
+}
+
+// retryRouting повторяет fn раз в секунду, пока она не выполнится или не отменят ctx.
+func (d *WebhookDispatcher) retryRouting(ctx context.Context, what string, fn func() error) error {
+	for {
+		err := fn()
+		if err == nil || ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Printf("[webhooks] %s: %v", what, err)
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (d *WebhookDispatcher) routeEvent(ctx context.Context, ev *Event) error {
+	routes, err := d.loadRoutes(ctx)
+	if err != nil {
+		return fmt.Errorf("load webhooks: %w", err)
+	}
+	var ids []int64
+	for _, r := range routes {
+		if (len(r.types) == 0 || r.types[ev.Type]) && r.filter.match(ev.Article) {
+			ids = append(ids, r.id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	n, err := d.repo.CreateWebhookDeliveries(ctx, ids, ev.Seq, ev.Type, ev.Article.ID)
+	if err != nil {
+		return fmt.Errorf("create deliveries: %w", err)
+	}
+	if n > 0 {
+		d.wakeUp()
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) loadRoutes(ctx context.Context) ([]webhookRoute, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if time.Since(d.loadedAt) < webhookRefresh {
+		return d.routes, nil
+	}
+	hooks, err := d.repo.ListEnabledWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]webhookRoute, 0, len(hooks))
+	for _, w := range hooks {
+		f, err := compileFilter(WebhookFilter(w))
+		if err != nil {
+			// фильтр проверяется при создании подписки, сюда попадает только испорченная вручную строка
+			log.Printf("[webhooks] webhook %d: %v", w.ID, err)
+			continue
+		}
+		r := webhookRoute{id: w.ID, filter: f}
+		if len(w.EventTypes) > 0 {
+			r.types = make(map[string]bool, len(w.EventTypes))
+			for _, t := range w.EventTypes {
+				r.types[t] = true
+			}
+		}
+		routes = append(routes, r)
+	}
+	d.routes, d.loadedAt = routes, time.Now()
+	return routes, nil
+}
+
+// WebhookFilter - фильтр событий подписки w.
+func WebhookFilter(w *db.Webhook) SubscriptionFilter {
+	return SubscriptionFilter{
+		Domains:     w.Domains,
+		Language:    w.Language,
+		TitleQuery:  w.TitleQuery,
+		TitleRegex:  w.TitleRegex,
+		MinReadTime: w.MinReadTime,
+		SourceID:    w.SourceID,
+	}
+}
+
+// ValidateFilter проверяет фильтр так же, как Hub.Subscribe.
+func ValidateFilter(f SubscriptionFilter) error {
+	_, err := compileFilter(f)
+	return err
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, del *db.WebhookDelivery) {
+	ctx, span := tracer.Start(ctx, "webhook.deliver", trace.WithNewRoot(), trace.WithAttributes(
+		attribute.Int64("webhook.id", del.WebhookID),
+		attribute.Int64("webhook.delivery_id", del.ID),
+		attribute.String("url.full", del.URL),
+	))
+	defer span.End()
+	attempt := db.WebhookAttempt{DisableAfter: d.retry.DisableAfter}
+	status, err := d.send(ctx, del)
+	if ctx.Err() != nil {
+		// доставка повторится по истечении аренды
+		return
+	}
+	attempt.Status = status
+	result := "ok"
+	if err != nil {
+		tracing.RecordError(span, err)
+		attempt.Err = err.Error()
+		result = "error"
+		if del.Attempts+1 < d.retry.MaxAttempts {
+			attempt.Retry = d.retry.delay(del.Attempts + 1)
+		}
+		log.Printf("[webhooks] delivery %d to %s (attempt %d): %v", del.ID, del.URL, del.Attempts+1, err)
+	} else {
+		attempt.Delivered = true
+	}
+	metrics.WebhookDeliveries.WithLabelValues(result).Inc()
+	disabled, err := d.repo.FinishWebhookAttempt(ctx, del, attempt)
+	if err != nil {
+		log.Printf("[webhooks] save delivery %d: %v", del.ID, err)
+		return
+	}
+	if disabled {
+		log.Printf("[webhooks] webhook %d disabled after %d consecutive failures", del.WebhookID, d.retry.DisableAfter)
+		d.Reload()
+	}
+}
+
+// send отправляет событие и возвращает HTTP-статус ответа (0, если ответа не было).
+func (d *WebhookDispatcher) send(ctx context.Context, del *db.WebhookDelivery) (int, error) {
+	art, err := d.repo.GetArticleByID(ctx, del.ArticleID)
+	if err != nil {
+		return 0, fmt.Errorf("load article %d: %w", del.ArticleID, err)
+	}
+	body, err := json.Marshal(NewEventPayload(del.EventSeq, del.EventType, art))
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", del.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, del.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(del.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(WebhookSignatureHeader, WebhookSignature(del.Secret, ts, body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse))
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponse))
+	return resp.StatusCode, nil
+}
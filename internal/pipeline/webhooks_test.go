@@ -0,0 +1,401 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"ArticleCrawler/internal/db"
+)
+
+// memWebhookStore повторяет в памяти запросы internal/db/webhooks.go, которыми пользуется диспетчер.
+type memWebhookStore struct {
+	mu         sync.Mutex
+	cursor     int64
+	hooks      []*db.Webhook
+	articles   map[int64]*db.Article
+	deliveries []*db.WebhookDelivery
+	attempts   []db.WebhookAttempt
+	// createErrs - сколько первых вызовов CreateWebhookDeliveries завершатся ошибкой
+	createErrs int
+}
+
+func (s *memWebhookStore) WebhookCursor(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursor, nil
+}
+
+func (s *memWebhookStore) AdvanceWebhookCursor(_ context.Context, seq int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursor = max(s.cursor, seq)
+	return nil
+}
+
+func (s *memWebhookStore) ListEnabledWebhooks(context.Context) ([]*db.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []*db.Webhook
+	for _, w := range s.hooks {
+		if w.Enabled {
+			c := *w
+			res = append(res, &c)
+		}
+	}
+	return res, nil
+}
+
+func (s *memWebhookStore) CreateWebhookDeliveries(_ context.Context, webhookIDs []int64, seq int64, eventType string, articleID int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.createErrs > 0 {
+		s.createErrs--
+		return 0, errors.New("connection refused")
+	}
+	n := 0
+	for _, id := range webhookIDs {
+		if s.delivery(id, seq) != nil {
+			continue
+		}
+		s.deliveries = append(s.deliveries, &db.WebhookDelivery{
+			ID: int64(len(s.deliveries) + 1), WebhookID: id, EventSeq: seq, EventType: eventType, ArticleID: articleID,
+			State: db.DeliveryPending, NextAttemptAt: time.Now(),
+		})
+		n++
+	}
+	return n, nil
+}
+
+func (s *memWebhookStore) ClaimWebhookDeliveries(_ context.Context, limit int, lease time.Duration) ([]*db.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var res []*db.WebhookDelivery
+	for _, d := range s.deliveries {
+		w := s.hook(d.WebhookID)
+		if len(res) == limit || d.State != db.DeliveryPending || d.NextAttemptAt.After(now) || !w.Enabled {
+			continue
+		}
+		d.NextAttemptAt = now.Add(lease)
+		c := *d
+		c.URL, c.Secret = w.URL, w.Secret
+		res = append(res, &c)
+	}
+	return res, nil
+}
+
+func (s *memWebhookStore) FinishWebhookAttempt(_ context.Context, c *db.WebhookDelivery, a db.WebhookAttempt) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts = append(s.attempts, a)
+	d := s.delivery(c.WebhookID, c.EventSeq)
+	switch {
+	case a.Delivered:
+		d.State = db.DeliveryDelivered
+	case a.Retry > 0:
+		d.State = db.DeliveryPending
+	default:
+		d.State = db.DeliveryFailed
+	}
+	d.Attempts++
+	d.NextAttemptAt = time.Now().Add(a.Retry)
+	w := s.hook(c.WebhookID)
+	if a.Delivered {
+		w.ConsecutiveFailures = 0
+		return false, nil
+	}
+	w.ConsecutiveFailures++
+	if w.Enabled && w.ConsecutiveFailures >= a.DisableAfter {
+		w.Enabled = false
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *memWebhookStore) GetArticleByID(_ context.Context, id int64) (*db.Article, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.articles[id], nil
+}
+
+func (s *memWebhookStore) hook(id int64) *db.Webhook {
+	for _, w := range s.hooks {
+		if w.ID == id {
+			return w
+		}
+	}
+	return nil
+}
+
+func (s *memWebhookStore) delivery(webhookID, seq int64) *db.WebhookDelivery {
+	for _, d := range s.deliveries {
+		if d.WebhookID == webhookID && d.EventSeq == seq {
+			return d
+		}
+	}
+	return nil
+}
+
+// wait ждет, пока cond не станет true под блокировкой хранилища.
+func (s *memWebhookStore) wait(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		ok := cond()
+		s.mu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+type webhookRequest struct {
+	at     time.Time
+	header http.Header
+	body   []byte
+}
+
+// receiver - получатель вебхуков, отвечающий статусами из statuses, а после них - последним.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []webhookRequest
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		status := r.statuses[min(len(r.requests), len(r.statuses)-1)]
+		r.requests = append(r.requests, webhookRequest{at: time.Now(), header: req.Header.Clone(), body: body})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []webhookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]webhookRequest(nil), r.requests...)
+}
+
+// startDispatcher запускает диспетчер над журналом, в котором уже есть одно событие, а курсор стоит
+// на нем, и записывает в журнал новое событие 2 для статьи 1, как AppendArticleEvent.
+func startDispatcher(t *testing.T, store *memWebhookStore, retry WebhookRetry) {
+	t.Helper()
+	events := &memEventLog{}
+	events.append(EventCreated, testArticle(100))
+	store.cursor = 1
+	hub := NewHub(events)
+	d := newWebhookDispatcher(store, hub, 10*time.Millisecond, 10, 2*time.Second, retry)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go d.Run(ctx)
+	hub.Publish(events.append(EventCreated, store.articles[1]))
+	store.wait(t, "delivery of event 2", func() bool { return len(store.deliveries) > 0 })
+}
+
+func newStore(url string) *memWebhookStore {
+	return &memWebhookStore{
+		hooks: []*db.Webhook{{ID: 1, URL: url, Secret: "s3cret", Enabled: true}},
+		articles: map[int64]*db.Article{
+			1: {ID: 1, URL: "https://example.com/a", Title: "Budget approved", Language: "en"},
+		},
+	}
+}
+
+func TestWebhookDeliverySignedAndRetried(t *testing.T) {
+	rcv := newReceiver(t, http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK)
+	store := newStore(rcv.URL)
+	retry := WebhookRetry{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 150 * time.Millisecond, DisableAfter: 10}
+	startDispatcher(t, store, retry)
+	store.wait(t, "delivered state", func() bool { return store.deliveries[0].State == db.DeliveryDelivered })
+
+	reqs := rcv.received()
+	if len(reqs) != 3 {
+		t.Fatalf("receiver got %d requests, want 3", len(reqs))
+	}
+	for i, r := range reqs {
+		// подпись считается так, как ее должен проверять получатель, без WebhookSignature
+		ts := r.header.Get(WebhookTimestampHeader)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(ts + "." + string(r.body)))
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if got := r.header.Get(WebhookSignatureHeader); !hmac.Equal([]byte(got), []byte(want)) {
+			t.Errorf("request %d: signature %q, want %q", i, got, want)
+		}
+		if sec, err := strconv.ParseInt(ts, 10, 64); err != nil || time.Since(time.Unix(sec, 0)) > time.Minute {
+			t.Errorf("request %d: bad timestamp %q", i, ts)
+		}
+		if got := r.header.Get(WebhookEventHeader); got != EventCreated {
+			t.Errorf("request %d: event header %q", i, got)
+		}
+		if got := r.header.Get(WebhookDeliveryHeader); got != "1" {
+			t.Errorf("request %d: delivery header %q", i, got)
+		}
+		var p EventPayload
+		if err := json.Unmarshal(r.body, &p); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if p.Sequence != 2 || p.Type != EventCreated || p.Article.ID != 1 || p.Article.Title != "Budget approved" {
+			t.Errorf("request %d: payload %+v", i, p)
+		}
+	}
+
+	// пауза удваивается от BaseDelay и упирается в MaxDelay
+	store.mu.Lock()
+	attempts := append([]db.WebhookAttempt(nil), store.attempts...)
+	store.mu.Unlock()
+	wantRetry := []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 0}
+	for i, a := range attempts {
+		if a.Retry != wantRetry[i] {
+			t.Errorf("attempt %d: retry after %v, want %v", i+1, a.Retry, wantRetry[i])
+		}
+		if i > 0 && reqs[i].at.Sub(reqs[i-1].at) < wantRetry[i-1] {
+			t.Errorf("attempt %d sent %v after the previous one, want at least %v", i+1, reqs[i].at.Sub(reqs[i-1].at), wantRetry[i-1])
+		}
+	}
+	if attempts[0].Status != http.StatusServiceUnavailable || attempts[0].Err == "" || !attempts[2].Delivered {
+		t.Errorf("attempts %+v", attempts)
+	}
+}
+
+func TestWebhookDisabledAfterConsecutiveFailures(t *testing.T) {
+	rcv := newReceiver(t, http.StatusInternalServerError)
+	store := newStore(rcv.URL)
+	retry := WebhookRetry{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 10 * time.Millisecond, DisableAfter: 3}
+	startDispatcher(t, store, retry)
+	store.wait(t, "webhook disabled", func() bool { return !store.hooks[0].Enabled })
+
+	// выключенной подписке доставка больше не отправляется, но остается ждать включения
+	time.Sleep(100 * time.Millisecond)
+	if n := len(rcv.received()); n != 3 {
+		t.Fatalf("receiver got %d requests, want 3", n)
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if d := store.deliveries[0]; d.State != db.DeliveryPending || d.Attempts != 3 {
+		t.Errorf("delivery %s after %d attempts, want pending after 3", d.State, d.Attempts)
+	}
+}
+
+func TestWebhookFailsAfterMaxAttempts(t *testing.T) {
+	rcv := newReceiver(t, http.StatusBadGateway)
+	store := newStore(rcv.URL)
+	retry := WebhookRetry{MaxAttempts: 2, BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second, DisableAfter: 10}
+	startDispatcher(t, store, retry)
+	store.wait(t, "failed state", func() bool { return store.deliveries[0].State == db.DeliveryFailed })
+	if n := len(rcv.received()); n != 2 {
+		t.Fatalf("receiver got %d requests, want 2", n)
+	}
+}
+
+func TestWebhookCursorAdvancesAfterDeliveriesCreated(t *testing.T) {
+	store := newStore("http://127.0.0.1:1")
+	store.createErrs = 1
+	events := &memEventLog{}
+	events.append(EventCreated, testArticle(100))
+	events.append(EventUpdated, store.articles[1])
+	store.cursor = 1
+	d := newWebhookDispatcher(store, NewHub(events), time.Hour, 10, time.Second, WebhookRetry{MaxAttempts: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.route(ctx)
+	// первая попытка завести доставку падает: курсор не сдвигается, пока событие не разобрано
+	store.wait(t, "failed create", func() bool { return store.createErrs == 0 })
+	store.mu.Lock()
+	if store.cursor != 1 {
+		t.Errorf("cursor moved to %d before the deliveries were created", store.cursor)
+	}
+	store.mu.Unlock()
+	store.wait(t, "cursor at 2", func() bool { return store.cursor == 2 && len(store.deliveries) == 1 })
+}
+
+func TestWebhookRoutingResumesFromCursor(t *testing.T) {
+	rcv := newReceiver(t, http.StatusOK)
+	store := newStore(rcv.URL)
+	events := &memEventLog{}
+	for i := int64(1); i <= 5; i++ {
+		store.articles[i] = testArticle(i)
+	}
+	events.append(EventCreated, store.articles[1])
+	store.cursor = 1
+	// run запускает диспетчер, как после рестарта процесса: новый хаб над тем же журналом и БД
+	run := func() (*Hub, func()) {
+		hub := NewHub(events)
+		d := newWebhookDispatcher(store, hub, 10*time.Millisecond, 10, 2*time.Second, WebhookRetry{MaxAttempts: 1})
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); d.route(ctx) }()
+		go func() { defer wg.Done(); d.dispatch(ctx) }()
+		return hub, func() { cancel(); wg.Wait() }
+	}
+	delivered := func(n int) func() bool {
+		return func() bool {
+			ok := len(store.deliveries) == n && store.cursor == int64(n+1)
+			for _, d := range store.deliveries {
+				ok = ok && d.State == db.DeliveryDelivered
+			}
+			return ok
+		}
+	}
+
+	hub, stop := run()
+	events.append(EventCreated, store.articles[2])
+	hub.Publish(events.append(EventCreated, store.articles[3]))
+	store.wait(t, "events 2 and 3", delivered(2))
+	stop()
+
+	// диспетчер остановлен: события пишутся в журнал, но их некому разобрать
+	hub.Publish(events.append(EventUpdated, store.articles[4]))
+	events.append(EventUpdated, store.articles[5])
+
+	hub, stop = run()
+	defer stop()
+	store.wait(t, "events 4 and 5", delivered(4))
+	// живое событие после догона тоже доходит
+	hub.Publish(events.append(EventUpdated, store.articles[1]))
+	store.wait(t, "event 6", delivered(5))
+	time.Sleep(50 * time.Millisecond)
+
+	var got []int64
+	for _, r := range rcv.received() {
+		var p EventPayload
+		if err := json.Unmarshal(r.body, &p); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p.Sequence)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	expectSeqs(t, got, 2, 6)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	r := WebhookRetry{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, w := range want {
+		if got := r.delay(i + 1); got != w {
+			t.Errorf("delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
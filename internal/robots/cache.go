@@ -0,0 +1,145 @@
+package robots
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// maxRobotsSize - лимит из RFC 9309, остальное можно не читать.
+const maxRobotsSize = 500 * 1024
+
+// errorTTL - сколько держим в кэше "запрещено всё" после 5xx или сетевой ошибки.
+const errorTTL = time.Minute
+
+type entry struct {
+	rules   *Rules
+	expires time.Time
+}
+
+type Cache struct {
+	client    *http.Client
+	userAgent string
+	ttl       time.Duration
+	allowlist []string
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func NewCache(client *http.Client, userAgent string, ttl time.Duration, allowlist []string) *Cache {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	hosts := make([]string, 0, len(allowlist))
+	for _, h := range allowlist {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	return &Cache{
+		client:    client,
+		userAgent: userAgent,
+		ttl:       ttl,
+		allowlist: hosts,
+		entries:   make(map[string]*entry),
+	}
+}
+
+func (c *Cache) allowlisted(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range c.allowlist {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check возвращает ErrDisallowed, если URL закрыт для краулера, и Crawl-delay для хоста.
+func (c *Cache) Check(ctx context.Context, rawURL string) (time.Duration, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, err
+	}
+	if c.allowlisted(u.Hostname()) {
+		return 0, nil
+	}
+	rules := c.rules(ctx, u)
+	path := u.EscapedPath()
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	delay := rules.CrawlDelay(c.userAgent)
+	if !rules.Allowed(c.userAgent, path) {
+		return delay, ErrDisallowed
+	}
+	return delay, nil
+}
+
+// Sitemaps возвращает карты сайта, перечисленные в robots.txt хоста rawURL.
+// allowlist здесь не действует: он отключает только проверку правил.
+func (c *Cache) Sitemaps(ctx context.Context, rawURL string) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return c.rules(ctx, u).Sitemaps, nil
+}
+
+func (c *Cache) rules(ctx context.Context, u *url.URL) *Rules {
+	key := u.Scheme + "://" + u.Host
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(e.expires) {
+		return e.rules
+	}
+	rules, ttl := c.fetch(ctx, key+"/robots.txt")
+	// запрос прерван отменой ctx, а не ответом сайта: запрещающий результат не запоминается
+	if ctx.Err() != nil {
+		return rules
+	}
+	c.mu.Lock()
+	c.entries[key] = &entry{rules: rules, expires: time.Now().Add(ttl)}
+	c.mu.Unlock()
+	return rules
+}
+
+func (c *Cache) fetch(ctx context.Context, robotsURL string) (*Rules, time.Duration) {
+	req, err := http.NewRequestWithContext(ctx, "GET", robotsURL, nil)
+	if err != nil {
+		return disallowAll(), errorTTL
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return disallowAll(), errorTTL
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return Parse(io.LimitReader(resp.Body, maxRobotsSize)), c.ttl
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		// RFC 9309: robots.txt недоступен - ограничений нет.
+		return &Rules{}, c.ttl
+	default:
+		return disallowAll(), errorTTL
+	}
+}
+
+func disallowAll() *Rules {
+	return &Rules{groups: []*group{{agents: []string{"*"}, rules: []rule{{allow: false, pattern: "/"}}}}}
+}
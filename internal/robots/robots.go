@@ -0,0 +1,162 @@
+package robots
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+type group struct {
+	agents     []string
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+type Rules struct {
+	groups   []*group
+	Sitemaps []string
+}
+
+// Parse разбирает robots.txt по RFC 9309 с расширениями Crawl-delay и Sitemap.
+func Parse(r io.Reader) *Rules {
+	res := &Rules{}
+	var cur *group
+	lastWasAgent := false
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if cur == nil || !lastWasAgent {
+				cur = &group{}
+				res.groups = append(res.groups, cur)
+			}
+			cur.agents = append(cur.agents, strings.ToLower(value))
+			lastWasAgent = true
+			continue
+		case "allow", "disallow":
+			if cur != nil && value != "" {
+				cur.rules = append(cur.rules, rule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			if cur != nil {
+				if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+					cur.crawlDelay = time.Duration(secs * float64(time.Second))
+				}
+			}
+		case "sitemap":
+			if value != "" {
+				res.Sitemaps = append(res.Sitemaps, value)
+			}
+		}
+		lastWasAgent = false
+	}
+	return res
+}
+
+// groupsFor возвращает группы, где user-agent совпадает с токеном продукта userAgent целиком
+// без учета регистра (RFC 9309), а если таких нет - группы для "*".
+func (r *Rules) groupsFor(userAgent string) []*group {
+	token := userAgent
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+	var matched, wildcard []*group
+	for _, g := range r.groups {
+		for _, a := range g.agents {
+			if a == "*" {
+				wildcard = append(wildcard, g)
+				continue
+			}
+			if a != "" && strings.EqualFold(token, a) {
+				matched = append(matched, g)
+				break
+			}
+		}
+	}
+	if matched != nil {
+		return matched
+	}
+	return wildcard
+}
+
+// Allowed проверяет путь (вместе с query) по правилам для userAgent.
+// Побеждает самое длинное совпавшее правило, при равной длине - Allow.
+func (r *Rules) Allowed(userAgent, path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	allowed := true
+	bestLen := -1
+	for _, g := range r.groupsFor(userAgent) {
+		for _, rl := range g.rules {
+			if !matchPattern(rl.pattern, path) {
+				continue
+			}
+			if len(rl.pattern) > bestLen || (len(rl.pattern) == bestLen && rl.allow) {
+				bestLen = len(rl.pattern)
+				allowed = rl.allow
+			}
+		}
+	}
+	return allowed
+}
+
+func (r *Rules) CrawlDelay(userAgent string) time.Duration {
+	var d time.Duration
+	for _, g := range r.groupsFor(userAgent) {
+		if g.crawlDelay > d {
+			d = g.crawlDelay
+		}
+	}
+	return d
+}
+
+// matchPattern сопоставляет путь с шаблоном, где "*" - любая последовательность символов,
+// а "$" в конце привязывает шаблон к концу пути.
+func matchPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	if len(parts) == 1 {
+		return !anchored || pos == len(path)
+	}
+	for _, p := range parts[1 : len(parts)-1] {
+		i := strings.Index(path[pos:], p)
+		if i < 0 {
+			return false
+		}
+		pos += i + len(p)
+	}
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(path[pos:], last)
+	}
+	return strings.Contains(path[pos:], last)
+}
@@ -0,0 +1,121 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/pipeline"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func crawlToProto(c *db.Crawl) *proto.Crawl {
+	return &proto.Crawl{
+		Id:          fmt.Sprintf("%d", c.ID),
+		SeedUrl:     c.SeedURL,
+		State:       string(c.State),
+		MaxDepth:    int32(c.MaxDepth),
+		MaxPages:    int32(c.MaxPages),
+		Scope:       c.Scope,
+		Include:     c.Include,
+		Exclude:     c.Exclude,
+		PagesQueued: int32(c.PagesQueued),
+		PagesDone:   int32(c.PagesDone),
+		PagesFailed: int32(c.PagesFailed),
+		CreatedAt:   c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   c.UpdatedAt.Format(time.RFC3339),
+		FinishedAt:  formatTime(c.FinishedAt),
+	}
+}
+
+func (s *Server) startCrawl(ctx context.Context, seed string, opts *proto.CrawlOptions) (*proto.SubmitUrlResponse, error) {
+	crawlID, jobID, err := s.crawls.Start(ctx, seed, pipeline.CrawlOptions{
+		MaxDepth: int(opts.MaxDepth),
+		MaxPages: int(opts.MaxPages),
+		Scope:    opts.Scope,
+		Include:  opts.Include,
+		Exclude:  opts.Exclude,
+	})
+	if errors.Is(err, pipeline.ErrCrawlOptions) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SubmitUrlResponse{
+		Id:      fmt.Sprintf("%d", jobID),
+		Message: "crawl started",
+		CrawlId: fmt.Sprintf("%d", crawlID),
+	}, nil
+}
+
+func (s *Server) getCrawl(ctx context.Context, rawID string) (*db.Crawl, error) {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid crawl id %q", rawID)
+	}
+	c, err := s.repo.GetCrawl(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "crawl %d not found", id)
+	}
+	return c, err
+}
+
+func (s *Server) GetCrawl(ctx context.Context, req *proto.GetCrawlRequest) (*proto.Crawl, error) {
+	c, err := s.getCrawl(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return crawlToProto(c), nil
+}
+
+func (s *Server) ListCrawls(ctx context.Context, req *proto.ListCrawlsRequest) (*proto.ListCrawlsResponse, error) {
+	if req == nil {
+		req = &proto.ListCrawlsRequest{}
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	switch db.CrawlState(req.State) {
+	case "", db.CrawlRunning, db.CrawlFinished, db.CrawlCanceled:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown crawl state %q", req.State)
+	}
+	if err := checkOffset(req.Offset); err != nil {
+		return nil, err
+	}
+	crawls, err := s.repo.ListCrawls(ctx, db.CrawlState(req.State), req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListCrawlsResponse{Crawls: make([]*proto.Crawl, 0, len(crawls))}
+	for _, c := range crawls {
+		resp.Crawls = append(resp.Crawls, crawlToProto(c))
+	}
+	return resp, nil
+}
+
+// CancelCrawl возвращает обход после отмены. Отмена завершенного обхода - FailedPrecondition.
+func (s *Server) CancelCrawl(ctx context.Context, req *proto.CancelCrawlRequest) (*proto.Crawl, error) {
+	c, err := s.getCrawl(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.crawls.Cancel(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "crawl %d is not running", c.ID)
+	}
+	if c, err = s.repo.GetCrawl(ctx, c.ID); err != nil {
+		return nil, err
+	}
+	return crawlToProto(c), nil
+}
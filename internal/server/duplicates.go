@@ -0,0 +1,37 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/simhash"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"strconv"
+)
+
+func (s *Server) GetDuplicates(ctx context.Context, req *proto.GetDuplicatesRequest) (*proto.GetDuplicatesResponse, error) {
+	id, err := strconv.ParseInt(req.ArticleId, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	art, err := s.repo.GetArticleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.GetDuplicatesResponse{ClusterId: formatID(art.ClusterID)}
+	if art.ClusterID == 0 {
+		return resp, nil
+	}
+	members, err := s.repo.ListClusterArticles(ctx, art.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if m.ID == art.ID {
+			continue
+		}
+		resp.Duplicates = append(resp.Duplicates, &proto.DuplicateArticle{
+			Article:  articleToProto(m),
+			Distance: int32(simhash.Distance(art.SimHash, m.SimHash)),
+		})
+	}
+	return resp, nil
+}
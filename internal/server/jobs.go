@@ -0,0 +1,89 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func jobToProto(j *db.CrawlJob, events []*db.JobEvent) *proto.JobStatus {
+	js := &proto.JobStatus{
+		Id:        fmt.Sprintf("%d", j.ID),
+		Url:       j.URL,
+		State:     string(j.State),
+		Attempts:  int32(j.Attempts),
+		Error:     j.LastError,
+		CreatedAt: j.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: j.UpdatedAt.Format(time.RFC3339),
+	}
+	if j.ArticleID != 0 {
+		js.ArticleId = fmt.Sprintf("%d", j.ArticleID)
+	}
+	if j.CrawlID != 0 {
+		js.CrawlId = fmt.Sprintf("%d", j.CrawlID)
+		js.Depth = int32(j.Depth)
+	}
+	js.SourceId = formatID(j.SourceID)
+	for _, e := range events {
+		js.Stages = append(js.Stages, &proto.JobStage{
+			Stage:     e.Stage,
+			Status:    e.Status,
+			Error:     e.Error,
+			CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return js
+}
+
+func (s *Server) GetJobStatus(ctx context.Context, req *proto.GetJobStatusRequest) (*proto.JobStatus, error) {
+	id, err := strconv.ParseInt(req.Id, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid job id %q", req.Id)
+	}
+	j, err := s.repo.GetJob(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "job %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.ListJobEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return jobToProto(j, events), nil
+}
+
+func (s *Server) ListJobs(ctx context.Context, req *proto.ListJobsRequest) (*proto.ListJobsResponse, error) {
+	if req == nil {
+		req = &proto.ListJobsRequest{}
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	switch db.JobState(req.State) {
+	case "", db.JobQueued, db.JobFetching, db.JobParsing, db.JobStored, db.JobDuplicate, db.JobNotModified, db.JobFailed, db.JobCanceled:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown job state %q", req.State)
+	}
+	if err := checkOffset(req.Offset); err != nil {
+		return nil, err
+	}
+	jobs, err := s.repo.ListJobs(ctx, db.JobState(req.State), req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListJobsResponse{Jobs: make([]*proto.JobStatus, 0, len(jobs))}
+	for _, j := range jobs {
+		resp.Jobs = append(resp.Jobs, jobToProto(j, nil))
+	}
+	return resp, nil
+}
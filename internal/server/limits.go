@@ -0,0 +1,36 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"time"
+)
+
+func (s *Server) ListDomainLimits(ctx context.Context, req *proto.ListDomainLimitsRequest) (*proto.ListDomainLimitsResponse, error) {
+	ss := s.limiter.StoreStats()
+	resp := &proto.ListDomainLimitsResponse{
+		TrackedDomains:    int64(ss.Tracked),
+		MaxDomains:        int64(ss.MaxDomains),
+		IdleEvictions:     ss.IdleEvictions,
+		CapacityEvictions: ss.CapacityEvictions,
+		Backend:           ss.Backend,
+		BackendErrors:     ss.BackendErrors,
+	}
+	for _, st := range s.limiter.Stats(req.Domain) {
+		l := &proto.DomainLimit{
+			Domain:            st.Domain,
+			Pattern:           st.Pattern,
+			ConfiguredRps:     st.ConfiguredRPS,
+			EffectiveRps:      st.EffectiveRPS,
+			Burst:             int32(st.Burst),
+			Factor:            st.Factor,
+			CrawlDelaySeconds: st.CrawlDelay.Seconds(),
+			LatencyMs:         st.Latency.Milliseconds(),
+		}
+		if st.BlockedUntil.After(time.Now()) {
+			l.BlockedUntil = st.BlockedUntil.Format(time.RFC3339)
+		}
+		resp.Limits = append(resp.Limits, l)
+	}
+	return resp, nil
+}
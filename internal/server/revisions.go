@@ -0,0 +1,114 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/diff"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func revisionToProto(r *db.ArticleRevision) *proto.ArticleRevision {
+	return &proto.ArticleRevision{
+		Id:          fmt.Sprintf("%d", r.ID),
+		ArticleId:   fmt.Sprintf("%d", r.ArticleID),
+		ContentHash: r.ContentHash,
+		Title:       r.Title,
+		Summary:     r.Summary,
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		LastSeenAt:  r.LastSeenAt.Format(time.RFC3339),
+	}
+}
+
+func diffOpsToProto(ops []diff.Op) ([]*proto.DiffOp, int32, int32) {
+	res := make([]*proto.DiffOp, 0, len(ops))
+	var inserted, deleted int32
+	for _, op := range ops {
+		res = append(res, &proto.DiffOp{Type: string(op.Type), Text: op.Text})
+		switch op.Type {
+		case diff.Insert:
+			inserted += int32(len(strings.Fields(op.Text)))
+		case diff.Delete:
+			deleted += int32(len(strings.Fields(op.Text)))
+		}
+	}
+	return res, inserted, deleted
+}
+
+func (s *Server) ListArticleRevisions(ctx context.Context, req *proto.ListArticleRevisionsRequest) (*proto.ListArticleRevisionsResponse, error) {
+	id, err := strconv.ParseInt(req.ArticleId, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	revs, err := s.repo.ListArticleRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListArticleRevisionsResponse{Revisions: make([]*proto.ArticleRevision, 0, len(revs))}
+	for _, r := range revs {
+		resp.Revisions = append(resp.Revisions, revisionToProto(r))
+	}
+	return resp, nil
+}
+
+func (s *Server) DiffArticleRevisions(ctx context.Context, req *proto.DiffArticleRevisionsRequest) (*proto.DiffArticleRevisionsResponse, error) {
+	fromID, err := strconv.ParseInt(req.FromRevisionId, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	toID, err := strconv.ParseInt(req.ToRevisionId, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.repo.GetArticleRevision(ctx, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.repo.GetArticleRevision(ctx, toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.ArticleID != to.ArticleID {
+		return nil, fmt.Errorf("revisions %d and %d belong to different articles", fromID, toID)
+	}
+	var bodyOps []diff.Op
+	switch req.Granularity {
+	case "", "line":
+		bodyOps, err = diff.Lines(from.Body, to.Body)
+	case "word":
+		bodyOps, err = diff.Words(from.Body, to.Body)
+	default:
+		return nil, fmt.Errorf("unknown granularity %q", req.Granularity)
+	}
+	if err != nil {
+		return nil, diffError(err)
+	}
+	rawTitleOps, err := diff.Words(from.Title, to.Title)
+	if err != nil {
+		return nil, diffError(err)
+	}
+	titleOps, _, _ := diffOpsToProto(rawTitleOps)
+	body, inserted, deleted := diffOpsToProto(bodyOps)
+	return &proto.DiffArticleRevisionsResponse{
+		From:     revisionToProto(from),
+		To:       revisionToProto(to),
+		TitleOps: titleOps,
+		BodyOps:  body,
+		Inserted: inserted,
+		Deleted:  deleted,
+	}, nil
+}
+
+func diffError(err error) error {
+	if errors.Is(err, diff.ErrTooLarge) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return err
+}
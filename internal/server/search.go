@@ -0,0 +1,67 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseSearchTime принимает RFC3339 или дату YYYY-MM-DD (начало суток в UTC).
+func parseSearchTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q: want RFC3339 or YYYY-MM-DD", s)
+}
+
+func (s *Server) SearchArticles(ctx context.Context, req *proto.SearchArticlesRequest) (*proto.SearchArticlesResponse, error) {
+	if req == nil || strings.TrimSpace(req.Query) == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty query")
+	}
+	from, err := parseSearchTime(req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	to, err := parseSearchTime(req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if err := checkOffset(req.Offset); err != nil {
+		return nil, err
+	}
+	hits, err := s.repo.SearchArticles(ctx, db.SearchParams{
+		Query:    req.Query,
+		Language: req.Language,
+		Domains:  req.Domains,
+		From:     from,
+		To:       to,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.SearchArticlesResponse{Hits: make([]*proto.SearchHit, 0, len(hits))}
+	for _, h := range hits {
+		resp.Hits = append(resp.Hits, &proto.SearchHit{
+			Article: articleToProto(h.Article),
+			Rank:    h.Rank,
+			Snippet: h.Snippet,
+		})
+	}
+	return resp, nil
+}
@@ -2,6 +2,7 @@ package grpcserver
 
 import (
 	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/limiter"
 	"ArticleCrawler/internal/pipeline"
 	"ArticleCrawler/pkg/proto"
 	"context"
@@ -11,8 +12,11 @@ import (
 	"strconv"
 	"time"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 )
 
@@ -20,24 +24,51 @@ type Server struct {
 	proto.UnimplementedCrawlerServer
 	repo     *db.Repository
 	hub      *pipeline.Hub
-	submitCh chan pipeline.FetchJob
+	jobs     *pipeline.JobQueue
+	crawls   *pipeline.Crawls
+	sources  *pipeline.SourcePoller
+	sitemaps *pipeline.SitemapImporter
+	webhooks *pipeline.WebhookDispatcher
+	limiter  *limiter.DomainLimiter
 	grpcSrv  *grpc.Server
+
+	sourceInterval time.Duration // интервал опроса ленты по умолчанию
 }
 
-func NewServer(repo *db.Repository, hub *pipeline.Hub, submitCh chan pipeline.FetchJob) *Server {
+func NewServer(repo *db.Repository, hub *pipeline.Hub, jobs *pipeline.JobQueue, crawls *pipeline.Crawls, lim *limiter.DomainLimiter) *Server {
 	return &Server{
-		repo:     repo,
-		hub:      hub,
-		submitCh: submitCh,
+		repo:    repo,
+		hub:     hub,
+		jobs:    jobs,
+		crawls:  crawls,
+		limiter: lim,
 	}
 }
 
+// SetSources задает интервал опроса новых лент по умолчанию и поллер, которого AddSource будит
+// для первого опроса. p == nil - поллер на этой реплике выключен, ленты опросят другие реплики.
+func (s *Server) SetSources(p *pipeline.SourcePoller, defaultInterval time.Duration) {
+	s.sources = p
+	s.sourceInterval = defaultInterval
+}
+
+// SetSitemaps задает загрузчик карт сайта для SubmitSitemap. Без него SubmitSitemap отвечает Unavailable.
+func (s *Server) SetSitemaps(imp *pipeline.SitemapImporter) {
+	s.sitemaps = imp
+}
+
+// SetWebhooks задает доставщик вебхуков, которому сообщается об изменении подписок.
+// d == nil - доставщик на этой реплике выключен.
+func (s *Server) SetWebhooks(d *pipeline.WebhookDispatcher) {
+	s.webhooks = d
+}
+
 func (s *Server) Start(ctx context.Context, addr string) error {
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		return err
 	}
-	s.grpcSrv = grpc.NewServer()
+	s.grpcSrv = grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
 	reflection.Register(s.grpcSrv)
 	proto.RegisterCrawlerServer(s.grpcSrv, s)
 	go func() {
@@ -54,16 +85,57 @@ func (s *Server) Start(ctx context.Context, addr string) error {
 	return nil
 }
 
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func articleToProto(a *db.Article) *proto.Article {
+	return &proto.Article{
+		Id:              fmt.Sprintf("%d", a.ID),
+		Url:             a.URL,
+		Title:           a.Title,
+		Body:            a.Body,
+		Summary:         a.Summary,
+		ContentHash:     a.ContentHash,
+		Language:        a.Language,
+		ReadTimeMinutes: a.ReadTimeMinutes,
+		CreatedAt:       a.CreatedAt.Format(time.RFC3339),
+		Author:          a.Author,
+		PublishedAt:     formatTime(a.PublishedAt),
+		ModifiedAt:      formatTime(a.ModifiedAt),
+		CanonicalUrl:    a.CanonicalURL,
+		SiteName:        a.SiteName,
+		ImageUrl:        a.ImageURL,
+		Section:         a.Section,
+		Keywords:        a.Keywords,
+		ClusterId:       formatID(a.ClusterID),
+		SourceId:        formatID(a.SourceID),
+	}
+}
+
+// formatID возвращает "" для нулевого id, которым в db помечаются отсутствующие ссылки.
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", id)
+}
+
 func (s *Server) SubmitUrl(ctx context.Context, req *proto.SubmitUrlRequest) (*proto.SubmitUrlResponse, error) {
 	if req == nil || req.Url == "" {
 		return &proto.SubmitUrlResponse{Id: "", Message: "empty url"}, fmt.Errorf("empty url")
 	}
-	select {
-	case s.submitCh <- pipeline.FetchJob{URL: req.Url}:
-		return &proto.SubmitUrlResponse{Id: req.Url, Message: "submitted"}, nil
-	default:
-		return &proto.SubmitUrlResponse{Id: req.Url, Message: "pipeline busy"}, nil
+	if req.Crawl != nil {
+		return s.startCrawl(ctx, req.Url, req.Crawl)
+	}
+	id, err := s.jobs.Submit(ctx, req.Url)
+	if err != nil {
+		return nil, err
 	}
+	return &proto.SubmitUrlResponse{Id: fmt.Sprintf("%d", id), Message: "submitted"}, nil
 }
 
 func (s *Server) GetArticle(ctx context.Context, req *proto.GetArticleRequest) (*proto.Article, error) {
@@ -75,71 +147,32 @@ func (s *Server) GetArticle(ctx context.Context, req *proto.GetArticleRequest) (
 	if err != nil {
 		return nil, err
 	}
-	return &proto.Article{
-		Id:              fmt.Sprintf("%d", art.ID),
-		Url:             art.URL,
-		Title:           art.Title,
-		Body:            art.Body,
-		Summary:         art.Summary,
-		ContentHash:     art.ContentHash,
-		Language:        art.Language,
-		ReadTimeMinutes: art.ReadTimeMinutes,
-		CreatedAt:       art.CreatedAt.Format(time.RFC3339),
-	}, nil
+	return articleToProto(art), nil
 }
 
 func (s *Server) ListArticles(ctx context.Context, req *proto.ListArticlesRequest) (*proto.ListArticlesResponse, error) {
 	if req == nil {
 		req = &proto.ListArticlesRequest{Limit: 20, Offset: 0}
 	}
+	if err := checkOffset(req.Offset); err != nil {
+		return nil, err
+	}
 	arts, err := s.repo.ListArticles(ctx, req.Limit, req.Offset)
 	if err != nil {
 		return nil, err
 	}
 	resp := &proto.ListArticlesResponse{Articles: make([]*proto.Article, 0, len(arts))}
 	for _, a := range arts {
-		resp.Articles = append(resp.Articles, &proto.Article{
-			Id:              fmt.Sprintf("%d", a.ID),
-			Url:             a.URL,
-			Title:           a.Title,
-			Body:            a.Body,
-			Summary:         a.Summary,
-			ContentHash:     a.ContentHash,
-			Language:        a.Language,
-			ReadTimeMinutes: a.ReadTimeMinutes,
-			CreatedAt:       a.CreatedAt.Format(time.RFC3339),
-		})
+		resp.Articles = append(resp.Articles, articleToProto(a))
 	}
 	return resp, nil
 }
 
-func (s *Server) StreamNewArticles(req *proto.StreamNewArticlesRequest, stream proto.Crawler_StreamNewArticlesServer) error {
-	id := fmt.Sprintf("sub-%d", time.Now().UnixNano())
-	ch := s.hub.Subscribe(id)
-	defer s.hub.Unsubscribe(id)
-	for {
-		select {
-		case art, ok := <-ch:
-			if !ok {
-				return nil
-			}
-			a := &proto.Article{
-				Id:              fmt.Sprintf("%d", art.ID),
-				Url:             art.URL,
-				Title:           art.Title,
-				Body:            art.Body,
-				Summary:         art.Summary,
-				ContentHash:     art.ContentHash,
-				Language:        art.Language,
-				ReadTimeMinutes: art.ReadTimeMinutes,
-				CreatedAt:       art.CreatedAt.Format(time.RFC3339),
-			}
-			if err := stream.Send(a); err != nil {
-				log.Printf("[grpc stream] send error: %v", err)
-				return err
-			}
-		case <-stream.Context().Done():
-			return nil
-		}
+// checkOffset проверяет смещение постраничного списка: отрицательный OFFSET Postgres отвергает,
+// и без проверки неверный запрос выглядел бы как внутренняя ошибка.
+func checkOffset(offset int32) error {
+	if offset < 0 {
+		return status.Errorf(codes.InvalidArgument, "offset must not be negative, got %d", offset)
 	}
+	return nil
 }
@@ -0,0 +1,98 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/pipeline"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func sitemapImportToProto(imp *db.SitemapImport) *proto.SitemapImport {
+	return &proto.SitemapImport{
+		Id:              fmt.Sprintf("%d", imp.ID),
+		State:           string(imp.State),
+		Sitemaps:        imp.Sitemaps,
+		SitemapsFetched: int32(imp.SitemapsFetched),
+		SitemapsFailed:  int32(imp.SitemapsFailed),
+		UrlsFound:       int32(imp.URLsFound),
+		UrlsMatched:     int32(imp.URLsMatched),
+		UrlsEnqueued:    int32(imp.URLsEnqueued),
+		LastError:       imp.LastError,
+		CreatedAt:       imp.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       imp.UpdatedAt.Format(time.RFC3339),
+		FinishedAt:      formatTime(imp.FinishedAt),
+	}
+}
+
+// SubmitSitemap создает загрузку карт и сразу возвращает ее; карты читаются в фоне,
+// ход загрузки виден через GetSitemapImport.
+func (s *Server) SubmitSitemap(ctx context.Context, req *proto.SubmitSitemapRequest) (*proto.SitemapImport, error) {
+	if s.sitemaps == nil {
+		return nil, status.Error(codes.Unavailable, "sitemap imports are not available")
+	}
+	if req == nil || (req.Url == "" && req.Site == "") {
+		return nil, status.Error(codes.InvalidArgument, "either url or site is required")
+	}
+	from, err := parseSearchTime(req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	to, err := parseSearchTime(req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	roots := []string{req.Url}
+	if req.Url == "" {
+		if roots, err = s.sitemaps.Discover(ctx, req.Site); err != nil {
+			return nil, sitemapError(err)
+		}
+	}
+	id, err := s.sitemaps.Start(ctx, roots, pipeline.SitemapOptions{
+		From:    from,
+		To:      to,
+		Include: req.Include,
+		Exclude: req.Exclude,
+		MaxURLs: int(req.MaxUrls),
+	})
+	if err != nil {
+		return nil, sitemapError(err)
+	}
+	return s.getSitemapImport(ctx, id)
+}
+
+func (s *Server) GetSitemapImport(ctx context.Context, req *proto.GetSitemapImportRequest) (*proto.SitemapImport, error) {
+	id, err := strconv.ParseInt(req.Id, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sitemap import id %q", req.Id)
+	}
+	return s.getSitemapImport(ctx, id)
+}
+
+func (s *Server) getSitemapImport(ctx context.Context, id int64) (*proto.SitemapImport, error) {
+	imp, err := s.repo.GetSitemapImport(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "sitemap import %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sitemapImportToProto(imp), nil
+}
+
+func sitemapError(err error) error {
+	switch {
+	case errors.Is(err, pipeline.ErrSitemapOptions):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, pipeline.ErrImportsBusy):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// minSourceInterval - чаще ленты не опрашиваются, даже если клиент просит.
+const minSourceInterval = time.Minute
+
+func sourceToProto(src *db.Source) *proto.Source {
+	return &proto.Source{
+		Id:              fmt.Sprintf("%d", src.ID),
+		Url:             src.URL,
+		Format:          src.Format,
+		Title:           src.Title,
+		IntervalSeconds: int32(src.Interval.Seconds()),
+		LastPolledAt:    formatTime(src.LastPolledAt),
+		NextPollAt:      src.NextPollAt.Format(time.RFC3339),
+		LastError:       src.LastError,
+		Entries:         int32(src.Entries),
+		CreatedAt:       src.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *Server) AddSource(ctx context.Context, req *proto.AddSourceRequest) (*proto.Source, error) {
+	if req == nil || req.Url == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty url")
+	}
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "source url must be absolute http(s), got %q", req.Url)
+	}
+	interval := time.Duration(req.IntervalSeconds) * time.Second
+	if interval == 0 {
+		interval = s.sourceInterval
+	}
+	if interval < minSourceInterval {
+		return nil, status.Errorf(codes.InvalidArgument, "interval_seconds must be at least %d", int(minSourceInterval.Seconds()))
+	}
+	src, err := s.repo.AddSource(ctx, u.String(), interval)
+	if errors.Is(err, db.ErrSourceExists) {
+		return nil, status.Errorf(codes.AlreadyExists, "source %s already exists", u.String())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.sources != nil {
+		s.sources.Wake()
+	}
+	return sourceToProto(src), nil
+}
+
+func (s *Server) ListSources(ctx context.Context, req *proto.ListSourcesRequest) (*proto.ListSourcesResponse, error) {
+	if req == nil {
+		req = &proto.ListSourcesRequest{}
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if err := checkOffset(req.Offset); err != nil {
+		return nil, err
+	}
+	sources, err := s.repo.ListSources(ctx, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListSourcesResponse{Sources: make([]*proto.Source, 0, len(sources))}
+	for _, src := range sources {
+		resp.Sources = append(resp.Sources, sourceToProto(src))
+	}
+	return resp, nil
+}
+
+func (s *Server) RemoveSource(ctx context.Context, req *proto.RemoveSourceRequest) (*proto.RemoveSourceResponse, error) {
+	id, err := strconv.ParseInt(req.Id, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid source id %q", req.Id)
+	}
+	ok, err := s.repo.RemoveSource(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "source %d not found", id)
+	}
+	return &proto.RemoveSourceResponse{}, nil
+}
@@ -0,0 +1,198 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/pipeline"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// streamFilter переводит фильтры запроса в фильтр подписки хаба.
+func streamFilter(req *proto.StreamNewArticlesRequest) (pipeline.SubscriptionFilter, error) {
+	f := pipeline.SubscriptionFilter{
+		Domains:     req.Domains,
+		Language:    req.Language,
+		TitleQuery:  req.TitleQuery,
+		TitleRegex:  req.TitleRegex,
+		MinReadTime: req.MinReadTime,
+	}
+	if req.SourceId != "" {
+		id, err := strconv.ParseInt(req.SourceId, 10, 64)
+		if err != nil {
+			return f, status.Errorf(codes.InvalidArgument, "invalid source id %q", req.SourceId)
+		}
+		f.SourceID = id
+	}
+	return f, nil
+}
+
+// articleFields проверяет имена полей Article из запроса. nil - передавать все поля.
+func articleFields(fields []string) (map[protoreflect.Name]bool, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	desc := (&proto.Article{}).ProtoReflect().Descriptor().Fields()
+	keep := map[protoreflect.Name]bool{"id": true}
+	for _, f := range fields {
+		name := protoreflect.Name(strings.TrimSpace(f))
+		if desc.ByName(name) == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown article field %q", f)
+		}
+		keep[name] = true
+	}
+	return keep, nil
+}
+
+// selectFields очищает поля a, которых нет в keep.
+func selectFields(a *proto.Article, keep map[protoreflect.Name]bool) *proto.Article {
+	if keep == nil {
+		return a
+	}
+	m := a.ProtoReflect()
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !keep[fd.Name()] {
+			m.Clear(fd)
+		}
+		return true
+	})
+	return a
+}
+
+// subscribe проверяет фильтры и поля запроса и подписывается на хаб.
+func (s *Server) subscribe(ctx context.Context, id string, req *proto.StreamNewArticlesRequest) (*pipeline.Subscription, map[protoreflect.Name]bool, error) {
+	filter, err := streamFilter(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	keep, err := articleFields(req.Fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	sub, err := s.hub.Subscribe(ctx, id, filter, req.SinceSequence)
+	if errors.Is(err, pipeline.ErrSubscriptionFilter) {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return sub, keep, nil
+}
+
+// StreamNewArticles - прежний поток новых статей: только события "created", без типа и sequence.
+func (s *Server) StreamNewArticles(req *proto.StreamNewArticlesRequest, stream proto.Crawler_StreamNewArticlesServer) error {
+	return s.streamEvents(req, stream, func(ev *pipeline.Event, keep map[protoreflect.Name]bool) error {
+		if ev.Type != pipeline.EventCreated {
+			return nil
+		}
+		return stream.Send(selectFields(articleToProto(ev.Article), keep))
+	})
+}
+
+func (s *Server) StreamArticleEvents(req *proto.StreamNewArticlesRequest, stream proto.Crawler_StreamArticleEventsServer) error {
+	return s.streamEvents(req, stream, func(ev *pipeline.Event, keep map[protoreflect.Name]bool) error {
+		return stream.Send(&proto.ArticleEvent{Type: ev.Type, Article: selectFields(articleToProto(ev.Article), keep), Sequence: ev.Seq})
+	})
+}
+
+// streamEvents подписывается на хаб и передает события в send, пока клиент не отключится.
+func (s *Server) streamEvents(req *proto.StreamNewArticlesRequest, stream grpc.ServerStream, send func(*pipeline.Event, map[protoreflect.Name]bool) error) error {
+	id := fmt.Sprintf("sub-%d", time.Now().UnixNano())
+	sub, keep, err := s.subscribe(stream.Context(), id, req)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	// заголовки уходят сразу после подписки: по ним клиент понимает, что фильтры приняты,
+	// не дожидаясь первого события
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	for {
+		ev, err := sub.Next(ctx)
+		if ctx.Err() != nil || (ev == nil && err == nil) {
+			return nil
+		}
+		if err != nil {
+			// клиент переподключится с since_sequence последнего полученного события
+			log.Printf("[grpc stream] %s: %v", id, err)
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		if err := send(ev, keep); err != nil {
+			log.Printf("[grpc stream] send error: %v", err)
+			return err
+		}
+	}
+}
+
+// ArticleStream - подписка на хаб для HTTP-клиентов (SSE, WebSocket): события отдаются в JSON.
+type ArticleStream struct {
+	sub  *pipeline.Subscription
+	keep map[protoreflect.Name]bool
+}
+
+// OpenStream подписывается на хаб с фильтрами, выбором полей и since_sequence из req,
+// как StreamNewArticles. Ошибки - gRPC-статусы. Подписку закрывает Close.
+func (s *Server) OpenStream(ctx context.Context, id string, req *proto.StreamNewArticlesRequest) (*ArticleStream, error) {
+	sub, keep, err := s.subscribe(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	return &ArticleStream{sub: sub, keep: keep}, nil
+}
+
+// Next ждет следующее событие и возвращает его вместе с JSON. nil без ошибки - хаб закрыл подписку.
+func (st *ArticleStream) Next(ctx context.Context) (*pipeline.Event, []byte, error) {
+	ev, err := st.sub.Next(ctx)
+	if ev == nil || err != nil {
+		return nil, nil, err
+	}
+	data, err := eventJSON(ev, st.keep)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ev, data, nil
+}
+
+func (st *ArticleStream) Close() {
+	st.sub.Close()
+}
+
+// eventJSON - событие в формате EventPayload (как у вебхуков); с выбором полей в статье
+// остаются только поля из keep.
+func eventJSON(ev *pipeline.Event, keep map[protoreflect.Name]bool) ([]byte, error) {
+	p := pipeline.NewEventPayload(ev.Seq, ev.Type, ev.Article)
+	if keep == nil {
+		return json.Marshal(p)
+	}
+	raw, err := json.Marshal(p.Article)
+	if err != nil {
+		return nil, err
+	}
+	var article map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &article); err != nil {
+		return nil, err
+	}
+	for name := range article {
+		if !keep[protoreflect.Name(name)] {
+			delete(article, name)
+		}
+	}
+	return json.Marshal(struct {
+		Sequence int64                      `json:"sequence"`
+		Type     string                     `json:"type"`
+		Article  map[string]json.RawMessage `json:"article"`
+	}{p.Sequence, p.Type, article})
+}
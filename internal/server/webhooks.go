@@ -0,0 +1,211 @@
+package grpcserver
+
+import (
+	"ArticleCrawler/internal/db"
+	"ArticleCrawler/internal/pipeline"
+	"ArticleCrawler/pkg/proto"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func webhookToProto(w *db.Webhook) *proto.Webhook {
+	return &proto.Webhook{
+		Id:                  fmt.Sprintf("%d", w.ID),
+		Url:                 w.URL,
+		Domains:             w.Domains,
+		Language:            w.Language,
+		TitleQuery:          w.TitleQuery,
+		TitleRegex:          w.TitleRegex,
+		MinReadTime:         w.MinReadTime,
+		SourceId:            formatID(w.SourceID),
+		EventTypes:          w.EventTypes,
+		Enabled:             w.Enabled,
+		ConsecutiveFailures: int32(w.ConsecutiveFailures),
+		DisabledReason:      w.DisabledReason,
+		CreatedAt:           w.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func deliveryToProto(d *db.WebhookDelivery) *proto.WebhookDelivery {
+	return &proto.WebhookDelivery{
+		Id:            fmt.Sprintf("%d", d.ID),
+		WebhookId:     fmt.Sprintf("%d", d.WebhookID),
+		EventSequence: d.EventSeq,
+		EventType:     d.EventType,
+		ArticleId:     fmt.Sprintf("%d", d.ArticleID),
+		State:         string(d.State),
+		Attempts:      int32(d.Attempts),
+		NextAttemptAt: d.NextAttemptAt.Format(time.RFC3339),
+		LastStatus:    int32(d.LastStatus),
+		LastError:     d.LastError,
+		CreatedAt:     d.CreatedAt.Format(time.RFC3339),
+		DeliveredAt:   formatTime(d.DeliveredAt),
+	}
+}
+
+func parseWebhookID(id string) (int64, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid webhook id %q", id)
+	}
+	return n, nil
+}
+
+func (s *Server) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest) (*proto.Webhook, error) {
+	if req == nil || req.Url == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty url")
+	}
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook url must be absolute http(s), got %q", req.Url)
+	}
+	for _, t := range req.EventTypes {
+		if t != pipeline.EventCreated && t != pipeline.EventUpdated {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event type %q: want %q or %q", t, pipeline.EventCreated, pipeline.EventUpdated)
+		}
+	}
+	filter, err := streamFilter(&proto.StreamNewArticlesRequest{
+		Domains:     req.Domains,
+		Language:    req.Language,
+		TitleQuery:  req.TitleQuery,
+		TitleRegex:  req.TitleRegex,
+		MinReadTime: req.MinReadTime,
+		SourceId:    req.SourceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := pipeline.ValidateFilter(filter); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	secret := req.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(b)
+	}
+	w, err := s.repo.CreateWebhook(ctx, &db.Webhook{
+		URL:         u.String(),
+		Secret:      secret,
+		Domains:     filter.Domains,
+		Language:    filter.Language,
+		TitleQuery:  filter.TitleQuery,
+		TitleRegex:  filter.TitleRegex,
+		MinReadTime: filter.MinReadTime,
+		SourceID:    filter.SourceID,
+		EventTypes:  req.EventTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.reloadWebhooks()
+	resp := webhookToProto(w)
+	resp.Secret = w.Secret
+	return resp, nil
+}
+
+func (s *Server) ListWebhooks(ctx context.Context, req *proto.ListWebhooksRequest) (*proto.ListWebhooksResponse, error) {
+	if req == nil {
+		req = &proto.ListWebhooksRequest{}
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if err := checkOffset(req.Offset); err != nil {
+		return nil, err
+	}
+	hooks, err := s.repo.ListWebhooks(ctx, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListWebhooksResponse{Webhooks: make([]*proto.Webhook, 0, len(hooks))}
+	for _, w := range hooks {
+		resp.Webhooks = append(resp.Webhooks, webhookToProto(w))
+	}
+	return resp, nil
+}
+
+func (s *Server) DeleteWebhook(ctx context.Context, req *proto.DeleteWebhookRequest) (*proto.DeleteWebhookResponse, error) {
+	id, err := parseWebhookID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.repo.DeleteWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "webhook %d not found", id)
+	}
+	s.reloadWebhooks()
+	return &proto.DeleteWebhookResponse{}, nil
+}
+
+func (s *Server) EnableWebhook(ctx context.Context, req *proto.EnableWebhookRequest) (*proto.Webhook, error) {
+	id, err := parseWebhookID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.repo.EnableWebhook(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "webhook %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.reloadWebhooks()
+	return webhookToProto(w), nil
+}
+
+func (s *Server) ListWebhookDeliveries(ctx context.Context, req *proto.ListWebhookDeliveriesRequest) (*proto.ListWebhookDeliveriesResponse, error) {
+	id, err := parseWebhookID(req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+	state := db.DeliveryState(req.State)
+	switch state {
+	case "", db.DeliveryPending, db.DeliveryDelivered, db.DeliveryFailed:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown delivery state %q", req.State)
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if _, err := s.repo.GetWebhook(ctx, id); errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "webhook %d not found", id)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := checkOffset(req.Offset); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.repo.ListWebhookDeliveries(ctx, id, state, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListWebhookDeliveriesResponse{Deliveries: make([]*proto.WebhookDelivery, 0, len(deliveries))}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, deliveryToProto(d))
+	}
+	return resp, nil
+}
+
+// reloadWebhooks сообщает доставщику этой реплики, что подписки изменились. Остальные реплики
+// перечитают их сами.
+func (s *Server) reloadWebhooks() {
+	if s.webhooks != nil {
+		s.webhooks.Reload()
+	}
+}
@@ -0,0 +1,63 @@
+// Package simhash считает 64-битный SimHash текста по шинглам из слов.
+// У почти одинаковых текстов (перепечатки с другой подписью или рекламным блоком)
+// отпечатки отличаются в небольшом числе бит.
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// ShingleSize - число подряд идущих слов в одном шингле.
+const ShingleSize = 3
+
+// Bands - число 16-битных полос, на которые делится отпечаток для поиска кандидатов.
+// Если отпечатки отличаются не больше чем в Bands-1 битах, хотя бы одна полоса совпадает целиком.
+const Bands = 4
+
+// Compute возвращает SimHash текста. Для пустого текста - 0.
+func Compute(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+	size := ShingleSize
+	if len(words) < size {
+		size = len(words)
+	}
+	var v [64]int
+	h := fnv.New64a()
+	for i := 0; i+size <= len(words); i++ {
+		h.Reset()
+		h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		sum := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if sum&(1<<uint(b)) != 0 {
+				v[b]++
+			} else {
+				v[b]--
+			}
+		}
+	}
+	var res uint64
+	for b := 0; b < 64; b++ {
+		if v[b] > 0 {
+			res |= 1 << uint(b)
+		}
+	}
+	return res
+}
+
+// Distance - расстояние Хэмминга между двумя отпечатками.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Band возвращает i-ю 16-битную полосу отпечатка, считая с младших бит.
+func Band(h uint64, i int) int64 {
+	return int64((h >> (16 * uint(i))) & 0xffff)
+}
@@ -0,0 +1,93 @@
+package simhash
+
+import (
+	"strings"
+	"testing"
+)
+
+const article = `The city council approved the new budget on Tuesday after a long debate about public transport.
+The plan adds twelve new bus routes, extends the tram line to the northern districts and raises parking fees
+in the historic centre. Opponents argued that the fee increase would hurt small shops, while supporters said
+the money would pay for cleaner air and shorter commutes. The mayor promised to review the fees next year
+if the number of visitors to the centre falls. Construction of the tram extension is expected to start in spring
+and take about two years, with temporary bus lines replacing the closed sections during the works.`
+
+func TestNearIdenticalTextsAreClose(t *testing.T) {
+	h := Compute(article)
+	variants := map[string]string{
+		"case and punctuation": strings.ToUpper(strings.ReplaceAll(article, ",", ";")),
+		"byline added":         "By Staff Reporter. " + article,
+		"footer added":         article + " Subscribe to our newsletter.",
+	}
+	for name, text := range variants {
+		if d := Distance(h, Compute(text)); d >= Bands {
+			t.Errorf("%s: distance %d, want less than %d", name, d, Bands)
+		}
+	}
+}
+
+func TestDifferentTextsAreFar(t *testing.T) {
+	other := `Scientists have discovered a new species of frog in the rainforest. The tiny amphibian,
+barely a centimetre long, lives in leaf litter and calls at night with a high-pitched whistle. Researchers
+spent three seasons recording its song before confirming that it differs from every known relative.`
+	far := Distance(Compute(article), Compute(other))
+	if far < 10 {
+		t.Fatalf("distance between unrelated texts is %d", far)
+	}
+	// правка внутри короткого текста меняет несколько шинглов, но текст остается заметно ближе чужого
+	edited := Distance(Compute(article), Compute(strings.Replace(article, "twelve", "eleven", 1)))
+	if edited*2 > far {
+		t.Fatalf("edited text distance %d is not much less than unrelated %d", edited, far)
+	}
+}
+
+func TestComputeIsStable(t *testing.T) {
+	if Compute(article) != Compute(article) {
+		t.Fatal("hash of the same text differs between calls")
+	}
+	if Compute("") != 0 || Compute(" ,.! ") != 0 {
+		t.Fatal("hash of a text without words is not 0")
+	}
+	// короче шингла: хэшируется весь текст
+	if Compute("two words") == 0 {
+		t.Fatal("hash of a short text is 0")
+	}
+}
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xffff, 0, 16},
+		{^uint64(0), 0, 64},
+		{0xf0f0, 0x0ff0, 8},
+	}
+	for _, tc := range cases {
+		if got := Distance(tc.a, tc.b); got != tc.want {
+			t.Errorf("Distance(%#x, %#x) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestBandsCoverSmallDistances(t *testing.T) {
+	// при расстоянии меньше Bands хотя бы одна полоса совпадает целиком - на этом держится поиск по индексам
+	h := Compute(article)
+	for _, flips := range [][]uint{{0, 17, 40}, {15, 31, 47}, {3, 5, 63}} {
+		g := h
+		for _, b := range flips {
+			g ^= 1 << b
+		}
+		same := false
+		for i := 0; i < Bands; i++ {
+			if Band(h, i) == Band(g, i) {
+				same = true
+			}
+		}
+		if !same {
+			t.Errorf("flipping bits %v leaves no equal band", flips)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package sitemap разбирает XML-карты сайта (sitemaps.org): обычные <urlset>, индексы <sitemapindex>
+// и расширение Google News, в том числе сжатые gzip.
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// MaxSize - предел несжатого размера по протоколу sitemaps.org, дальше документ не читается.
+const MaxSize = 50 << 20
+
+// ErrNotSitemap - документ не <urlset> и не <sitemapindex>.
+var ErrNotSitemap = errors.New("not a sitemap")
+
+// URL - запись <url>. Published - <news:publication_date>, есть только в новостных картах.
+type URL struct {
+	Loc       string
+	LastMod   *time.Time
+	Published *time.Time
+}
+
+// Date - дата, по которой запись фильтруется: публикация, если известна, иначе lastmod.
+func (u URL) Date() *time.Time {
+	if u.Published != nil {
+		return u.Published
+	}
+	return u.LastMod
+}
+
+// Ref - ссылка индекса на дочернюю карту.
+type Ref struct {
+	Loc     string
+	LastMod *time.Time
+}
+
+// Document - разобранная карта: либо индекс (Sitemaps), либо список страниц (URLs).
+type Document struct {
+	Index    bool
+	Sitemaps []Ref
+	URLs     []URL
+}
+
+type xmlDoc struct {
+	XMLName  xml.Name
+	Sitemaps []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"sitemap"`
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+		News    struct {
+			PublicationDate string `xml:"publication_date"`
+		} `xml:"news"`
+	} `xml:"url"`
+}
+
+// Parse читает карту из r. Сжатие gzip определяется по сигнатуре, а не по имени файла:
+// сервера часто отдают sitemap.xml.gz без Content-Encoding.
+func Parse(r io.Reader) (*Document, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+	dec := xml.NewDecoder(io.LimitReader(br, MaxSize))
+	dec.CharsetReader = charset.NewReaderLabel
+	var doc xmlDoc
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotSitemap, err)
+	}
+	switch doc.XMLName.Local {
+	case "sitemapindex":
+		res := &Document{Index: true}
+		for _, s := range doc.Sitemaps {
+			if loc := strings.TrimSpace(s.Loc); loc != "" {
+				res.Sitemaps = append(res.Sitemaps, Ref{Loc: loc, LastMod: parseDate(s.LastMod)})
+			}
+		}
+		return res, nil
+	case "urlset":
+		res := &Document{}
+		for _, u := range doc.URLs {
+			if loc := strings.TrimSpace(u.Loc); loc != "" {
+				res.URLs = append(res.URLs, URL{Loc: loc, LastMod: parseDate(u.LastMod), Published: parseDate(u.News.PublicationDate)})
+			}
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("%w: root element <%s>", ErrNotSitemap, doc.XMLName.Local)
+}
+
+// форматы W3C Datetime, которые допускает протокол
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+func parseDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
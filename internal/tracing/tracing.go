@@ -0,0 +1,124 @@
+// Package tracing настраивает OpenTelemetry: глобальный TracerProvider, распространение
+// контекста в формате W3C traceparent и экспорт спанов в OTLP, stdout или файл.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+type Options struct {
+	ServiceName string
+	Exporter    string
+	Endpoint    string // host:port OTLP/gRPC коллектора
+	Insecure    bool
+	FilePath    string
+	SampleRatio float64
+}
+
+// Setup регистрирует глобальный TracerProvider. Возвращаемая функция дописывает
+// оставшиеся спаны и закрывает экспортер, ее нужно вызвать при остановке.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var exp sdktrace.SpanExporter
+	var closer io.Closer
+	var err error
+	switch opts.Exporter {
+	case ExporterOTLP, "":
+		o := []otlptracegrpc.Option{}
+		if opts.Endpoint != "" {
+			o = append(o, otlptracegrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			o = append(o, otlptracegrpc.WithInsecure())
+		}
+		exp, err = otlptracegrpc.New(ctx, o...)
+	case ExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterFile:
+		f, ferr := os.OpenFile(opts.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if ferr != nil {
+			return nil, ferr
+		}
+		closer = f
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name := opts.ServiceName
+	if name == "" {
+		name = "article-crawler"
+	}
+	ratio := opts.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", name))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closer != nil {
+			closer.Close()
+		}
+		return err
+	}, nil
+}
+
+// Inject возвращает traceparent текущего спана из ctx или "", если трассировка выключена.
+func Inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// Extract восстанавливает контекст спана из traceparent, сохраненного через Inject.
+func Extract(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
+
+// WithParent делает parent родителем спанов, начатых из ctx. Так контекст задачи переходит
+// между этапами пайплайна через каналы, не таща за собой отмену и значения исходного ctx.
+func WithParent(ctx context.Context, parent trace.SpanContext) context.Context {
+	if !parent.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, parent)
+}
+
+// RecordError помечает спан как завершившийся ошибкой.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,82 @@
+// Package urlfilter решает, можно ли обходить URL, по спискам доменов и регулярным выражениям.
+package urlfilter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Filter проверяет URL по порядку: deny-домены, deny-шаблоны, затем allow-домены.
+// Домен в списке действует и на все его поддомены. Пустой allow-список пропускает любой домен.
+// Нулевой *Filter пропускает все.
+type Filter struct {
+	allow    []string
+	deny     []string
+	patterns []*regexp.Regexp
+}
+
+// New компилирует шаблоны denyPatterns (синтаксис regexp, сопоставляются с полным URL)
+// и возвращает ошибки всех некорректных шаблонов разом.
+func New(allowDomains, denyDomains, denyPatterns []string) (*Filter, error) {
+	f := &Filter{allow: normalize(allowDomains), deny: normalize(denyDomains)}
+	var errs []error
+	for _, p := range denyPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pattern %q: %w", p, err))
+			continue
+		}
+		f.patterns = append(f.patterns, re)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func normalize(domains []string) []string {
+	out := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func matchDomain(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check возвращает причину, по которой URL отфильтрован, или nil.
+func (f *Filter) Check(raw string) error {
+	if f == nil {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	host := strings.ToLower(u.Hostname())
+	if matchDomain(host, f.deny) {
+		return fmt.Errorf("domain %s is denied by url filter", host)
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(raw) {
+			return fmt.Errorf("url matches denied pattern %q", re.String())
+		}
+	}
+	if len(f.allow) > 0 && !matchDomain(host, f.allow) {
+		return fmt.Errorf("domain %s is not in url filter allowlist", host)
+	}
+	return nil
+}
@@ -2,7 +2,7 @@
 // versions:
 // 	protoc-gen-go v1.36.9
 // 	protoc        v3.21.12
-// source: pkg/proto/crawler.proto
+// source: crawler.proto
 
 package proto
 
@@ -21,16 +21,98 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Параметры рекурсивного обхода: ссылки со страниц ставятся в очередь, пока они в области обхода
+// и не исчерпаны max_depth (переходов от затравки, 0 - по умолчанию 2) и max_pages (0 - 1000).
+// scope: "host" (по умолчанию) - тот же хост, "domain" - домен затравки и его поддомены,
+// "prefix" - URL начинается с каталога затравки. include/exclude - регулярные выражения по полному URL.
+type CrawlOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxDepth      int32                  `protobuf:"varint,1,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	MaxPages      int32                  `protobuf:"varint,2,opt,name=max_pages,json=maxPages,proto3" json:"max_pages,omitempty"`
+	Scope         string                 `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
+	Include       []string               `protobuf:"bytes,4,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude       []string               `protobuf:"bytes,5,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlOptions) Reset() {
+	*x = CrawlOptions{}
+	mi := &file_crawler_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlOptions) ProtoMessage() {}
+
+func (x *CrawlOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlOptions.ProtoReflect.Descriptor instead.
+func (*CrawlOptions) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CrawlOptions) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *CrawlOptions) GetMaxPages() int32 {
+	if x != nil {
+		return x.MaxPages
+	}
+	return 0
+}
+
+func (x *CrawlOptions) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *CrawlOptions) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *CrawlOptions) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+// crawl не задан - обрабатывается только сам url.
 type SubmitUrlRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Crawl         *CrawlOptions          `protobuf:"bytes,2,opt,name=crawl,proto3" json:"crawl,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubmitUrlRequest) Reset() {
 	*x = SubmitUrlRequest{}
-	mi := &file_pkg_proto_crawler_proto_msgTypes[0]
+	mi := &file_crawler_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -42,7 +124,7 @@ func (x *SubmitUrlRequest) String() string {
 func (*SubmitUrlRequest) ProtoMessage() {}
 
 func (x *SubmitUrlRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_crawler_proto_msgTypes[0]
+	mi := &file_crawler_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,7 +137,7 @@ func (x *SubmitUrlRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitUrlRequest.ProtoReflect.Descriptor instead.
 func (*SubmitUrlRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_crawler_proto_rawDescGZIP(), []int{0}
+	return file_crawler_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *SubmitUrlRequest) GetUrl() string {
@@ -65,17 +147,26 @@ func (x *SubmitUrlRequest) GetUrl() string {
 	return ""
 }
 
+func (x *SubmitUrlRequest) GetCrawl() *CrawlOptions {
+	if x != nil {
+		return x.Crawl
+	}
+	return nil
+}
+
+// id - задача для url, crawl_id - обход, если он был запрошен.
 type SubmitUrlResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	CrawlId       string                 `protobuf:"bytes,3,opt,name=crawl_id,json=crawlId,proto3" json:"crawl_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubmitUrlResponse) Reset() {
 	*x = SubmitUrlResponse{}
-	mi := &file_pkg_proto_crawler_proto_msgTypes[1]
+	mi := &file_crawler_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -87,7 +178,7 @@ func (x *SubmitUrlResponse) String() string {
 func (*SubmitUrlResponse) ProtoMessage() {}
 
 func (x *SubmitUrlResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_crawler_proto_msgTypes[1]
+	mi := &file_crawler_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,7 +191,7 @@ func (x *SubmitUrlResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitUrlResponse.ProtoReflect.Descriptor instead.
 func (*SubmitUrlResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_crawler_proto_rawDescGZIP(), []int{1}
+	return file_crawler_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *SubmitUrlResponse) GetId() string {
@@ -117,6 +208,13 @@ func (x *SubmitUrlResponse) GetMessage() string {
 	return ""
 }
 
+func (x *SubmitUrlResponse) GetCrawlId() string {
+	if x != nil {
+		return x.CrawlId
+	}
+	return ""
+}
+
 type GetArticleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -126,7 +224,7 @@ type GetArticleRequest struct {
 
 func (x *GetArticleRequest) Reset() {
 	*x = GetArticleRequest{}
-	mi := &file_pkg_proto_crawler_proto_msgTypes[2]
+	mi := &file_crawler_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -138,7 +236,7 @@ func (x *GetArticleRequest) String() string {
 func (*GetArticleRequest) ProtoMessage() {}
 
 func (x *GetArticleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_crawler_proto_msgTypes[2]
+	mi := &file_crawler_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -151,7 +249,7 @@ func (x *GetArticleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetArticleRequest.ProtoReflect.Descriptor instead.
 func (*GetArticleRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_crawler_proto_rawDescGZIP(), []int{2}
+	return file_crawler_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetArticleRequest) GetId() string {
@@ -171,7 +269,7 @@ type ListArticlesRequest struct {
 
 func (x *ListArticlesRequest) Reset() {
 	*x = ListArticlesRequest{}
-	mi := &file_pkg_proto_crawler_proto_msgTypes[3]
+	mi := &file_crawler_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -183,7 +281,7 @@ func (x *ListArticlesRequest) String() string {
 func (*ListArticlesRequest) ProtoMessage() {}
 
 func (x *ListArticlesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_crawler_proto_msgTypes[3]
+	mi := &file_crawler_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -196,7 +294,7 @@ func (x *ListArticlesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListArticlesRequest.ProtoReflect.Descriptor instead.
 func (*ListArticlesRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_crawler_proto_rawDescGZIP(), []int{3}
+	return file_crawler_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ListArticlesRequest) GetLimit() int32 {
@@ -224,13 +322,23 @@ type Article struct {
 	Language        string                 `protobuf:"bytes,7,opt,name=language,proto3" json:"language,omitempty"`
 	ReadTimeMinutes int32                  `protobuf:"varint,8,opt,name=read_time_minutes,json=readTimeMinutes,proto3" json:"read_time_minutes,omitempty"`
 	CreatedAt       string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Author          string                 `protobuf:"bytes,10,opt,name=author,proto3" json:"author,omitempty"`
+	PublishedAt     string                 `protobuf:"bytes,11,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	ModifiedAt      string                 `protobuf:"bytes,12,opt,name=modified_at,json=modifiedAt,proto3" json:"modified_at,omitempty"`
+	CanonicalUrl    string                 `protobuf:"bytes,13,opt,name=canonical_url,json=canonicalUrl,proto3" json:"canonical_url,omitempty"`
+	SiteName        string                 `protobuf:"bytes,14,opt,name=site_name,json=siteName,proto3" json:"site_name,omitempty"`
+	ImageUrl        string                 `protobuf:"bytes,15,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	Section         string                 `protobuf:"bytes,16,opt,name=section,proto3" json:"section,omitempty"`
+	Keywords        []string               `protobuf:"bytes,17,rep,name=keywords,proto3" json:"keywords,omitempty"`
+	ClusterId       string                 `protobuf:"bytes,18,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	SourceId        string                 `protobuf:"bytes,19,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Article) Reset() {
 	*x = Article{}
-	mi := &file_pkg_proto_crawler_proto_msgTypes[4]
+	mi := &file_crawler_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -242,7 +350,7 @@ func (x *Article) String() string {
 func (*Article) ProtoMessage() {}
 
 func (x *Article) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_crawler_proto_msgTypes[4]
+	mi := &file_crawler_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -255,7 +363,7 @@ func (x *Article) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Article.ProtoReflect.Descriptor instead.
 func (*Article) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_crawler_proto_rawDescGZIP(), []int{4}
+	return file_crawler_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Article) GetId() string {
@@ -321,6 +429,225 @@ func (x *Article) GetCreatedAt() string {
 	return ""
 }
 
+func (x *Article) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Article) GetPublishedAt() string {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return ""
+}
+
+func (x *Article) GetModifiedAt() string {
+	if x != nil {
+		return x.ModifiedAt
+	}
+	return ""
+}
+
+func (x *Article) GetCanonicalUrl() string {
+	if x != nil {
+		return x.CanonicalUrl
+	}
+	return ""
+}
+
+func (x *Article) GetSiteName() string {
+	if x != nil {
+		return x.SiteName
+	}
+	return ""
+}
+
+func (x *Article) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Article) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Article) GetKeywords() []string {
+	if x != nil {
+		return x.Keywords
+	}
+	return nil
+}
+
+func (x *Article) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *Article) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+// duplicates - остальные статьи кластера, distance - расстояние Хэмминга их SimHash до запрошенной.
+type GetDuplicatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ArticleId     string                 `protobuf:"bytes,1,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDuplicatesRequest) Reset() {
+	*x = GetDuplicatesRequest{}
+	mi := &file_crawler_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDuplicatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDuplicatesRequest) ProtoMessage() {}
+
+func (x *GetDuplicatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDuplicatesRequest.ProtoReflect.Descriptor instead.
+func (*GetDuplicatesRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetDuplicatesRequest) GetArticleId() string {
+	if x != nil {
+		return x.ArticleId
+	}
+	return ""
+}
+
+type DuplicateArticle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Article       *Article               `protobuf:"bytes,1,opt,name=article,proto3" json:"article,omitempty"`
+	Distance      int32                  `protobuf:"varint,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateArticle) Reset() {
+	*x = DuplicateArticle{}
+	mi := &file_crawler_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateArticle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateArticle) ProtoMessage() {}
+
+func (x *DuplicateArticle) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateArticle.ProtoReflect.Descriptor instead.
+func (*DuplicateArticle) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DuplicateArticle) GetArticle() *Article {
+	if x != nil {
+		return x.Article
+	}
+	return nil
+}
+
+func (x *DuplicateArticle) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type GetDuplicatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId     string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	Duplicates    []*DuplicateArticle    `protobuf:"bytes,2,rep,name=duplicates,proto3" json:"duplicates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDuplicatesResponse) Reset() {
+	*x = GetDuplicatesResponse{}
+	mi := &file_crawler_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDuplicatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDuplicatesResponse) ProtoMessage() {}
+
+func (x *GetDuplicatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDuplicatesResponse.ProtoReflect.Descriptor instead.
+func (*GetDuplicatesResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetDuplicatesResponse) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *GetDuplicatesResponse) GetDuplicates() []*DuplicateArticle {
+	if x != nil {
+		return x.Duplicates
+	}
+	return nil
+}
+
 type ListArticlesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Articles      []*Article             `protobuf:"bytes,1,rep,name=articles,proto3" json:"articles,omitempty"`
@@ -330,7 +657,7 @@ type ListArticlesResponse struct {
 
 func (x *ListArticlesResponse) Reset() {
 	*x = ListArticlesResponse{}
-	mi := &file_pkg_proto_crawler_proto_msgTypes[5]
+	mi := &file_crawler_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -342,7 +669,7 @@ func (x *ListArticlesResponse) String() string {
 func (*ListArticlesResponse) ProtoMessage() {}
 
 func (x *ListArticlesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_crawler_proto_msgTypes[5]
+	mi := &file_crawler_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -355,7 +682,7 @@ func (x *ListArticlesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListArticlesResponse.ProtoReflect.Descriptor instead.
 func (*ListArticlesResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_crawler_proto_rawDescGZIP(), []int{5}
+	return file_crawler_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ListArticlesResponse) GetArticles() []*Article {
@@ -365,27 +692,37 @@ func (x *ListArticlesResponse) GetArticles() []*Article {
 	return nil
 }
 
-type StreamNewArticlesRequest struct {
+// query - синтаксис websearch_to_tsquery: "точная фраза", OR, -исключение.
+// language - код whatlanggo (eng, rus, ...), domains - хост или родительский домен,
+// from/to - RFC3339 или YYYY-MM-DD по дате публикации (или дате сохранения, если ее нет).
+type SearchArticlesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Domains       []string               `protobuf:"bytes,3,rep,name=domains,proto3" json:"domains,omitempty"`
+	From          string                 `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StreamNewArticlesRequest) Reset() {
-	*x = StreamNewArticlesRequest{}
-	mi := &file_pkg_proto_crawler_proto_msgTypes[6]
+func (x *SearchArticlesRequest) Reset() {
+	*x = SearchArticlesRequest{}
+	mi := &file_crawler_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamNewArticlesRequest) String() string {
+func (x *SearchArticlesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamNewArticlesRequest) ProtoMessage() {}
+func (*SearchArticlesRequest) ProtoMessage() {}
 
-func (x *StreamNewArticlesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_crawler_proto_msgTypes[6]
+func (x *SearchArticlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -396,106 +733,3525 @@ func (x *StreamNewArticlesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamNewArticlesRequest.ProtoReflect.Descriptor instead.
-func (*StreamNewArticlesRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_crawler_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use SearchArticlesRequest.ProtoReflect.Descriptor instead.
+func (*SearchArticlesRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{10}
 }
 
-var File_pkg_proto_crawler_proto protoreflect.FileDescriptor
+func (x *SearchArticlesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
 
-const file_pkg_proto_crawler_proto_rawDesc = "" +
-	"\n" +
-	"\x17pkg/proto/crawler.proto\x12\x05proto\"$\n" +
-	"\x10SubmitUrlRequest\x12\x10\n" +
-	"\x03url\x18\x01 \x01(\tR\x03url\"=\n" +
-	"\x11SubmitUrlResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"#\n" +
-	"\x11GetArticleRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
-	"\x13ListArticlesRequest\x12\x14\n" +
-	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"\xf9\x01\n" +
-	"\aArticle\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
-	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
-	"\x05title\x18\x03 \x01(\tR\x05title\x12\x12\n" +
-	"\x04body\x18\x04 \x01(\tR\x04body\x12\x18\n" +
-	"\asummary\x18\x05 \x01(\tR\asummary\x12!\n" +
-	"\fcontent_hash\x18\x06 \x01(\tR\vcontentHash\x12\x1a\n" +
-	"\blanguage\x18\a \x01(\tR\blanguage\x12*\n" +
-	"\x11read_time_minutes\x18\b \x01(\x05R\x0freadTimeMinutes\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\t \x01(\tR\tcreatedAt\"B\n" +
-	"\x14ListArticlesResponse\x12*\n" +
-	"\barticles\x18\x01 \x03(\v2\x0e.proto.ArticleR\barticles\"\x1a\n" +
-	"\x18StreamNewArticlesRequest2\x92\x02\n" +
-	"\aCrawler\x12>\n" +
-	"\tSubmitUrl\x12\x17.proto.SubmitUrlRequest\x1a\x18.proto.SubmitUrlResponse\x126\n" +
-	"\n" +
-	"GetArticle\x12\x18.proto.GetArticleRequest\x1a\x0e.proto.Article\x12G\n" +
-	"\fListArticles\x12\x1a.proto.ListArticlesRequest\x1a\x1b.proto.ListArticlesResponse\x12F\n" +
-	"\x11StreamNewArticles\x12\x1f.proto.StreamNewArticlesRequest\x1a\x0e.proto.Article0\x01B7Z5github.com/kiyotaka137/articlecrawler/pkg/proto;protob\x06proto3"
+func (x *SearchArticlesRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
 
-var (
-	file_pkg_proto_crawler_proto_rawDescOnce sync.Once
-	file_pkg_proto_crawler_proto_rawDescData []byte
-)
+func (x *SearchArticlesRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
 
-func file_pkg_proto_crawler_proto_rawDescGZIP() []byte {
-	file_pkg_proto_crawler_proto_rawDescOnce.Do(func() {
-		file_pkg_proto_crawler_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_proto_crawler_proto_rawDesc), len(file_pkg_proto_crawler_proto_rawDesc)))
+func (x *SearchArticlesRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *SearchArticlesRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *SearchArticlesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchArticlesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type SearchHit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Article       *Article               `protobuf:"bytes,1,opt,name=article,proto3" json:"article,omitempty"`
+	Rank          float64                `protobuf:"fixed64,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	Snippet       string                 `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_crawler_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchHit) GetArticle() *Article {
+	if x != nil {
+		return x.Article
+	}
+	return nil
+}
+
+func (x *SearchHit) GetRank() float64 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *SearchHit) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type SearchArticlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          []*SearchHit           `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchArticlesResponse) Reset() {
+	*x = SearchArticlesResponse{}
+	mi := &file_crawler_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchArticlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchArticlesResponse) ProtoMessage() {}
+
+func (x *SearchArticlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchArticlesResponse.ProtoReflect.Descriptor instead.
+func (*SearchArticlesResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchArticlesResponse) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+// Фильтры подписки, пустые поля не фильтруют. domains - хосты вместе с поддоменами,
+// title_query - слова, которые все должны быть в заголовке (без учета регистра), title_regex -
+// регулярное выражение по заголовку, min_read_time - минимальное время чтения в минутах.
+// fields - поля Article в событиях (например "url", "title"), пусто - все; id передается всегда.
+// since_sequence > 0 - сначала события журнала с номером больше since_sequence (то, что клиент
+// пропустил, пока был отключен), затем живые. Доставка "хотя бы один раз", повторы отбрасываются по sequence.
+type StreamNewArticlesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []string               `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	TitleQuery    string                 `protobuf:"bytes,3,opt,name=title_query,json=titleQuery,proto3" json:"title_query,omitempty"`
+	TitleRegex    string                 `protobuf:"bytes,4,opt,name=title_regex,json=titleRegex,proto3" json:"title_regex,omitempty"`
+	MinReadTime   int32                  `protobuf:"varint,5,opt,name=min_read_time,json=minReadTime,proto3" json:"min_read_time,omitempty"`
+	SourceId      string                 `protobuf:"bytes,6,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	Fields        []string               `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty"`
+	SinceSequence int64                  `protobuf:"varint,8,opt,name=since_sequence,json=sinceSequence,proto3" json:"since_sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamNewArticlesRequest) Reset() {
+	*x = StreamNewArticlesRequest{}
+	mi := &file_crawler_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamNewArticlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamNewArticlesRequest) ProtoMessage() {}
+
+func (x *StreamNewArticlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamNewArticlesRequest.ProtoReflect.Descriptor instead.
+func (*StreamNewArticlesRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamNewArticlesRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *StreamNewArticlesRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *StreamNewArticlesRequest) GetTitleQuery() string {
+	if x != nil {
+		return x.TitleQuery
+	}
+	return ""
+}
+
+func (x *StreamNewArticlesRequest) GetTitleRegex() string {
+	if x != nil {
+		return x.TitleRegex
+	}
+	return ""
+}
+
+func (x *StreamNewArticlesRequest) GetMinReadTime() int32 {
+	if x != nil {
+		return x.MinReadTime
+	}
+	return 0
+}
+
+func (x *StreamNewArticlesRequest) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+func (x *StreamNewArticlesRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *StreamNewArticlesRequest) GetSinceSequence() int64 {
+	if x != nil {
+		return x.SinceSequence
+	}
+	return 0
+}
+
+// Событие StreamArticleEvents. StreamNewArticles с теми же фильтрами отдает только статьи событий "created".
+// type: "created" для новой статьи, "updated" для новой ревизии существующей.
+// sequence - номер события, растет монотонно; 0 - событие не попало в журнал и не будет повторено.
+type ArticleEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Article       *Article               `protobuf:"bytes,2,opt,name=article,proto3" json:"article,omitempty"`
+	Sequence      int64                  `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArticleEvent) Reset() {
+	*x = ArticleEvent{}
+	mi := &file_crawler_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArticleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArticleEvent) ProtoMessage() {}
+
+func (x *ArticleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArticleEvent.ProtoReflect.Descriptor instead.
+func (*ArticleEvent) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ArticleEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ArticleEvent) GetArticle() *Article {
+	if x != nil {
+		return x.Article
+	}
+	return nil
+}
+
+func (x *ArticleEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type ListArticleRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ArticleId     string                 `protobuf:"bytes,1,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListArticleRevisionsRequest) Reset() {
+	*x = ListArticleRevisionsRequest{}
+	mi := &file_crawler_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListArticleRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArticleRevisionsRequest) ProtoMessage() {}
+
+func (x *ListArticleRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArticleRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListArticleRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListArticleRevisionsRequest) GetArticleId() string {
+	if x != nil {
+		return x.ArticleId
+	}
+	return ""
+}
+
+type ArticleRevision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ArticleId     string                 `protobuf:"bytes,2,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	ContentHash   string                 `protobuf:"bytes,3,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Summary       string                 `protobuf:"bytes,5,opt,name=summary,proto3" json:"summary,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastSeenAt    string                 `protobuf:"bytes,7,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArticleRevision) Reset() {
+	*x = ArticleRevision{}
+	mi := &file_crawler_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArticleRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArticleRevision) ProtoMessage() {}
+
+func (x *ArticleRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArticleRevision.ProtoReflect.Descriptor instead.
+func (*ArticleRevision) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ArticleRevision) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ArticleRevision) GetArticleId() string {
+	if x != nil {
+		return x.ArticleId
+	}
+	return ""
+}
+
+func (x *ArticleRevision) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *ArticleRevision) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ArticleRevision) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *ArticleRevision) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ArticleRevision) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+type ListArticleRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*ArticleRevision     `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListArticleRevisionsResponse) Reset() {
+	*x = ListArticleRevisionsResponse{}
+	mi := &file_crawler_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListArticleRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArticleRevisionsResponse) ProtoMessage() {}
+
+func (x *ListArticleRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArticleRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListArticleRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListArticleRevisionsResponse) GetRevisions() []*ArticleRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+// granularity: "line" (по умолчанию) или "word".
+type DiffArticleRevisionsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FromRevisionId string                 `protobuf:"bytes,1,opt,name=from_revision_id,json=fromRevisionId,proto3" json:"from_revision_id,omitempty"`
+	ToRevisionId   string                 `protobuf:"bytes,2,opt,name=to_revision_id,json=toRevisionId,proto3" json:"to_revision_id,omitempty"`
+	Granularity    string                 `protobuf:"bytes,3,opt,name=granularity,proto3" json:"granularity,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DiffArticleRevisionsRequest) Reset() {
+	*x = DiffArticleRevisionsRequest{}
+	mi := &file_crawler_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffArticleRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffArticleRevisionsRequest) ProtoMessage() {}
+
+func (x *DiffArticleRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffArticleRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*DiffArticleRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DiffArticleRevisionsRequest) GetFromRevisionId() string {
+	if x != nil {
+		return x.FromRevisionId
+	}
+	return ""
+}
+
+func (x *DiffArticleRevisionsRequest) GetToRevisionId() string {
+	if x != nil {
+		return x.ToRevisionId
+	}
+	return ""
+}
+
+func (x *DiffArticleRevisionsRequest) GetGranularity() string {
+	if x != nil {
+		return x.Granularity
+	}
+	return ""
+}
+
+// type: "equal", "insert" или "delete".
+type DiffOp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffOp) Reset() {
+	*x = DiffOp{}
+	mi := &file_crawler_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffOp) ProtoMessage() {}
+
+func (x *DiffOp) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffOp.ProtoReflect.Descriptor instead.
+func (*DiffOp) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DiffOp) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DiffOp) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// inserted/deleted - число добавленных и удаленных слов в body.
+type DiffArticleRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *ArticleRevision       `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *ArticleRevision       `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	TitleOps      []*DiffOp              `protobuf:"bytes,3,rep,name=title_ops,json=titleOps,proto3" json:"title_ops,omitempty"`
+	BodyOps       []*DiffOp              `protobuf:"bytes,4,rep,name=body_ops,json=bodyOps,proto3" json:"body_ops,omitempty"`
+	Inserted      int32                  `protobuf:"varint,5,opt,name=inserted,proto3" json:"inserted,omitempty"`
+	Deleted       int32                  `protobuf:"varint,6,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffArticleRevisionsResponse) Reset() {
+	*x = DiffArticleRevisionsResponse{}
+	mi := &file_crawler_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffArticleRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffArticleRevisionsResponse) ProtoMessage() {}
+
+func (x *DiffArticleRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffArticleRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*DiffArticleRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DiffArticleRevisionsResponse) GetFrom() *ArticleRevision {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *DiffArticleRevisionsResponse) GetTo() *ArticleRevision {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *DiffArticleRevisionsResponse) GetTitleOps() []*DiffOp {
+	if x != nil {
+		return x.TitleOps
+	}
+	return nil
+}
+
+func (x *DiffArticleRevisionsResponse) GetBodyOps() []*DiffOp {
+	if x != nil {
+		return x.BodyOps
+	}
+	return nil
+}
+
+func (x *DiffArticleRevisionsResponse) GetInserted() int32 {
+	if x != nil {
+		return x.Inserted
+	}
+	return 0
+}
+
+func (x *DiffArticleRevisionsResponse) GetDeleted() int32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+// domain пустой - все домены, к которым уже были запросы.
+type ListDomainLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDomainLimitsRequest) Reset() {
+	*x = ListDomainLimitsRequest{}
+	mi := &file_crawler_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDomainLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDomainLimitsRequest) ProtoMessage() {}
+
+func (x *ListDomainLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDomainLimitsRequest.ProtoReflect.Descriptor instead.
+func (*ListDomainLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListDomainLimitsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+// pattern - ключ rate_limit.domains, под который попал домен ("" - лимит по умолчанию).
+// effective_rps учитывает адаптивное замедление (factor) и Crawl-delay,
+// blocked_until - до какого момента домен закрыт по Retry-After.
+type DomainLimit struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Domain            string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Pattern           string                 `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	ConfiguredRps     float64                `protobuf:"fixed64,3,opt,name=configured_rps,json=configuredRps,proto3" json:"configured_rps,omitempty"`
+	EffectiveRps      float64                `protobuf:"fixed64,4,opt,name=effective_rps,json=effectiveRps,proto3" json:"effective_rps,omitempty"`
+	Burst             int32                  `protobuf:"varint,5,opt,name=burst,proto3" json:"burst,omitempty"`
+	Factor            float64                `protobuf:"fixed64,6,opt,name=factor,proto3" json:"factor,omitempty"`
+	CrawlDelaySeconds float64                `protobuf:"fixed64,7,opt,name=crawl_delay_seconds,json=crawlDelaySeconds,proto3" json:"crawl_delay_seconds,omitempty"`
+	BlockedUntil      string                 `protobuf:"bytes,8,opt,name=blocked_until,json=blockedUntil,proto3" json:"blocked_until,omitempty"`
+	LatencyMs         int64                  `protobuf:"varint,9,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DomainLimit) Reset() {
+	*x = DomainLimit{}
+	mi := &file_crawler_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainLimit) ProtoMessage() {}
+
+func (x *DomainLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainLimit.ProtoReflect.Descriptor instead.
+func (*DomainLimit) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DomainLimit) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DomainLimit) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *DomainLimit) GetConfiguredRps() float64 {
+	if x != nil {
+		return x.ConfiguredRps
+	}
+	return 0
+}
+
+func (x *DomainLimit) GetEffectiveRps() float64 {
+	if x != nil {
+		return x.EffectiveRps
+	}
+	return 0
+}
+
+func (x *DomainLimit) GetBurst() int32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *DomainLimit) GetFactor() float64 {
+	if x != nil {
+		return x.Factor
+	}
+	return 0
+}
+
+func (x *DomainLimit) GetCrawlDelaySeconds() float64 {
+	if x != nil {
+		return x.CrawlDelaySeconds
+	}
+	return 0
+}
+
+func (x *DomainLimit) GetBlockedUntil() string {
+	if x != nil {
+		return x.BlockedUntil
+	}
+	return ""
+}
+
+func (x *DomainLimit) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+// tracked_domains - сколько доменов лимитер помнит сейчас (max_domains 0 - без ограничения),
+// *_evictions - сколько забыто по простою и по переполнению с момента запуска.
+// backend - где хранятся ведра токенов (memory или postgres), backend_errors - сколько раз оно не ответило.
+type ListDomainLimitsResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Limits            []*DomainLimit         `protobuf:"bytes,1,rep,name=limits,proto3" json:"limits,omitempty"`
+	TrackedDomains    int64                  `protobuf:"varint,2,opt,name=tracked_domains,json=trackedDomains,proto3" json:"tracked_domains,omitempty"`
+	MaxDomains        int64                  `protobuf:"varint,3,opt,name=max_domains,json=maxDomains,proto3" json:"max_domains,omitempty"`
+	IdleEvictions     int64                  `protobuf:"varint,4,opt,name=idle_evictions,json=idleEvictions,proto3" json:"idle_evictions,omitempty"`
+	CapacityEvictions int64                  `protobuf:"varint,5,opt,name=capacity_evictions,json=capacityEvictions,proto3" json:"capacity_evictions,omitempty"`
+	Backend           string                 `protobuf:"bytes,6,opt,name=backend,proto3" json:"backend,omitempty"`
+	BackendErrors     int64                  `protobuf:"varint,7,opt,name=backend_errors,json=backendErrors,proto3" json:"backend_errors,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListDomainLimitsResponse) Reset() {
+	*x = ListDomainLimitsResponse{}
+	mi := &file_crawler_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDomainLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDomainLimitsResponse) ProtoMessage() {}
+
+func (x *ListDomainLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDomainLimitsResponse.ProtoReflect.Descriptor instead.
+func (*ListDomainLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListDomainLimitsResponse) GetLimits() []*DomainLimit {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *ListDomainLimitsResponse) GetTrackedDomains() int64 {
+	if x != nil {
+		return x.TrackedDomains
+	}
+	return 0
+}
+
+func (x *ListDomainLimitsResponse) GetMaxDomains() int64 {
+	if x != nil {
+		return x.MaxDomains
+	}
+	return 0
+}
+
+func (x *ListDomainLimitsResponse) GetIdleEvictions() int64 {
+	if x != nil {
+		return x.IdleEvictions
+	}
+	return 0
+}
+
+func (x *ListDomainLimitsResponse) GetCapacityEvictions() int64 {
+	if x != nil {
+		return x.CapacityEvictions
+	}
+	return 0
+}
+
+func (x *ListDomainLimitsResponse) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *ListDomainLimitsResponse) GetBackendErrors() int64 {
+	if x != nil {
+		return x.BackendErrors
+	}
+	return 0
+}
+
+type GetJobStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobStatusRequest) Reset() {
+	*x = GetJobStatusRequest{}
+	mi := &file_crawler_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobStatusRequest) ProtoMessage() {}
+
+func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetJobStatusRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetJobStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type JobStage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStage) Reset() {
+	*x = JobStage{}
+	mi := &file_crawler_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStage) ProtoMessage() {}
+
+func (x *JobStage) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStage.ProtoReflect.Descriptor instead.
+func (*JobStage) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *JobStage) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *JobStage) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobStage) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobStage) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type JobStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Attempts      int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	ArticleId     string                 `protobuf:"bytes,5,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Stages        []*JobStage            `protobuf:"bytes,9,rep,name=stages,proto3" json:"stages,omitempty"`
+	CrawlId       string                 `protobuf:"bytes,10,opt,name=crawl_id,json=crawlId,proto3" json:"crawl_id,omitempty"`
+	Depth         int32                  `protobuf:"varint,11,opt,name=depth,proto3" json:"depth,omitempty"`
+	SourceId      string                 `protobuf:"bytes,12,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatus) Reset() {
+	*x = JobStatus{}
+	mi := &file_crawler_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatus) ProtoMessage() {}
+
+func (x *JobStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
+func (*JobStatus) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *JobStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *JobStatus) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *JobStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *JobStatus) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *JobStatus) GetArticleId() string {
+	if x != nil {
+		return x.ArticleId
+	}
+	return ""
+}
+
+func (x *JobStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobStatus) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *JobStatus) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *JobStatus) GetStages() []*JobStage {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *JobStatus) GetCrawlId() string {
+	if x != nil {
+		return x.CrawlId
+	}
+	return ""
+}
+
+func (x *JobStatus) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *JobStatus) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+type ListJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_crawler_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListJobsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type ListJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*JobStatus           `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_crawler_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListJobsResponse) GetJobs() []*JobStatus {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type GetCrawlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCrawlRequest) Reset() {
+	*x = GetCrawlRequest{}
+	mi := &file_crawler_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCrawlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCrawlRequest) ProtoMessage() {}
+
+func (x *GetCrawlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCrawlRequest.ProtoReflect.Descriptor instead.
+func (*GetCrawlRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetCrawlRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelCrawlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelCrawlRequest) Reset() {
+	*x = CancelCrawlRequest{}
+	mi := &file_crawler_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelCrawlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCrawlRequest) ProtoMessage() {}
+
+func (x *CancelCrawlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCrawlRequest.ProtoReflect.Descriptor instead.
+func (*CancelCrawlRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CancelCrawlRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListCrawlsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCrawlsRequest) Reset() {
+	*x = ListCrawlsRequest{}
+	mi := &file_crawler_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCrawlsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCrawlsRequest) ProtoMessage() {}
+
+func (x *ListCrawlsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCrawlsRequest.ProtoReflect.Descriptor instead.
+func (*ListCrawlsRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListCrawlsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCrawlsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListCrawlsRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+// state: "running", "finished" или "canceled".
+// pages_queued - поставлено в очередь вместе с затравкой, pages_done - обработано
+// (сохранено, дубликат или без изменений), pages_failed - с ошибкой или снято при отмене.
+type Crawl struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SeedUrl       string                 `protobuf:"bytes,2,opt,name=seed_url,json=seedUrl,proto3" json:"seed_url,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	MaxDepth      int32                  `protobuf:"varint,4,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	MaxPages      int32                  `protobuf:"varint,5,opt,name=max_pages,json=maxPages,proto3" json:"max_pages,omitempty"`
+	Scope         string                 `protobuf:"bytes,6,opt,name=scope,proto3" json:"scope,omitempty"`
+	Include       []string               `protobuf:"bytes,7,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude       []string               `protobuf:"bytes,8,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	PagesQueued   int32                  `protobuf:"varint,9,opt,name=pages_queued,json=pagesQueued,proto3" json:"pages_queued,omitempty"`
+	PagesDone     int32                  `protobuf:"varint,10,opt,name=pages_done,json=pagesDone,proto3" json:"pages_done,omitempty"`
+	PagesFailed   int32                  `protobuf:"varint,11,opt,name=pages_failed,json=pagesFailed,proto3" json:"pages_failed,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	FinishedAt    string                 `protobuf:"bytes,14,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Crawl) Reset() {
+	*x = Crawl{}
+	mi := &file_crawler_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Crawl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Crawl) ProtoMessage() {}
+
+func (x *Crawl) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Crawl.ProtoReflect.Descriptor instead.
+func (*Crawl) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Crawl) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Crawl) GetSeedUrl() string {
+	if x != nil {
+		return x.SeedUrl
+	}
+	return ""
+}
+
+func (x *Crawl) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Crawl) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *Crawl) GetMaxPages() int32 {
+	if x != nil {
+		return x.MaxPages
+	}
+	return 0
+}
+
+func (x *Crawl) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *Crawl) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *Crawl) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *Crawl) GetPagesQueued() int32 {
+	if x != nil {
+		return x.PagesQueued
+	}
+	return 0
+}
+
+func (x *Crawl) GetPagesDone() int32 {
+	if x != nil {
+		return x.PagesDone
+	}
+	return 0
+}
+
+func (x *Crawl) GetPagesFailed() int32 {
+	if x != nil {
+		return x.PagesFailed
+	}
+	return 0
+}
+
+func (x *Crawl) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Crawl) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Crawl) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+type ListCrawlsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Crawls        []*Crawl               `protobuf:"bytes,1,rep,name=crawls,proto3" json:"crawls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCrawlsResponse) Reset() {
+	*x = ListCrawlsResponse{}
+	mi := &file_crawler_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCrawlsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCrawlsResponse) ProtoMessage() {}
+
+func (x *ListCrawlsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCrawlsResponse.ProtoReflect.Descriptor instead.
+func (*ListCrawlsResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListCrawlsResponse) GetCrawls() []*Crawl {
+	if x != nil {
+		return x.Crawls
+	}
+	return nil
+}
+
+// url - адрес ленты RSS, Atom или JSON Feed, interval_seconds - как часто ее опрашивать
+// (0 - sources.default_interval_seconds, не меньше 60).
+type AddSourceRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Url             string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	IntervalSeconds int32                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddSourceRequest) Reset() {
+	*x = AddSourceRequest{}
+	mi := &file_crawler_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSourceRequest) ProtoMessage() {}
+
+func (x *AddSourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSourceRequest.ProtoReflect.Descriptor instead.
+func (*AddSourceRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AddSourceRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AddSourceRequest) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+// format ("rss", "atom", "json") и title известны после первого успешного опроса.
+// entries - сколько записей ленты уже поставлено в очередь, last_error - ошибка последнего опроса.
+type Source struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url             string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Format          string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	Title           string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	IntervalSeconds int32                  `protobuf:"varint,5,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	LastPolledAt    string                 `protobuf:"bytes,6,opt,name=last_polled_at,json=lastPolledAt,proto3" json:"last_polled_at,omitempty"`
+	NextPollAt      string                 `protobuf:"bytes,7,opt,name=next_poll_at,json=nextPollAt,proto3" json:"next_poll_at,omitempty"`
+	LastError       string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	Entries         int32                  `protobuf:"varint,9,opt,name=entries,proto3" json:"entries,omitempty"`
+	CreatedAt       string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Source) Reset() {
+	*x = Source{}
+	mi := &file_crawler_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Source) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Source) ProtoMessage() {}
+
+func (x *Source) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Source.ProtoReflect.Descriptor instead.
+func (*Source) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Source) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Source) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Source) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *Source) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Source) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *Source) GetLastPolledAt() string {
+	if x != nil {
+		return x.LastPolledAt
+	}
+	return ""
+}
+
+func (x *Source) GetNextPollAt() string {
+	if x != nil {
+		return x.NextPollAt
+	}
+	return ""
+}
+
+func (x *Source) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Source) GetEntries() int32 {
+	if x != nil {
+		return x.Entries
+	}
+	return 0
+}
+
+func (x *Source) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListSourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSourcesRequest) Reset() {
+	*x = ListSourcesRequest{}
+	mi := &file_crawler_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSourcesRequest) ProtoMessage() {}
+
+func (x *ListSourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSourcesRequest.ProtoReflect.Descriptor instead.
+func (*ListSourcesRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListSourcesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListSourcesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListSourcesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sources       []*Source              `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSourcesResponse) Reset() {
+	*x = ListSourcesResponse{}
+	mi := &file_crawler_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSourcesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSourcesResponse) ProtoMessage() {}
+
+func (x *ListSourcesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSourcesResponse.ProtoReflect.Descriptor instead.
+func (*ListSourcesResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListSourcesResponse) GetSources() []*Source {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type RemoveSourceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveSourceRequest) Reset() {
+	*x = RemoveSourceRequest{}
+	mi := &file_crawler_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveSourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveSourceRequest) ProtoMessage() {}
+
+func (x *RemoveSourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveSourceRequest.ProtoReflect.Descriptor instead.
+func (*RemoveSourceRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RemoveSourceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RemoveSourceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveSourceResponse) Reset() {
+	*x = RemoveSourceResponse{}
+	mi := &file_crawler_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveSourceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveSourceResponse) ProtoMessage() {}
+
+func (x *RemoveSourceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveSourceResponse.ProtoReflect.Descriptor instead.
+func (*RemoveSourceResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{39}
+}
+
+// url - карта или индекс карт (можно .xml.gz). Если url пуст, карты берутся из robots.txt сайта site,
+// а без них - site/sitemap.xml. from/to (RFC3339 или YYYY-MM-DD) ограничивают дату публикации
+// (news:publication_date, иначе lastmod); include/exclude - регулярные выражения по URL.
+type SubmitSitemapRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Site          string                 `protobuf:"bytes,2,opt,name=site,proto3" json:"site,omitempty"`
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Include       []string               `protobuf:"bytes,5,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude       []string               `protobuf:"bytes,6,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	MaxUrls       int32                  `protobuf:"varint,7,opt,name=max_urls,json=maxUrls,proto3" json:"max_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitSitemapRequest) Reset() {
+	*x = SubmitSitemapRequest{}
+	mi := &file_crawler_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitSitemapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitSitemapRequest) ProtoMessage() {}
+
+func (x *SubmitSitemapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitSitemapRequest.ProtoReflect.Descriptor instead.
+func (*SubmitSitemapRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SubmitSitemapRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SubmitSitemapRequest) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *SubmitSitemapRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *SubmitSitemapRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *SubmitSitemapRequest) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *SubmitSitemapRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *SubmitSitemapRequest) GetMaxUrls() int32 {
+	if x != nil {
+		return x.MaxUrls
+	}
+	return 0
+}
+
+type GetSitemapImportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSitemapImportRequest) Reset() {
+	*x = GetSitemapImportRequest{}
+	mi := &file_crawler_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSitemapImportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSitemapImportRequest) ProtoMessage() {}
+
+func (x *GetSitemapImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSitemapImportRequest.ProtoReflect.Descriptor instead.
+func (*GetSitemapImportRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetSitemapImportRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// urls_matched - сколько URL прошло фильтры, urls_enqueued - сколько из них стало новыми задачами.
+type SitemapImport struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State           string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Sitemaps        []string               `protobuf:"bytes,3,rep,name=sitemaps,proto3" json:"sitemaps,omitempty"`
+	SitemapsFetched int32                  `protobuf:"varint,4,opt,name=sitemaps_fetched,json=sitemapsFetched,proto3" json:"sitemaps_fetched,omitempty"`
+	SitemapsFailed  int32                  `protobuf:"varint,5,opt,name=sitemaps_failed,json=sitemapsFailed,proto3" json:"sitemaps_failed,omitempty"`
+	UrlsFound       int32                  `protobuf:"varint,6,opt,name=urls_found,json=urlsFound,proto3" json:"urls_found,omitempty"`
+	UrlsMatched     int32                  `protobuf:"varint,7,opt,name=urls_matched,json=urlsMatched,proto3" json:"urls_matched,omitempty"`
+	UrlsEnqueued    int32                  `protobuf:"varint,8,opt,name=urls_enqueued,json=urlsEnqueued,proto3" json:"urls_enqueued,omitempty"`
+	LastError       string                 `protobuf:"bytes,9,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt       string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       string                 `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	FinishedAt      string                 `protobuf:"bytes,12,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SitemapImport) Reset() {
+	*x = SitemapImport{}
+	mi := &file_crawler_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SitemapImport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SitemapImport) ProtoMessage() {}
+
+func (x *SitemapImport) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SitemapImport.ProtoReflect.Descriptor instead.
+func (*SitemapImport) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *SitemapImport) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SitemapImport) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *SitemapImport) GetSitemaps() []string {
+	if x != nil {
+		return x.Sitemaps
+	}
+	return nil
+}
+
+func (x *SitemapImport) GetSitemapsFetched() int32 {
+	if x != nil {
+		return x.SitemapsFetched
+	}
+	return 0
+}
+
+func (x *SitemapImport) GetSitemapsFailed() int32 {
+	if x != nil {
+		return x.SitemapsFailed
+	}
+	return 0
+}
+
+func (x *SitemapImport) GetUrlsFound() int32 {
+	if x != nil {
+		return x.UrlsFound
+	}
+	return 0
+}
+
+func (x *SitemapImport) GetUrlsMatched() int32 {
+	if x != nil {
+		return x.UrlsMatched
+	}
+	return 0
+}
+
+func (x *SitemapImport) GetUrlsEnqueued() int32 {
+	if x != nil {
+		return x.UrlsEnqueued
+	}
+	return 0
+}
+
+func (x *SitemapImport) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *SitemapImport) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *SitemapImport) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *SitemapImport) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+// Подписка на события статей по HTTP. Фильтры - как в StreamNewArticlesRequest, event_types - "created",
+// "updated", пусто - все. Пустой secret - сервер создаст случайный; секрет возвращается только в CreateWebhook.
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	Domains       []string               `protobuf:"bytes,3,rep,name=domains,proto3" json:"domains,omitempty"`
+	Language      string                 `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	TitleQuery    string                 `protobuf:"bytes,5,opt,name=title_query,json=titleQuery,proto3" json:"title_query,omitempty"`
+	TitleRegex    string                 `protobuf:"bytes,6,opt,name=title_regex,json=titleRegex,proto3" json:"title_regex,omitempty"`
+	MinReadTime   int32                  `protobuf:"varint,7,opt,name=min_read_time,json=minReadTime,proto3" json:"min_read_time,omitempty"`
+	SourceId      string                 `protobuf:"bytes,8,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,9,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_crawler_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *CreateWebhookRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetTitleQuery() string {
+	if x != nil {
+		return x.TitleQuery
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetTitleRegex() string {
+	if x != nil {
+		return x.TitleRegex
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetMinReadTime() int32 {
+	if x != nil {
+		return x.MinReadTime
+	}
+	return 0
+}
+
+func (x *CreateWebhookRequest) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+// enabled = false - подписка выключена после consecutive_failures неудачных доставок подряд,
+// причина в disabled_reason; EnableWebhook включает ее снова.
+type Webhook struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url                 string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Secret              string                 `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	Domains             []string               `protobuf:"bytes,4,rep,name=domains,proto3" json:"domains,omitempty"`
+	Language            string                 `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`
+	TitleQuery          string                 `protobuf:"bytes,6,opt,name=title_query,json=titleQuery,proto3" json:"title_query,omitempty"`
+	TitleRegex          string                 `protobuf:"bytes,7,opt,name=title_regex,json=titleRegex,proto3" json:"title_regex,omitempty"`
+	MinReadTime         int32                  `protobuf:"varint,8,opt,name=min_read_time,json=minReadTime,proto3" json:"min_read_time,omitempty"`
+	SourceId            string                 `protobuf:"bytes,9,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	EventTypes          []string               `protobuf:"bytes,10,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Enabled             bool                   `protobuf:"varint,11,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ConsecutiveFailures int32                  `protobuf:"varint,12,opt,name=consecutive_failures,json=consecutiveFailures,proto3" json:"consecutive_failures,omitempty"`
+	DisabledReason      string                 `protobuf:"bytes,13,opt,name=disabled_reason,json=disabledReason,proto3" json:"disabled_reason,omitempty"`
+	CreatedAt           string                 `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_crawler_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *Webhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *Webhook) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Webhook) GetTitleQuery() string {
+	if x != nil {
+		return x.TitleQuery
+	}
+	return ""
+}
+
+func (x *Webhook) GetTitleRegex() string {
+	if x != nil {
+		return x.TitleRegex
+	}
+	return ""
+}
+
+func (x *Webhook) GetMinReadTime() int32 {
+	if x != nil {
+		return x.MinReadTime
+	}
+	return 0
+}
+
+func (x *Webhook) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *Webhook) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Webhook) GetConsecutiveFailures() int32 {
+	if x != nil {
+		return x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *Webhook) GetDisabledReason() string {
+	if x != nil {
+		return x.DisabledReason
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_crawler_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListWebhooksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListWebhooksRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_crawler_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_crawler_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *DeleteWebhookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_crawler_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{48}
+}
+
+type EnableWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableWebhookRequest) Reset() {
+	*x = EnableWebhookRequest{}
+	mi := &file_crawler_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableWebhookRequest) ProtoMessage() {}
+
+func (x *EnableWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableWebhookRequest.ProtoReflect.Descriptor instead.
+func (*EnableWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *EnableWebhookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// state: "pending", "delivered", "failed", пусто - все.
+type ListWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_crawler_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// last_status - HTTP-статус последней попытки, 0 - ответа не было.
+type WebhookDelivery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	EventSequence int64                  `protobuf:"varint,3,opt,name=event_sequence,json=eventSequence,proto3" json:"event_sequence,omitempty"`
+	EventType     string                 `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	ArticleId     string                 `protobuf:"bytes,5,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	State         string                 `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	Attempts      int32                  `protobuf:"varint,7,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt string                 `protobuf:"bytes,8,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastStatus    int32                  `protobuf:"varint,9,opt,name=last_status,json=lastStatus,proto3" json:"last_status,omitempty"`
+	LastError     string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeliveredAt   string                 `protobuf:"bytes,12,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_crawler_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *WebhookDelivery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventSequence() int64 {
+	if x != nil {
+		return x.EventSequence
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetArticleId() string {
+	if x != nil {
+		return x.ArticleId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() string {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetLastStatus() int32 {
+	if x != nil {
+		return x.LastStatus
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetDeliveredAt() string {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_crawler_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+var File_crawler_proto protoreflect.FileDescriptor
+
+const file_crawler_proto_rawDesc = "" +
+	"\n" +
+	"\rcrawler.proto\x12\x05proto\"\x92\x01\n" +
+	"\fCrawlOptions\x12\x1b\n" +
+	"\tmax_depth\x18\x01 \x01(\x05R\bmaxDepth\x12\x1b\n" +
+	"\tmax_pages\x18\x02 \x01(\x05R\bmaxPages\x12\x14\n" +
+	"\x05scope\x18\x03 \x01(\tR\x05scope\x12\x18\n" +
+	"\ainclude\x18\x04 \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\x05 \x03(\tR\aexclude\"O\n" +
+	"\x10SubmitUrlRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12)\n" +
+	"\x05crawl\x18\x02 \x01(\v2\x13.proto.CrawlOptionsR\x05crawl\"X\n" +
+	"\x11SubmitUrlResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
+	"\bcrawl_id\x18\x03 \x01(\tR\acrawlId\"#\n" +
+	"\x11GetArticleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
+	"\x13ListArticlesRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"\xa6\x04\n" +
+	"\aArticle\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x12\x18\n" +
+	"\asummary\x18\x05 \x01(\tR\asummary\x12!\n" +
+	"\fcontent_hash\x18\x06 \x01(\tR\vcontentHash\x12\x1a\n" +
+	"\blanguage\x18\a \x01(\tR\blanguage\x12*\n" +
+	"\x11read_time_minutes\x18\b \x01(\x05R\x0freadTimeMinutes\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\x12\x16\n" +
+	"\x06author\x18\n" +
+	" \x01(\tR\x06author\x12!\n" +
+	"\fpublished_at\x18\v \x01(\tR\vpublishedAt\x12\x1f\n" +
+	"\vmodified_at\x18\f \x01(\tR\n" +
+	"modifiedAt\x12#\n" +
+	"\rcanonical_url\x18\r \x01(\tR\fcanonicalUrl\x12\x1b\n" +
+	"\tsite_name\x18\x0e \x01(\tR\bsiteName\x12\x1b\n" +
+	"\timage_url\x18\x0f \x01(\tR\bimageUrl\x12\x18\n" +
+	"\asection\x18\x10 \x01(\tR\asection\x12\x1a\n" +
+	"\bkeywords\x18\x11 \x03(\tR\bkeywords\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x12 \x01(\tR\tclusterId\x12\x1b\n" +
+	"\tsource_id\x18\x13 \x01(\tR\bsourceId\"5\n" +
+	"\x14GetDuplicatesRequest\x12\x1d\n" +
+	"\n" +
+	"article_id\x18\x01 \x01(\tR\tarticleId\"X\n" +
+	"\x10DuplicateArticle\x12(\n" +
+	"\aarticle\x18\x01 \x01(\v2\x0e.proto.ArticleR\aarticle\x12\x1a\n" +
+	"\bdistance\x18\x02 \x01(\x05R\bdistance\"o\n" +
+	"\x15GetDuplicatesResponse\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x127\n" +
+	"\n" +
+	"duplicates\x18\x02 \x03(\v2\x17.proto.DuplicateArticleR\n" +
+	"duplicates\"B\n" +
+	"\x14ListArticlesResponse\x12*\n" +
+	"\barticles\x18\x01 \x03(\v2\x0e.proto.ArticleR\barticles\"\xb5\x01\n" +
+	"\x15SearchArticlesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x18\n" +
+	"\adomains\x18\x03 \x03(\tR\adomains\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x05 \x01(\tR\x02to\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\a \x01(\x05R\x06offset\"c\n" +
+	"\tSearchHit\x12(\n" +
+	"\aarticle\x18\x01 \x01(\v2\x0e.proto.ArticleR\aarticle\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x01R\x04rank\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\">\n" +
+	"\x16SearchArticlesResponse\x12$\n" +
+	"\x04hits\x18\x01 \x03(\v2\x10.proto.SearchHitR\x04hits\"\x92\x02\n" +
+	"\x18StreamNewArticlesRequest\x12\x18\n" +
+	"\adomains\x18\x01 \x03(\tR\adomains\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x1f\n" +
+	"\vtitle_query\x18\x03 \x01(\tR\n" +
+	"titleQuery\x12\x1f\n" +
+	"\vtitle_regex\x18\x04 \x01(\tR\n" +
+	"titleRegex\x12\"\n" +
+	"\rmin_read_time\x18\x05 \x01(\x05R\vminReadTime\x12\x1b\n" +
+	"\tsource_id\x18\x06 \x01(\tR\bsourceId\x12\x16\n" +
+	"\x06fields\x18\a \x03(\tR\x06fields\x12%\n" +
+	"\x0esince_sequence\x18\b \x01(\x03R\rsinceSequence\"h\n" +
+	"\fArticleEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12(\n" +
+	"\aarticle\x18\x02 \x01(\v2\x0e.proto.ArticleR\aarticle\x12\x1a\n" +
+	"\bsequence\x18\x03 \x01(\x03R\bsequence\"<\n" +
+	"\x1bListArticleRevisionsRequest\x12\x1d\n" +
+	"\n" +
+	"article_id\x18\x01 \x01(\tR\tarticleId\"\xd4\x01\n" +
+	"\x0fArticleRevision\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"article_id\x18\x02 \x01(\tR\tarticleId\x12!\n" +
+	"\fcontent_hash\x18\x03 \x01(\tR\vcontentHash\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x18\n" +
+	"\asummary\x18\x05 \x01(\tR\asummary\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_seen_at\x18\a \x01(\tR\n" +
+	"lastSeenAt\"T\n" +
+	"\x1cListArticleRevisionsResponse\x124\n" +
+	"\trevisions\x18\x01 \x03(\v2\x16.proto.ArticleRevisionR\trevisions\"\x8f\x01\n" +
+	"\x1bDiffArticleRevisionsRequest\x12(\n" +
+	"\x10from_revision_id\x18\x01 \x01(\tR\x0efromRevisionId\x12$\n" +
+	"\x0eto_revision_id\x18\x02 \x01(\tR\ftoRevisionId\x12 \n" +
+	"\vgranularity\x18\x03 \x01(\tR\vgranularity\"0\n" +
+	"\x06DiffOp\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"\xfe\x01\n" +
+	"\x1cDiffArticleRevisionsResponse\x12*\n" +
+	"\x04from\x18\x01 \x01(\v2\x16.proto.ArticleRevisionR\x04from\x12&\n" +
+	"\x02to\x18\x02 \x01(\v2\x16.proto.ArticleRevisionR\x02to\x12*\n" +
+	"\ttitle_ops\x18\x03 \x03(\v2\r.proto.DiffOpR\btitleOps\x12(\n" +
+	"\bbody_ops\x18\x04 \x03(\v2\r.proto.DiffOpR\abodyOps\x12\x1a\n" +
+	"\binserted\x18\x05 \x01(\x05R\binserted\x12\x18\n" +
+	"\adeleted\x18\x06 \x01(\x05R\adeleted\"1\n" +
+	"\x17ListDomainLimitsRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\"\xad\x02\n" +
+	"\vDomainLimit\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x18\n" +
+	"\apattern\x18\x02 \x01(\tR\apattern\x12%\n" +
+	"\x0econfigured_rps\x18\x03 \x01(\x01R\rconfiguredRps\x12#\n" +
+	"\reffective_rps\x18\x04 \x01(\x01R\feffectiveRps\x12\x14\n" +
+	"\x05burst\x18\x05 \x01(\x05R\x05burst\x12\x16\n" +
+	"\x06factor\x18\x06 \x01(\x01R\x06factor\x12.\n" +
+	"\x13crawl_delay_seconds\x18\a \x01(\x01R\x11crawlDelaySeconds\x12#\n" +
+	"\rblocked_until\x18\b \x01(\tR\fblockedUntil\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\t \x01(\x03R\tlatencyMs\"\xa7\x02\n" +
+	"\x18ListDomainLimitsResponse\x12*\n" +
+	"\x06limits\x18\x01 \x03(\v2\x12.proto.DomainLimitR\x06limits\x12'\n" +
+	"\x0ftracked_domains\x18\x02 \x01(\x03R\x0etrackedDomains\x12\x1f\n" +
+	"\vmax_domains\x18\x03 \x01(\x03R\n" +
+	"maxDomains\x12%\n" +
+	"\x0eidle_evictions\x18\x04 \x01(\x03R\ridleEvictions\x12-\n" +
+	"\x12capacity_evictions\x18\x05 \x01(\x03R\x11capacityEvictions\x12\x18\n" +
+	"\abackend\x18\x06 \x01(\tR\abackend\x12%\n" +
+	"\x0ebackend_errors\x18\a \x01(\x03R\rbackendErrors\"%\n" +
+	"\x13GetJobStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"m\n" +
+	"\bJobStage\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"\xc9\x02\n" +
+	"\tJobStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"article_id\x18\x05 \x01(\tR\tarticleId\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\x12'\n" +
+	"\x06stages\x18\t \x03(\v2\x0f.proto.JobStageR\x06stages\x12\x19\n" +
+	"\bcrawl_id\x18\n" +
+	" \x01(\tR\acrawlId\x12\x14\n" +
+	"\x05depth\x18\v \x01(\x05R\x05depth\x12\x1b\n" +
+	"\tsource_id\x18\f \x01(\tR\bsourceId\"U\n" +
+	"\x0fListJobsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\"8\n" +
+	"\x10ListJobsResponse\x12$\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x10.proto.JobStatusR\x04jobs\"!\n" +
+	"\x0fGetCrawlRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"$\n" +
+	"\x12CancelCrawlRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"W\n" +
+	"\x11ListCrawlsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\"\x90\x03\n" +
+	"\x05Crawl\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bseed_url\x18\x02 \x01(\tR\aseedUrl\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x1b\n" +
+	"\tmax_depth\x18\x04 \x01(\x05R\bmaxDepth\x12\x1b\n" +
+	"\tmax_pages\x18\x05 \x01(\x05R\bmaxPages\x12\x14\n" +
+	"\x05scope\x18\x06 \x01(\tR\x05scope\x12\x18\n" +
+	"\ainclude\x18\a \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\b \x03(\tR\aexclude\x12!\n" +
+	"\fpages_queued\x18\t \x01(\x05R\vpagesQueued\x12\x1d\n" +
+	"\n" +
+	"pages_done\x18\n" +
+	" \x01(\x05R\tpagesDone\x12!\n" +
+	"\fpages_failed\x18\v \x01(\x05R\vpagesFailed\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\f \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x0e \x01(\tR\n" +
+	"finishedAt\":\n" +
+	"\x12ListCrawlsResponse\x12$\n" +
+	"\x06crawls\x18\x01 \x03(\v2\f.proto.CrawlR\x06crawls\"O\n" +
+	"\x10AddSourceRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\"\xa3\x02\n" +
+	"\x06Source\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12)\n" +
+	"\x10interval_seconds\x18\x05 \x01(\x05R\x0fintervalSeconds\x12$\n" +
+	"\x0elast_polled_at\x18\x06 \x01(\tR\flastPolledAt\x12 \n" +
+	"\fnext_poll_at\x18\a \x01(\tR\n" +
+	"nextPollAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tR\tlastError\x12\x18\n" +
+	"\aentries\x18\t \x01(\x05R\aentries\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\"B\n" +
+	"\x12ListSourcesRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\">\n" +
+	"\x13ListSourcesResponse\x12'\n" +
+	"\asources\x18\x01 \x03(\v2\r.proto.SourceR\asources\"%\n" +
+	"\x13RemoveSourceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x16\n" +
+	"\x14RemoveSourceResponse\"\xaf\x01\n" +
+	"\x14SubmitSitemapRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x12\n" +
+	"\x04site\x18\x02 \x01(\tR\x04site\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x18\n" +
+	"\ainclude\x18\x05 \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\x06 \x03(\tR\aexclude\x12\x19\n" +
+	"\bmax_urls\x18\a \x01(\x05R\amaxUrls\")\n" +
+	"\x17GetSitemapImportRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8a\x03\n" +
+	"\rSitemapImport\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x1a\n" +
+	"\bsitemaps\x18\x03 \x03(\tR\bsitemaps\x12)\n" +
+	"\x10sitemaps_fetched\x18\x04 \x01(\x05R\x0fsitemapsFetched\x12'\n" +
+	"\x0fsitemaps_failed\x18\x05 \x01(\x05R\x0esitemapsFailed\x12\x1d\n" +
+	"\n" +
+	"urls_found\x18\x06 \x01(\x05R\turlsFound\x12!\n" +
+	"\furls_matched\x18\a \x01(\x05R\vurlsMatched\x12#\n" +
+	"\rurls_enqueued\x18\b \x01(\x05R\furlsEnqueued\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\t \x01(\tR\tlastError\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\vfinished_at\x18\f \x01(\tR\n" +
+	"finishedAt\"\x9a\x02\n" +
+	"\x14CreateWebhookRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\x12\x18\n" +
+	"\adomains\x18\x03 \x03(\tR\adomains\x12\x1a\n" +
+	"\blanguage\x18\x04 \x01(\tR\blanguage\x12\x1f\n" +
+	"\vtitle_query\x18\x05 \x01(\tR\n" +
+	"titleQuery\x12\x1f\n" +
+	"\vtitle_regex\x18\x06 \x01(\tR\n" +
+	"titleRegex\x12\"\n" +
+	"\rmin_read_time\x18\a \x01(\x05R\vminReadTime\x12\x1b\n" +
+	"\tsource_id\x18\b \x01(\tR\bsourceId\x12\x1f\n" +
+	"\vevent_types\x18\t \x03(\tR\n" +
+	"eventTypes\"\xb2\x03\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x03 \x01(\tR\x06secret\x12\x18\n" +
+	"\adomains\x18\x04 \x03(\tR\adomains\x12\x1a\n" +
+	"\blanguage\x18\x05 \x01(\tR\blanguage\x12\x1f\n" +
+	"\vtitle_query\x18\x06 \x01(\tR\n" +
+	"titleQuery\x12\x1f\n" +
+	"\vtitle_regex\x18\a \x01(\tR\n" +
+	"titleRegex\x12\"\n" +
+	"\rmin_read_time\x18\b \x01(\x05R\vminReadTime\x12\x1b\n" +
+	"\tsource_id\x18\t \x01(\tR\bsourceId\x12\x1f\n" +
+	"\vevent_types\x18\n" +
+	" \x03(\tR\n" +
+	"eventTypes\x12\x18\n" +
+	"\aenabled\x18\v \x01(\bR\aenabled\x121\n" +
+	"\x14consecutive_failures\x18\f \x01(\x05R\x13consecutiveFailures\x12'\n" +
+	"\x0fdisabled_reason\x18\r \x01(\tR\x0edisabledReason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\tR\tcreatedAt\"C\n" +
+	"\x13ListWebhooksRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"B\n" +
+	"\x14ListWebhooksResponse\x12*\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x0e.proto.WebhookR\bwebhooks\"&\n" +
+	"\x14DeleteWebhookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x17\n" +
+	"\x15DeleteWebhookResponse\"&\n" +
+	"\x14EnableWebhookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x81\x01\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\x81\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12%\n" +
+	"\x0eevent_sequence\x18\x03 \x01(\x03R\reventSequence\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tR\teventType\x12\x1d\n" +
+	"\n" +
+	"article_id\x18\x05 \x01(\tR\tarticleId\x12\x14\n" +
+	"\x05state\x18\x06 \x01(\tR\x05state\x12\x1a\n" +
+	"\battempts\x18\a \x01(\x05R\battempts\x12&\n" +
+	"\x0fnext_attempt_at\x18\b \x01(\tR\rnextAttemptAt\x12\x1f\n" +
+	"\vlast_status\x18\t \x01(\x05R\n" +
+	"lastStatus\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\v \x01(\tR\tcreatedAt\x12!\n" +
+	"\fdelivered_at\x18\f \x01(\tR\vdeliveredAt\"W\n" +
+	"\x1dListWebhookDeliveriesResponse\x126\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x16.proto.WebhookDeliveryR\n" +
+	"deliveries2\x82\x0e\n" +
+	"\aCrawler\x12>\n" +
+	"\tSubmitUrl\x12\x17.proto.SubmitUrlRequest\x1a\x18.proto.SubmitUrlResponse\x126\n" +
+	"\n" +
+	"GetArticle\x12\x18.proto.GetArticleRequest\x1a\x0e.proto.Article\x12G\n" +
+	"\fListArticles\x12\x1a.proto.ListArticlesRequest\x1a\x1b.proto.ListArticlesResponse\x12M\n" +
+	"\x0eSearchArticles\x12\x1c.proto.SearchArticlesRequest\x1a\x1d.proto.SearchArticlesResponse\x12J\n" +
+	"\rGetDuplicates\x12\x1b.proto.GetDuplicatesRequest\x1a\x1c.proto.GetDuplicatesResponse\x12F\n" +
+	"\x11StreamNewArticles\x12\x1f.proto.StreamNewArticlesRequest\x1a\x0e.proto.Article0\x01\x12M\n" +
+	"\x13StreamArticleEvents\x12\x1f.proto.StreamNewArticlesRequest\x1a\x13.proto.ArticleEvent0\x01\x12<\n" +
+	"\fGetJobStatus\x12\x1a.proto.GetJobStatusRequest\x1a\x10.proto.JobStatus\x12;\n" +
+	"\bListJobs\x12\x16.proto.ListJobsRequest\x1a\x17.proto.ListJobsResponse\x120\n" +
+	"\bGetCrawl\x12\x16.proto.GetCrawlRequest\x1a\f.proto.Crawl\x12A\n" +
+	"\n" +
+	"ListCrawls\x12\x18.proto.ListCrawlsRequest\x1a\x19.proto.ListCrawlsResponse\x126\n" +
+	"\vCancelCrawl\x12\x19.proto.CancelCrawlRequest\x1a\f.proto.Crawl\x123\n" +
+	"\tAddSource\x12\x17.proto.AddSourceRequest\x1a\r.proto.Source\x12D\n" +
+	"\vListSources\x12\x19.proto.ListSourcesRequest\x1a\x1a.proto.ListSourcesResponse\x12G\n" +
+	"\fRemoveSource\x12\x1a.proto.RemoveSourceRequest\x1a\x1b.proto.RemoveSourceResponse\x12B\n" +
+	"\rSubmitSitemap\x12\x1b.proto.SubmitSitemapRequest\x1a\x14.proto.SitemapImport\x12H\n" +
+	"\x10GetSitemapImport\x12\x1e.proto.GetSitemapImportRequest\x1a\x14.proto.SitemapImport\x12<\n" +
+	"\rCreateWebhook\x12\x1b.proto.CreateWebhookRequest\x1a\x0e.proto.Webhook\x12G\n" +
+	"\fListWebhooks\x12\x1a.proto.ListWebhooksRequest\x1a\x1b.proto.ListWebhooksResponse\x12J\n" +
+	"\rDeleteWebhook\x12\x1b.proto.DeleteWebhookRequest\x1a\x1c.proto.DeleteWebhookResponse\x12<\n" +
+	"\rEnableWebhook\x12\x1b.proto.EnableWebhookRequest\x1a\x0e.proto.Webhook\x12b\n" +
+	"\x15ListWebhookDeliveries\x12#.proto.ListWebhookDeliveriesRequest\x1a$.proto.ListWebhookDeliveriesResponse\x12S\n" +
+	"\x10ListDomainLimits\x12\x1e.proto.ListDomainLimitsRequest\x1a\x1f.proto.ListDomainLimitsResponse\x12_\n" +
+	"\x14ListArticleRevisions\x12\".proto.ListArticleRevisionsRequest\x1a#.proto.ListArticleRevisionsResponse\x12_\n" +
+	"\x14DiffArticleRevisions\x12\".proto.DiffArticleRevisionsRequest\x1a#.proto.DiffArticleRevisionsResponseB7Z5github.com/kiyotaka137/articlecrawler/pkg/proto;protob\x06proto3"
+
+var (
+	file_crawler_proto_rawDescOnce sync.Once
+	file_crawler_proto_rawDescData []byte
+)
+
+func file_crawler_proto_rawDescGZIP() []byte {
+	file_crawler_proto_rawDescOnce.Do(func() {
+		file_crawler_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_crawler_proto_rawDesc), len(file_crawler_proto_rawDesc)))
 	})
-	return file_pkg_proto_crawler_proto_rawDescData
-}
-
-var file_pkg_proto_crawler_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_pkg_proto_crawler_proto_goTypes = []any{
-	(*SubmitUrlRequest)(nil),         // 0: proto.SubmitUrlRequest
-	(*SubmitUrlResponse)(nil),        // 1: proto.SubmitUrlResponse
-	(*GetArticleRequest)(nil),        // 2: proto.GetArticleRequest
-	(*ListArticlesRequest)(nil),      // 3: proto.ListArticlesRequest
-	(*Article)(nil),                  // 4: proto.Article
-	(*ListArticlesResponse)(nil),     // 5: proto.ListArticlesResponse
-	(*StreamNewArticlesRequest)(nil), // 6: proto.StreamNewArticlesRequest
-}
-var file_pkg_proto_crawler_proto_depIdxs = []int32{
-	4, // 0: proto.ListArticlesResponse.articles:type_name -> proto.Article
-	0, // 1: proto.Crawler.SubmitUrl:input_type -> proto.SubmitUrlRequest
-	2, // 2: proto.Crawler.GetArticle:input_type -> proto.GetArticleRequest
-	3, // 3: proto.Crawler.ListArticles:input_type -> proto.ListArticlesRequest
-	6, // 4: proto.Crawler.StreamNewArticles:input_type -> proto.StreamNewArticlesRequest
-	1, // 5: proto.Crawler.SubmitUrl:output_type -> proto.SubmitUrlResponse
-	4, // 6: proto.Crawler.GetArticle:output_type -> proto.Article
-	5, // 7: proto.Crawler.ListArticles:output_type -> proto.ListArticlesResponse
-	4, // 8: proto.Crawler.StreamNewArticles:output_type -> proto.Article
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
-}
-
-func init() { file_pkg_proto_crawler_proto_init() }
-func file_pkg_proto_crawler_proto_init() {
-	if File_pkg_proto_crawler_proto != nil {
+	return file_crawler_proto_rawDescData
+}
+
+var file_crawler_proto_msgTypes = make([]protoimpl.MessageInfo, 53)
+var file_crawler_proto_goTypes = []any{
+	(*CrawlOptions)(nil),                  // 0: proto.CrawlOptions
+	(*SubmitUrlRequest)(nil),              // 1: proto.SubmitUrlRequest
+	(*SubmitUrlResponse)(nil),             // 2: proto.SubmitUrlResponse
+	(*GetArticleRequest)(nil),             // 3: proto.GetArticleRequest
+	(*ListArticlesRequest)(nil),           // 4: proto.ListArticlesRequest
+	(*Article)(nil),                       // 5: proto.Article
+	(*GetDuplicatesRequest)(nil),          // 6: proto.GetDuplicatesRequest
+	(*DuplicateArticle)(nil),              // 7: proto.DuplicateArticle
+	(*GetDuplicatesResponse)(nil),         // 8: proto.GetDuplicatesResponse
+	(*ListArticlesResponse)(nil),          // 9: proto.ListArticlesResponse
+	(*SearchArticlesRequest)(nil),         // 10: proto.SearchArticlesRequest
+	(*SearchHit)(nil),                     // 11: proto.SearchHit
+	(*SearchArticlesResponse)(nil),        // 12: proto.SearchArticlesResponse
+	(*StreamNewArticlesRequest)(nil),      // 13: proto.StreamNewArticlesRequest
+	(*ArticleEvent)(nil),                  // 14: proto.ArticleEvent
+	(*ListArticleRevisionsRequest)(nil),   // 15: proto.ListArticleRevisionsRequest
+	(*ArticleRevision)(nil),               // 16: proto.ArticleRevision
+	(*ListArticleRevisionsResponse)(nil),  // 17: proto.ListArticleRevisionsResponse
+	(*DiffArticleRevisionsRequest)(nil),   // 18: proto.DiffArticleRevisionsRequest
+	(*DiffOp)(nil),                        // 19: proto.DiffOp
+	(*DiffArticleRevisionsResponse)(nil),  // 20: proto.DiffArticleRevisionsResponse
+	(*ListDomainLimitsRequest)(nil),       // 21: proto.ListDomainLimitsRequest
+	(*DomainLimit)(nil),                   // 22: proto.DomainLimit
+	(*ListDomainLimitsResponse)(nil),      // 23: proto.ListDomainLimitsResponse
+	(*GetJobStatusRequest)(nil),           // 24: proto.GetJobStatusRequest
+	(*JobStage)(nil),                      // 25: proto.JobStage
+	(*JobStatus)(nil),                     // 26: proto.JobStatus
+	(*ListJobsRequest)(nil),               // 27: proto.ListJobsRequest
+	(*ListJobsResponse)(nil),              // 28: proto.ListJobsResponse
+	(*GetCrawlRequest)(nil),               // 29: proto.GetCrawlRequest
+	(*CancelCrawlRequest)(nil),            // 30: proto.CancelCrawlRequest
+	(*ListCrawlsRequest)(nil),             // 31: proto.ListCrawlsRequest
+	(*Crawl)(nil),                         // 32: proto.Crawl
+	(*ListCrawlsResponse)(nil),            // 33: proto.ListCrawlsResponse
+	(*AddSourceRequest)(nil),              // 34: proto.AddSourceRequest
+	(*Source)(nil),                        // 35: proto.Source
+	(*ListSourcesRequest)(nil),            // 36: proto.ListSourcesRequest
+	(*ListSourcesResponse)(nil),           // 37: proto.ListSourcesResponse
+	(*RemoveSourceRequest)(nil),           // 38: proto.RemoveSourceRequest
+	(*RemoveSourceResponse)(nil),          // 39: proto.RemoveSourceResponse
+	(*SubmitSitemapRequest)(nil),          // 40: proto.SubmitSitemapRequest
+	(*GetSitemapImportRequest)(nil),       // 41: proto.GetSitemapImportRequest
+	(*SitemapImport)(nil),                 // 42: proto.SitemapImport
+	(*CreateWebhookRequest)(nil),          // 43: proto.CreateWebhookRequest
+	(*Webhook)(nil),                       // 44: proto.Webhook
+	(*ListWebhooksRequest)(nil),           // 45: proto.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),          // 46: proto.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),          // 47: proto.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),         // 48: proto.DeleteWebhookResponse
+	(*EnableWebhookRequest)(nil),          // 49: proto.EnableWebhookRequest
+	(*ListWebhookDeliveriesRequest)(nil),  // 50: proto.ListWebhookDeliveriesRequest
+	(*WebhookDelivery)(nil),               // 51: proto.WebhookDelivery
+	(*ListWebhookDeliveriesResponse)(nil), // 52: proto.ListWebhookDeliveriesResponse
+}
+var file_crawler_proto_depIdxs = []int32{
+	0,  // 0: proto.SubmitUrlRequest.crawl:type_name -> proto.CrawlOptions
+	5,  // 1: proto.DuplicateArticle.article:type_name -> proto.Article
+	7,  // 2: proto.GetDuplicatesResponse.duplicates:type_name -> proto.DuplicateArticle
+	5,  // 3: proto.ListArticlesResponse.articles:type_name -> proto.Article
+	5,  // 4: proto.SearchHit.article:type_name -> proto.Article
+	11, // 5: proto.SearchArticlesResponse.hits:type_name -> proto.SearchHit
+	5,  // 6: proto.ArticleEvent.article:type_name -> proto.Article
+	16, // 7: proto.ListArticleRevisionsResponse.revisions:type_name -> proto.ArticleRevision
+	16, // 8: proto.DiffArticleRevisionsResponse.from:type_name -> proto.ArticleRevision
+	16, // 9: proto.DiffArticleRevisionsResponse.to:type_name -> proto.ArticleRevision
+	19, // 10: proto.DiffArticleRevisionsResponse.title_ops:type_name -> proto.DiffOp
+	19, // 11: proto.DiffArticleRevisionsResponse.body_ops:type_name -> proto.DiffOp
+	22, // 12: proto.ListDomainLimitsResponse.limits:type_name -> proto.DomainLimit
+	25, // 13: proto.JobStatus.stages:type_name -> proto.JobStage
+	26, // 14: proto.ListJobsResponse.jobs:type_name -> proto.JobStatus
+	32, // 15: proto.ListCrawlsResponse.crawls:type_name -> proto.Crawl
+	35, // 16: proto.ListSourcesResponse.sources:type_name -> proto.Source
+	44, // 17: proto.ListWebhooksResponse.webhooks:type_name -> proto.Webhook
+	51, // 18: proto.ListWebhookDeliveriesResponse.deliveries:type_name -> proto.WebhookDelivery
+	1,  // 19: proto.Crawler.SubmitUrl:input_type -> proto.SubmitUrlRequest
+	3,  // 20: proto.Crawler.GetArticle:input_type -> proto.GetArticleRequest
+	4,  // 21: proto.Crawler.ListArticles:input_type -> proto.ListArticlesRequest
+	10, // 22: proto.Crawler.SearchArticles:input_type -> proto.SearchArticlesRequest
+	6,  // 23: proto.Crawler.GetDuplicates:input_type -> proto.GetDuplicatesRequest
+	13, // 24: proto.Crawler.StreamNewArticles:input_type -> proto.StreamNewArticlesRequest
+	13, // 25: proto.Crawler.StreamArticleEvents:input_type -> proto.StreamNewArticlesRequest
+	24, // 26: proto.Crawler.GetJobStatus:input_type -> proto.GetJobStatusRequest
+	27, // 27: proto.Crawler.ListJobs:input_type -> proto.ListJobsRequest
+	29, // 28: proto.Crawler.GetCrawl:input_type -> proto.GetCrawlRequest
+	31, // 29: proto.Crawler.ListCrawls:input_type -> proto.ListCrawlsRequest
+	30, // 30: proto.Crawler.CancelCrawl:input_type -> proto.CancelCrawlRequest
+	34, // 31: proto.Crawler.AddSource:input_type -> proto.AddSourceRequest
+	36, // 32: proto.Crawler.ListSources:input_type -> proto.ListSourcesRequest
+	38, // 33: proto.Crawler.RemoveSource:input_type -> proto.RemoveSourceRequest
+	40, // 34: proto.Crawler.SubmitSitemap:input_type -> proto.SubmitSitemapRequest
+	41, // 35: proto.Crawler.GetSitemapImport:input_type -> proto.GetSitemapImportRequest
+	43, // 36: proto.Crawler.CreateWebhook:input_type -> proto.CreateWebhookRequest
+	45, // 37: proto.Crawler.ListWebhooks:input_type -> proto.ListWebhooksRequest
+	47, // 38: proto.Crawler.DeleteWebhook:input_type -> proto.DeleteWebhookRequest
+	49, // 39: proto.Crawler.EnableWebhook:input_type -> proto.EnableWebhookRequest
+	50, // 40: proto.Crawler.ListWebhookDeliveries:input_type -> proto.ListWebhookDeliveriesRequest
+	21, // 41: proto.Crawler.ListDomainLimits:input_type -> proto.ListDomainLimitsRequest
+	15, // 42: proto.Crawler.ListArticleRevisions:input_type -> proto.ListArticleRevisionsRequest
+	18, // 43: proto.Crawler.DiffArticleRevisions:input_type -> proto.DiffArticleRevisionsRequest
+	2,  // 44: proto.Crawler.SubmitUrl:output_type -> proto.SubmitUrlResponse
+	5,  // 45: proto.Crawler.GetArticle:output_type -> proto.Article
+	9,  // 46: proto.Crawler.ListArticles:output_type -> proto.ListArticlesResponse
+	12, // 47: proto.Crawler.SearchArticles:output_type -> proto.SearchArticlesResponse
+	8,  // 48: proto.Crawler.GetDuplicates:output_type -> proto.GetDuplicatesResponse
+	5,  // 49: proto.Crawler.StreamNewArticles:output_type -> proto.Article
+	14, // 50: proto.Crawler.StreamArticleEvents:output_type -> proto.ArticleEvent
+	26, // 51: proto.Crawler.GetJobStatus:output_type -> proto.JobStatus
+	28, // 52: proto.Crawler.ListJobs:output_type -> proto.ListJobsResponse
+	32, // 53: proto.Crawler.GetCrawl:output_type -> proto.Crawl
+	33, // 54: proto.Crawler.ListCrawls:output_type -> proto.ListCrawlsResponse
+	32, // 55: proto.Crawler.CancelCrawl:output_type -> proto.Crawl
+	35, // 56: proto.Crawler.AddSource:output_type -> proto.Source
+	37, // 57: proto.Crawler.ListSources:output_type -> proto.ListSourcesResponse
+	39, // 58: proto.Crawler.RemoveSource:output_type -> proto.RemoveSourceResponse
+	42, // 59: proto.Crawler.SubmitSitemap:output_type -> proto.SitemapImport
+	42, // 60: proto.Crawler.GetSitemapImport:output_type -> proto.SitemapImport
+	44, // 61: proto.Crawler.CreateWebhook:output_type -> proto.Webhook
+	46, // 62: proto.Crawler.ListWebhooks:output_type -> proto.ListWebhooksResponse
+	48, // 63: proto.Crawler.DeleteWebhook:output_type -> proto.DeleteWebhookResponse
+	44, // 64: proto.Crawler.EnableWebhook:output_type -> proto.Webhook
+	52, // 65: proto.Crawler.ListWebhookDeliveries:output_type -> proto.ListWebhookDeliveriesResponse
+	23, // 66: proto.Crawler.ListDomainLimits:output_type -> proto.ListDomainLimitsResponse
+	17, // 67: proto.Crawler.ListArticleRevisions:output_type -> proto.ListArticleRevisionsResponse
+	20, // 68: proto.Crawler.DiffArticleRevisions:output_type -> proto.DiffArticleRevisionsResponse
+	44, // [44:69] is the sub-list for method output_type
+	19, // [19:44] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_crawler_proto_init() }
+func file_crawler_proto_init() {
+	if File_crawler_proto != nil {
 		return
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_crawler_proto_rawDesc), len(file_pkg_proto_crawler_proto_rawDesc)),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_crawler_proto_rawDesc), len(file_crawler_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   53,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
-		GoTypes:           file_pkg_proto_crawler_proto_goTypes,
-		DependencyIndexes: file_pkg_proto_crawler_proto_depIdxs,
-		MessageInfos:      file_pkg_proto_crawler_proto_msgTypes,
+		GoTypes:           file_crawler_proto_goTypes,
+		DependencyIndexes: file_crawler_proto_depIdxs,
+		MessageInfos:      file_crawler_proto_msgTypes,
 	}.Build()
-	File_pkg_proto_crawler_proto = out.File
-	file_pkg_proto_crawler_proto_goTypes = nil
-	file_pkg_proto_crawler_proto_depIdxs = nil
+	File_crawler_proto = out.File
+	file_crawler_proto_goTypes = nil
+	file_crawler_proto_depIdxs = nil
 }
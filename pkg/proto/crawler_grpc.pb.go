@@ -2,7 +2,7 @@
 // versions:
 // - protoc-gen-go-grpc v1.5.1
 // - protoc             v3.21.12
-// source: pkg/proto/crawler.proto
+// source: crawler.proto
 
 package proto
 
@@ -19,10 +19,31 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Crawler_SubmitUrl_FullMethodName         = "/proto.Crawler/SubmitUrl"
-	Crawler_GetArticle_FullMethodName        = "/proto.Crawler/GetArticle"
-	Crawler_ListArticles_FullMethodName      = "/proto.Crawler/ListArticles"
-	Crawler_StreamNewArticles_FullMethodName = "/proto.Crawler/StreamNewArticles"
+	Crawler_SubmitUrl_FullMethodName             = "/proto.Crawler/SubmitUrl"
+	Crawler_GetArticle_FullMethodName            = "/proto.Crawler/GetArticle"
+	Crawler_ListArticles_FullMethodName          = "/proto.Crawler/ListArticles"
+	Crawler_SearchArticles_FullMethodName        = "/proto.Crawler/SearchArticles"
+	Crawler_GetDuplicates_FullMethodName         = "/proto.Crawler/GetDuplicates"
+	Crawler_StreamNewArticles_FullMethodName     = "/proto.Crawler/StreamNewArticles"
+	Crawler_StreamArticleEvents_FullMethodName   = "/proto.Crawler/StreamArticleEvents"
+	Crawler_GetJobStatus_FullMethodName          = "/proto.Crawler/GetJobStatus"
+	Crawler_ListJobs_FullMethodName              = "/proto.Crawler/ListJobs"
+	Crawler_GetCrawl_FullMethodName              = "/proto.Crawler/GetCrawl"
+	Crawler_ListCrawls_FullMethodName            = "/proto.Crawler/ListCrawls"
+	Crawler_CancelCrawl_FullMethodName           = "/proto.Crawler/CancelCrawl"
+	Crawler_AddSource_FullMethodName             = "/proto.Crawler/AddSource"
+	Crawler_ListSources_FullMethodName           = "/proto.Crawler/ListSources"
+	Crawler_RemoveSource_FullMethodName          = "/proto.Crawler/RemoveSource"
+	Crawler_SubmitSitemap_FullMethodName         = "/proto.Crawler/SubmitSitemap"
+	Crawler_GetSitemapImport_FullMethodName      = "/proto.Crawler/GetSitemapImport"
+	Crawler_CreateWebhook_FullMethodName         = "/proto.Crawler/CreateWebhook"
+	Crawler_ListWebhooks_FullMethodName          = "/proto.Crawler/ListWebhooks"
+	Crawler_DeleteWebhook_FullMethodName         = "/proto.Crawler/DeleteWebhook"
+	Crawler_EnableWebhook_FullMethodName         = "/proto.Crawler/EnableWebhook"
+	Crawler_ListWebhookDeliveries_FullMethodName = "/proto.Crawler/ListWebhookDeliveries"
+	Crawler_ListDomainLimits_FullMethodName      = "/proto.Crawler/ListDomainLimits"
+	Crawler_ListArticleRevisions_FullMethodName  = "/proto.Crawler/ListArticleRevisions"
+	Crawler_DiffArticleRevisions_FullMethodName  = "/proto.Crawler/DiffArticleRevisions"
 )
 
 // CrawlerClient is the client API for Crawler service.
@@ -32,7 +53,28 @@ type CrawlerClient interface {
 	SubmitUrl(ctx context.Context, in *SubmitUrlRequest, opts ...grpc.CallOption) (*SubmitUrlResponse, error)
 	GetArticle(ctx context.Context, in *GetArticleRequest, opts ...grpc.CallOption) (*Article, error)
 	ListArticles(ctx context.Context, in *ListArticlesRequest, opts ...grpc.CallOption) (*ListArticlesResponse, error)
+	SearchArticles(ctx context.Context, in *SearchArticlesRequest, opts ...grpc.CallOption) (*SearchArticlesResponse, error)
+	GetDuplicates(ctx context.Context, in *GetDuplicatesRequest, opts ...grpc.CallOption) (*GetDuplicatesResponse, error)
 	StreamNewArticles(ctx context.Context, in *StreamNewArticlesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Article], error)
+	StreamArticleEvents(ctx context.Context, in *StreamNewArticlesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArticleEvent], error)
+	GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	GetCrawl(ctx context.Context, in *GetCrawlRequest, opts ...grpc.CallOption) (*Crawl, error)
+	ListCrawls(ctx context.Context, in *ListCrawlsRequest, opts ...grpc.CallOption) (*ListCrawlsResponse, error)
+	CancelCrawl(ctx context.Context, in *CancelCrawlRequest, opts ...grpc.CallOption) (*Crawl, error)
+	AddSource(ctx context.Context, in *AddSourceRequest, opts ...grpc.CallOption) (*Source, error)
+	ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error)
+	RemoveSource(ctx context.Context, in *RemoveSourceRequest, opts ...grpc.CallOption) (*RemoveSourceResponse, error)
+	SubmitSitemap(ctx context.Context, in *SubmitSitemapRequest, opts ...grpc.CallOption) (*SitemapImport, error)
+	GetSitemapImport(ctx context.Context, in *GetSitemapImportRequest, opts ...grpc.CallOption) (*SitemapImport, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	EnableWebhook(ctx context.Context, in *EnableWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	ListDomainLimits(ctx context.Context, in *ListDomainLimitsRequest, opts ...grpc.CallOption) (*ListDomainLimitsResponse, error)
+	ListArticleRevisions(ctx context.Context, in *ListArticleRevisionsRequest, opts ...grpc.CallOption) (*ListArticleRevisionsResponse, error)
+	DiffArticleRevisions(ctx context.Context, in *DiffArticleRevisionsRequest, opts ...grpc.CallOption) (*DiffArticleRevisionsResponse, error)
 }
 
 type crawlerClient struct {
@@ -73,6 +115,26 @@ func (c *crawlerClient) ListArticles(ctx context.Context, in *ListArticlesReques
 	return out, nil
 }
 
+func (c *crawlerClient) SearchArticles(ctx context.Context, in *SearchArticlesRequest, opts ...grpc.CallOption) (*SearchArticlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchArticlesResponse)
+	err := c.cc.Invoke(ctx, Crawler_SearchArticles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) GetDuplicates(ctx context.Context, in *GetDuplicatesRequest, opts ...grpc.CallOption) (*GetDuplicatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDuplicatesResponse)
+	err := c.cc.Invoke(ctx, Crawler_GetDuplicates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *crawlerClient) StreamNewArticles(ctx context.Context, in *StreamNewArticlesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Article], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Crawler_ServiceDesc.Streams[0], Crawler_StreamNewArticles_FullMethodName, cOpts...)
@@ -92,6 +154,205 @@ func (c *crawlerClient) StreamNewArticles(ctx context.Context, in *StreamNewArti
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Crawler_StreamNewArticlesClient = grpc.ServerStreamingClient[Article]
 
+func (c *crawlerClient) StreamArticleEvents(ctx context.Context, in *StreamNewArticlesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArticleEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Crawler_ServiceDesc.Streams[1], Crawler_StreamArticleEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamNewArticlesRequest, ArticleEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Crawler_StreamArticleEventsClient = grpc.ServerStreamingClient[ArticleEvent]
+
+func (c *crawlerClient) GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, Crawler_GetJobStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, Crawler_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) GetCrawl(ctx context.Context, in *GetCrawlRequest, opts ...grpc.CallOption) (*Crawl, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Crawl)
+	err := c.cc.Invoke(ctx, Crawler_GetCrawl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) ListCrawls(ctx context.Context, in *ListCrawlsRequest, opts ...grpc.CallOption) (*ListCrawlsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCrawlsResponse)
+	err := c.cc.Invoke(ctx, Crawler_ListCrawls_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) CancelCrawl(ctx context.Context, in *CancelCrawlRequest, opts ...grpc.CallOption) (*Crawl, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Crawl)
+	err := c.cc.Invoke(ctx, Crawler_CancelCrawl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) AddSource(ctx context.Context, in *AddSourceRequest, opts ...grpc.CallOption) (*Source, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Source)
+	err := c.cc.Invoke(ctx, Crawler_AddSource_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSourcesResponse)
+	err := c.cc.Invoke(ctx, Crawler_ListSources_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) RemoveSource(ctx context.Context, in *RemoveSourceRequest, opts ...grpc.CallOption) (*RemoveSourceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveSourceResponse)
+	err := c.cc.Invoke(ctx, Crawler_RemoveSource_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) SubmitSitemap(ctx context.Context, in *SubmitSitemapRequest, opts ...grpc.CallOption) (*SitemapImport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SitemapImport)
+	err := c.cc.Invoke(ctx, Crawler_SubmitSitemap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) GetSitemapImport(ctx context.Context, in *GetSitemapImportRequest, opts ...grpc.CallOption) (*SitemapImport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SitemapImport)
+	err := c.cc.Invoke(ctx, Crawler_GetSitemapImport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, Crawler_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, Crawler_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, Crawler_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) EnableWebhook(ctx context.Context, in *EnableWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, Crawler_EnableWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, Crawler_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) ListDomainLimits(ctx context.Context, in *ListDomainLimitsRequest, opts ...grpc.CallOption) (*ListDomainLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDomainLimitsResponse)
+	err := c.cc.Invoke(ctx, Crawler_ListDomainLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) ListArticleRevisions(ctx context.Context, in *ListArticleRevisionsRequest, opts ...grpc.CallOption) (*ListArticleRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListArticleRevisionsResponse)
+	err := c.cc.Invoke(ctx, Crawler_ListArticleRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerClient) DiffArticleRevisions(ctx context.Context, in *DiffArticleRevisionsRequest, opts ...grpc.CallOption) (*DiffArticleRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiffArticleRevisionsResponse)
+	err := c.cc.Invoke(ctx, Crawler_DiffArticleRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CrawlerServer is the server API for Crawler service.
 // All implementations must embed UnimplementedCrawlerServer
 // for forward compatibility.
@@ -99,7 +360,28 @@ type CrawlerServer interface {
 	SubmitUrl(context.Context, *SubmitUrlRequest) (*SubmitUrlResponse, error)
 	GetArticle(context.Context, *GetArticleRequest) (*Article, error)
 	ListArticles(context.Context, *ListArticlesRequest) (*ListArticlesResponse, error)
+	SearchArticles(context.Context, *SearchArticlesRequest) (*SearchArticlesResponse, error)
+	GetDuplicates(context.Context, *GetDuplicatesRequest) (*GetDuplicatesResponse, error)
 	StreamNewArticles(*StreamNewArticlesRequest, grpc.ServerStreamingServer[Article]) error
+	StreamArticleEvents(*StreamNewArticlesRequest, grpc.ServerStreamingServer[ArticleEvent]) error
+	GetJobStatus(context.Context, *GetJobStatusRequest) (*JobStatus, error)
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	GetCrawl(context.Context, *GetCrawlRequest) (*Crawl, error)
+	ListCrawls(context.Context, *ListCrawlsRequest) (*ListCrawlsResponse, error)
+	CancelCrawl(context.Context, *CancelCrawlRequest) (*Crawl, error)
+	AddSource(context.Context, *AddSourceRequest) (*Source, error)
+	ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error)
+	RemoveSource(context.Context, *RemoveSourceRequest) (*RemoveSourceResponse, error)
+	SubmitSitemap(context.Context, *SubmitSitemapRequest) (*SitemapImport, error)
+	GetSitemapImport(context.Context, *GetSitemapImportRequest) (*SitemapImport, error)
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	EnableWebhook(context.Context, *EnableWebhookRequest) (*Webhook, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	ListDomainLimits(context.Context, *ListDomainLimitsRequest) (*ListDomainLimitsResponse, error)
+	ListArticleRevisions(context.Context, *ListArticleRevisionsRequest) (*ListArticleRevisionsResponse, error)
+	DiffArticleRevisions(context.Context, *DiffArticleRevisionsRequest) (*DiffArticleRevisionsResponse, error)
 	mustEmbedUnimplementedCrawlerServer()
 }
 
@@ -119,9 +401,72 @@ func (UnimplementedCrawlerServer) GetArticle(context.Context, *GetArticleRequest
 func (UnimplementedCrawlerServer) ListArticles(context.Context, *ListArticlesRequest) (*ListArticlesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListArticles not implemented")
 }
+func (UnimplementedCrawlerServer) SearchArticles(context.Context, *SearchArticlesRequest) (*SearchArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchArticles not implemented")
+}
+func (UnimplementedCrawlerServer) GetDuplicates(context.Context, *GetDuplicatesRequest) (*GetDuplicatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDuplicates not implemented")
+}
 func (UnimplementedCrawlerServer) StreamNewArticles(*StreamNewArticlesRequest, grpc.ServerStreamingServer[Article]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamNewArticles not implemented")
 }
+func (UnimplementedCrawlerServer) StreamArticleEvents(*StreamNewArticlesRequest, grpc.ServerStreamingServer[ArticleEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamArticleEvents not implemented")
+}
+func (UnimplementedCrawlerServer) GetJobStatus(context.Context, *GetJobStatusRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
+}
+func (UnimplementedCrawlerServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedCrawlerServer) GetCrawl(context.Context, *GetCrawlRequest) (*Crawl, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrawl not implemented")
+}
+func (UnimplementedCrawlerServer) ListCrawls(context.Context, *ListCrawlsRequest) (*ListCrawlsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCrawls not implemented")
+}
+func (UnimplementedCrawlerServer) CancelCrawl(context.Context, *CancelCrawlRequest) (*Crawl, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCrawl not implemented")
+}
+func (UnimplementedCrawlerServer) AddSource(context.Context, *AddSourceRequest) (*Source, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSource not implemented")
+}
+func (UnimplementedCrawlerServer) ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSources not implemented")
+}
+func (UnimplementedCrawlerServer) RemoveSource(context.Context, *RemoveSourceRequest) (*RemoveSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSource not implemented")
+}
+func (UnimplementedCrawlerServer) SubmitSitemap(context.Context, *SubmitSitemapRequest) (*SitemapImport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitSitemap not implemented")
+}
+func (UnimplementedCrawlerServer) GetSitemapImport(context.Context, *GetSitemapImportRequest) (*SitemapImport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSitemapImport not implemented")
+}
+func (UnimplementedCrawlerServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedCrawlerServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedCrawlerServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedCrawlerServer) EnableWebhook(context.Context, *EnableWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableWebhook not implemented")
+}
+func (UnimplementedCrawlerServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedCrawlerServer) ListDomainLimits(context.Context, *ListDomainLimitsRequest) (*ListDomainLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDomainLimits not implemented")
+}
+func (UnimplementedCrawlerServer) ListArticleRevisions(context.Context, *ListArticleRevisionsRequest) (*ListArticleRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArticleRevisions not implemented")
+}
+func (UnimplementedCrawlerServer) DiffArticleRevisions(context.Context, *DiffArticleRevisionsRequest) (*DiffArticleRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffArticleRevisions not implemented")
+}
 func (UnimplementedCrawlerServer) mustEmbedUnimplementedCrawlerServer() {}
 func (UnimplementedCrawlerServer) testEmbeddedByValue()                 {}
 
@@ -197,6 +542,42 @@ func _Crawler_ListArticles_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Crawler_SearchArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).SearchArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_SearchArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).SearchArticles(ctx, req.(*SearchArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_GetDuplicates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDuplicatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).GetDuplicates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_GetDuplicates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).GetDuplicates(ctx, req.(*GetDuplicatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Crawler_StreamNewArticles_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(StreamNewArticlesRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -208,6 +589,341 @@ func _Crawler_StreamNewArticles_Handler(srv interface{}, stream grpc.ServerStrea
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Crawler_StreamNewArticlesServer = grpc.ServerStreamingServer[Article]
 
+func _Crawler_StreamArticleEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamNewArticlesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CrawlerServer).StreamArticleEvents(m, &grpc.GenericServerStream[StreamNewArticlesRequest, ArticleEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Crawler_StreamArticleEventsServer = grpc.ServerStreamingServer[ArticleEvent]
+
+func _Crawler_GetJobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).GetJobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_GetJobStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).GetJobStatus(ctx, req.(*GetJobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_GetCrawl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCrawlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).GetCrawl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_GetCrawl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).GetCrawl(ctx, req.(*GetCrawlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_ListCrawls_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCrawlsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).ListCrawls(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_ListCrawls_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).ListCrawls(ctx, req.(*ListCrawlsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_CancelCrawl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelCrawlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).CancelCrawl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_CancelCrawl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).CancelCrawl(ctx, req.(*CancelCrawlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_AddSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).AddSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_AddSource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).AddSource(ctx, req.(*AddSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_ListSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).ListSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_ListSources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).ListSources(ctx, req.(*ListSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_RemoveSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).RemoveSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_RemoveSource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).RemoveSource(ctx, req.(*RemoveSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_SubmitSitemap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitSitemapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).SubmitSitemap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_SubmitSitemap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).SubmitSitemap(ctx, req.(*SubmitSitemapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_GetSitemapImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSitemapImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).GetSitemapImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_GetSitemapImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).GetSitemapImport(ctx, req.(*GetSitemapImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_EnableWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).EnableWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_EnableWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).EnableWebhook(ctx, req.(*EnableWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_ListDomainLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDomainLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).ListDomainLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_ListDomainLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).ListDomainLimits(ctx, req.(*ListDomainLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_ListArticleRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArticleRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).ListArticleRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_ListArticleRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).ListArticleRevisions(ctx, req.(*ListArticleRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Crawler_DiffArticleRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffArticleRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServer).DiffArticleRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Crawler_DiffArticleRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServer).DiffArticleRevisions(ctx, req.(*DiffArticleRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Crawler_ServiceDesc is the grpc.ServiceDesc for Crawler service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -227,6 +943,86 @@ var Crawler_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListArticles",
 			Handler:    _Crawler_ListArticles_Handler,
 		},
+		{
+			MethodName: "SearchArticles",
+			Handler:    _Crawler_SearchArticles_Handler,
+		},
+		{
+			MethodName: "GetDuplicates",
+			Handler:    _Crawler_GetDuplicates_Handler,
+		},
+		{
+			MethodName: "GetJobStatus",
+			Handler:    _Crawler_GetJobStatus_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _Crawler_ListJobs_Handler,
+		},
+		{
+			MethodName: "GetCrawl",
+			Handler:    _Crawler_GetCrawl_Handler,
+		},
+		{
+			MethodName: "ListCrawls",
+			Handler:    _Crawler_ListCrawls_Handler,
+		},
+		{
+			MethodName: "CancelCrawl",
+			Handler:    _Crawler_CancelCrawl_Handler,
+		},
+		{
+			MethodName: "AddSource",
+			Handler:    _Crawler_AddSource_Handler,
+		},
+		{
+			MethodName: "ListSources",
+			Handler:    _Crawler_ListSources_Handler,
+		},
+		{
+			MethodName: "RemoveSource",
+			Handler:    _Crawler_RemoveSource_Handler,
+		},
+		{
+			MethodName: "SubmitSitemap",
+			Handler:    _Crawler_SubmitSitemap_Handler,
+		},
+		{
+			MethodName: "GetSitemapImport",
+			Handler:    _Crawler_GetSitemapImport_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _Crawler_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _Crawler_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _Crawler_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "EnableWebhook",
+			Handler:    _Crawler_EnableWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _Crawler_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "ListDomainLimits",
+			Handler:    _Crawler_ListDomainLimits_Handler,
+		},
+		{
+			MethodName: "ListArticleRevisions",
+			Handler:    _Crawler_ListArticleRevisions_Handler,
+		},
+		{
+			MethodName: "DiffArticleRevisions",
+			Handler:    _Crawler_DiffArticleRevisions_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -234,6 +1030,11 @@ var Crawler_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Crawler_StreamNewArticles_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamArticleEvents",
+			Handler:       _Crawler_StreamArticleEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Metadata: "pkg/proto/crawler.proto",
+	Metadata: "crawler.proto",
 }